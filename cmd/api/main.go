@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/vitalfit/api/config"
 	"github.com/vitalfit/api/internal/app"
@@ -31,6 +32,17 @@ func main() {
 	//initialize config
 	config := config.LoadConfig()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(config, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if config.Db.MigrateOnStart {
+		migrateOnStart(config)
+	}
+
 	//db gorm connection
 	db, err := db.New(config.Db.Dsn, config.Db.MaxOpenConns, config.Db.MaxIdleConns, config.Db.MaxIdleTime)
 	if err != nil {
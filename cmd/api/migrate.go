@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"github.com/vitalfit/api/config"
+	"github.com/vitalfit/api/internal/migrate"
+	"github.com/vitalfit/api/pkg/db"
+)
+
+const migrateUsage = `usage: api migrate <command>
+
+  up [N]          apply the pending migrations, or only the next N
+  down [N|all]    revert the last N migrations (default 1)
+  version         print the current version
+  force V         set the version without running anything (-1 for none)
+  status          list every migration and whether it is applied`
+
+// runMigrate is the `api migrate ...` subcommand
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 || !slices.Contains([]string{"up", "down", "version", "force", "status"}, args[0]) {
+		return errors.New(migrateUsage)
+	}
+
+	conn, err := db.NewPGDB(cfg.Db.Dsn, 2, 1, cfg.Db.MaxIdleTime)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	m, err := migrate.New(conn)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		n, err := countArg(args, 0)
+		if err != nil {
+			return err
+		}
+		applied, err := m.Up(ctx, n)
+		printMigrations("applied", applied, err)
+		return err
+
+	case "down":
+		n, err := countArg(args, 1)
+		if err != nil {
+			return err
+		}
+		reverted, err := m.Down(ctx, n)
+		printMigrations("reverted", reverted, err)
+		return err
+
+	case "version":
+		version, dirty, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(formatVersion(version, dirty))
+		return nil
+
+	case "force":
+		if len(args) != 2 {
+			return errors.New("usage: api migrate force V")
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return m.Force(ctx, version)
+
+	case "status":
+		statuses, version, dirty, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%06d  %-8s %s\n", s.Version, state, s.Name)
+		}
+		if version == migrate.NilVersion {
+			fmt.Println("version: none")
+		} else {
+			fmt.Println("version:", formatVersion(version, dirty))
+		}
+		return nil
+	}
+
+	return errors.New(migrateUsage)
+}
+
+// migrateOnStart applies the pending migrations before serving when MIGRATE_ON_START is set
+func migrateOnStart(cfg *config.Config) {
+	conn, err := db.NewPGDB(cfg.Db.Dsn, 2, 1, cfg.Db.MaxIdleTime)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	m, err := migrate.New(conn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	applied, err := m.Up(context.Background(), 0)
+	printMigrations("applied", applied, err)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// countArg reads the optional N of up and down, "all" and 0 mean every migration
+func countArg(args []string, def int) (int, error) {
+	if len(args) < 2 {
+		return def, nil
+	}
+	if args[1] == "all" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count %q", args[1])
+	}
+	return n, nil
+}
+
+func printMigrations(action string, migrations []migrate.Migration, err error) {
+	if len(migrations) == 0 && err == nil {
+		log.Printf("migrate: %s", migrate.ErrNoChange)
+		return
+	}
+	for _, m := range migrations {
+		log.Printf("migrate: %s %d_%s", action, m.Version, m.Name)
+	}
+}
+
+func formatVersion(version int64, dirty bool) string {
+	if dirty {
+		return fmt.Sprintf("%d (dirty)", version)
+	}
+	return strconv.FormatInt(version, 10)
+}
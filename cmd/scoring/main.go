@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/vitalfit/api/config"
+	"github.com/vitalfit/api/internal/app"
+	"github.com/vitalfit/api/pkg/db"
+)
+
+// scoring recomputes client scoring and categories once and exits.
+// Use it from cron when the API runs with SCORING_ENABLED=false.
+func main() {
+	//initialize config
+	config := config.LoadConfig()
+
+	//db gorm connection
+	db, err := db.New(config.Db.Dsn, config.Db.MaxOpenConns, config.Db.MaxIdleConns, config.Db.MaxIdleTime)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app := app.BuildApplication(config, db)
+	if err := app.RunScoring(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
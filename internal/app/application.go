@@ -21,6 +21,7 @@ import (
 	appservices "github.com/vitalfit/api/internal/app/services"
 	"github.com/vitalfit/api/internal/shared/middleware/auth"
 	ratelimiterm "github.com/vitalfit/api/internal/shared/middleware/ratelimiter"
+	"github.com/vitalfit/api/internal/shared/middleware/requestid"
 	"github.com/vitalfit/api/internal/store"
 	"go.uber.org/zap"
 )
@@ -35,26 +36,55 @@ type application struct {
 	Store       store.Storage
 	Services    appservices.Services
 	Handlers    apphandlers.Handlers
-	ratelimiter ratelimiter.Limiter
+	ratelimiter *ratelimiter.Limiters
 }
 
 // Mount config and return router
 func (app *application) Mount() http.Handler {
 	r := gin.New()
 	docs.SwaggerInfo.BasePath = "/v1"
-	r.Use(gin.Logger(), gin.Recovery())
+	r.Use(requestid.RequestIDMiddleware(), gin.Logger(), gin.Recovery())
 	cors.SetupCORS(r)
 	m := auth.NewAuthMiddleware(app.Services)
-	rate := ratelimiterm.NewRateLimiterMiddleware(app.ratelimiter, app.Config.RateLimiter, app.Logger)
-	r.Use(rate.RateLimiterMiddleware())
+	// without trusted proxies X-Forwarded-For is ignored, so clients cannot pick the IP they are limited on
+	if err := r.SetTrustedProxies(app.Config.RateLimiter.TrustedProxies); err != nil {
+		app.Logger.Errorw("invalid trusted proxies", "error", err)
+	}
+	rate := ratelimiterm.NewRateLimiterMiddleware(app.ratelimiter, app.Config.RateLimiter, app.Logger).
+		Route("/v1/auth/login", ratelimiterm.Rule{
+			Policy: ratelimiter.PolicyLogin,
+			Key:    ratelimiterm.Join(ratelimiterm.ByIP, ratelimiterm.ByJSONField("email")),
+		}).
+		Route("/v1/auth/password/forgot", ratelimiterm.Rule{
+			Policy: ratelimiter.PolicyPasswordForgot,
+			Key:    ratelimiterm.ByJSONField("email"),
+		})
+	if app.Config.Storage.Driver == "local" {
+		r.Static("/uploads", app.Config.Storage.Local.Dir)
+	}
 	{
 
 		v1 := r.Group("/v1")
 
 		v1.GET("/health", app.HealthCheckHandler)
 
-		app.Handlers.AuthHandlers.AuthRoutes(v1, m)
-		app.Handlers.AuthHandlers.UserRoutes(v1, m)
+		// authenticated requests spend the budget of their user, the rest the one of their IP
+		api := v1.Group("", rate.Limit(
+			ratelimiterm.Rule{Policy: ratelimiter.PolicyUser, Key: ratelimiterm.BearerSubject(app.Services.AuthServices.ValidateToken)},
+			ratelimiterm.Rule{Policy: ratelimiter.PolicyIP, Key: ratelimiterm.ByIP},
+		))
+
+		app.Handlers.AuthHandlers.AuthRoutes(api, m)
+		app.Handlers.AuthHandlers.UserRoutes(api, m)
+		app.Handlers.AuthHandlers.InstructorRoutes(api, m)
+		app.Handlers.AuthHandlers.AdminRoutes(api, m)
+		app.Handlers.BranchHandlers.BranchRoutes(api, m)
+		app.Handlers.MembershipHandlers.MembershipRoutes(api, m)
+		app.Handlers.CheckinHandlers.CheckinRoutes(api, m)
+		app.Handlers.ClassHandlers.ClassRoutes(api, m)
+		app.Handlers.ScoringHandlers.ScoringRoutes(api, m)
+		app.Handlers.AuditHandlers.AuditRoutes(api, m)
+		app.Handlers.OutboxHandlers.OutboxRoutes(api, m)
 
 		v1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -76,6 +106,10 @@ func (app *application) Run(mux http.Handler) error {
 
 	shutdown := make(chan error)
 
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	app.startJobs(jobs)
+
 	go func() {
 		quit := make(chan os.Signal, 1)
 
@@ -86,6 +120,7 @@ func (app *application) Run(mux http.Handler) error {
 		defer cancel()
 
 		app.Logger.Infow("signal caught", "signal", s.String())
+		stopJobs()
 
 		shutdown <- srv.Shutdown(ctx)
 	}()
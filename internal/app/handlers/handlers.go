@@ -2,16 +2,37 @@ package apphandlers
 
 import (
 	appservices "github.com/vitalfit/api/internal/app/services"
+	audithandlers "github.com/vitalfit/api/internal/audit/handlers"
 	authhandlers "github.com/vitalfit/api/internal/auth/handlers"
+	branchhandlers "github.com/vitalfit/api/internal/branches/handlers"
+	checkinhandlers "github.com/vitalfit/api/internal/checkins/handlers"
+	classhandlers "github.com/vitalfit/api/internal/classes/handlers"
+	membershiphandlers "github.com/vitalfit/api/internal/membership/handlers"
+	outboxhandlers "github.com/vitalfit/api/internal/outbox/handlers"
+	scoringhandlers "github.com/vitalfit/api/internal/scoring/handlers"
 )
 
 type Handlers struct {
-	AuthHandlers authhandlers.AuthHandlersInterface
+	AuthHandlers       authhandlers.AuthHandlersInterface
+	BranchHandlers     branchhandlers.BranchHandlersInterface
+	MembershipHandlers membershiphandlers.MembershipHandlersInterface
+	CheckinHandlers    checkinhandlers.CheckinHandlersInterface
+	ClassHandlers      classhandlers.ClassHandlersInterface
+	ScoringHandlers    scoringhandlers.ScoringHandlersInterface
+	AuditHandlers      audithandlers.AuditHandlersInterface
+	OutboxHandlers     outboxhandlers.OutboxHandlersInterface
 }
 
 func NewAppHandlers(services appservices.Services) Handlers {
 	return Handlers{
-		AuthHandlers: authhandlers.NewAuthHandlers(services),
+		AuthHandlers:       authhandlers.NewAuthHandlers(services),
+		BranchHandlers:     branchhandlers.NewBranchHandlers(services),
+		MembershipHandlers: membershiphandlers.NewMembershipHandlers(services),
+		CheckinHandlers:    checkinhandlers.NewCheckinHandlers(services),
+		ClassHandlers:      classhandlers.NewClassHandlers(services),
+		ScoringHandlers:    scoringhandlers.NewScoringHandlers(services),
+		AuditHandlers:      audithandlers.NewAuditHandlers(services),
+		OutboxHandlers:     outboxhandlers.NewOutboxHandlers(services),
 	}
 
 }
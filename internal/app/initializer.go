@@ -7,7 +7,10 @@ import (
 	authservices "github.com/vitalfit/api/internal/auth/services"
 	"github.com/vitalfit/api/internal/store"
 	"github.com/vitalfit/api/pkg/mailer"
+	"github.com/vitalfit/api/pkg/notifier"
 	rate_mw "github.com/vitalfit/api/pkg/ratelimiter"
+	"github.com/vitalfit/api/pkg/secretbox"
+	"github.com/vitalfit/api/pkg/storage"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -17,13 +20,36 @@ func BuildApplication(cfg *config.Config, db *gorm.DB) *application {
 	//initialize store
 
 	logger := zap.Must(zap.NewProduction()).Sugar()
-	mailer, err := mailer.NewResendClient(cfg.Mail.Resend.ApiKey, cfg.Mail.FromEmail)
+	templates, err := mailer.NewTemplates(cfg.Mail.Brand, cfg.Mail.DefaultLocale)
 	if err != nil {
-		logger.Errorw("error creating mailer", "error", err.Error())
+		logger.Fatalw("error parsing mail templates", "error", err.Error())
+	}
+	mailer, err := mailer.New(cfg.Mail.Config, templates, logger)
+	if err != nil {
+		logger.Fatalw("error creating mailer", "driver", cfg.Mail.Driver, "error", err.Error())
+	}
+	notifier, err := notifier.New(cfg.Notifier, mailer, logger)
+	if err != nil {
+		logger.Fatalw("error creating notifier", "error", err.Error())
 	}
 	auth := authservices.NewJWTAuthenticator(cfg.Auth.Token.Secret, cfg.Auth.Token.Iss, cfg.Auth.Token.Iss)
-	rateLimiter := rate_mw.NewFixedWindowLimiter(cfg.RateLimiter.RequestsPerTimeFrame, cfg.RateLimiter.TimeFrame)
-	store := store.NewStorage(db, *cfg, mailer, auth)
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatalw("error getting the database pool", "error", err.Error())
+	}
+	rateLimiter, err := rate_mw.New(cfg.RateLimiter, sqlDB)
+	if err != nil {
+		logger.Fatalw("error creating rate limiter", "backend", cfg.RateLimiter.Backend, "error", err.Error())
+	}
+	files, err := storage.New(cfg.Storage)
+	if err != nil {
+		logger.Fatalw("error creating file storage", "error", err.Error())
+	}
+	secrets, err := secretbox.New(cfg.Auth.MFA.Key)
+	if err != nil {
+		logger.Fatalw("error creating the secrets box, set MFA_ENCRYPTION_KEY", "error", err.Error())
+	}
+	store := store.NewStorage(db, *cfg, notifier, templates, auth, files, secrets)
 	services := appservices.NewServices(store, logger)
 	handlers := apphandlers.NewAppHandlers(services)
 	defer logger.Sync()
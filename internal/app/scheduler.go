@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+)
+
+// startJobs launches the background jobs of the API process, they stop when ctx is cancelled
+func (app *application) startJobs(ctx context.Context) {
+	if app.Config.Scoring.Enabled {
+		go app.every(ctx, app.Config.Scoring.Interval, app.runDueScoring)
+	}
+	if app.Config.Classes.GenerateSessions {
+		go app.every(ctx, app.Config.Classes.GenerateInterval, app.GenerateSessions)
+	}
+	if app.Config.Outbox.Enabled {
+		go app.every(ctx, app.Config.Outbox.Interval, app.DispatchOutbox)
+	}
+	// one sweeper for every key replaces a goroutine per client
+	if app.Config.RateLimiter.Enabled {
+		go app.every(ctx, app.Config.RateLimiter.SweepInterval, app.ratelimiter.Sweep)
+	}
+}
+
+// every runs job right away and then at each interval until ctx is cancelled
+func (app *application) every(ctx context.Context, interval time.Duration, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := job(ctx); err != nil {
+			app.Logger.Errorw("background job failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunScoring recomputes client categories once, it is used by cmd/scoring
+func (app *application) RunScoring(ctx context.Context) error {
+	summary, err := app.Services.ScoringServices.Run(ctx)
+	return app.logScoring(summary, err)
+}
+
+// runDueScoring is the scheduled run, every replica schedules it and the first one to find it due
+// runs it while the others skip it
+func (app *application) runDueScoring(ctx context.Context) error {
+	summary, err := app.Services.ScoringServices.RunDue(ctx, app.Config.Scoring.Interval)
+	return app.logScoring(summary, err)
+}
+
+func (app *application) logScoring(summary *scoringdomain.RunSummary, err error) error {
+	if err != nil {
+		if errors.Is(err, scoringdomain.ErrRunInProgress) || errors.Is(err, scoringdomain.ErrRunNotDue) {
+			app.Logger.Infow("scoring run skipped", "reason", err)
+			return nil
+		}
+		return err
+	}
+	app.Logger.Infow("scoring run finished",
+		"evaluated", summary.Evaluated,
+		"updated", summary.Updated,
+		"category_changes", summary.CategoryChanges,
+		"categories", summary.Categories,
+	)
+	return nil
+}
+
+// GenerateSessions extends the sessions of the recurring schedules up to the horizon. Creating
+// them is idempotent so every replica can run it.
+func (app *application) GenerateSessions(ctx context.Context) error {
+	created, err := app.Services.ClassServices.GenerateSessions(ctx)
+	if err != nil {
+		return err
+	}
+	if created > 0 {
+		app.Logger.Infow("class sessions generated", "created", created)
+	}
+	return nil
+}
+
+// DispatchOutbox delivers the queued messages that are due, batch after batch until none is left
+func (app *application) DispatchOutbox(ctx context.Context) error {
+	for ctx.Err() == nil {
+		summary, err := app.Services.OutboxServices.Dispatch(ctx)
+		if err != nil {
+			return err
+		}
+		if summary.Dead > 0 {
+			app.Logger.Warnw("outbox messages dead-lettered", "dead", summary.Dead)
+		}
+		if summary.Claimed > 0 {
+			app.Logger.Infow("outbox dispatched",
+				"claimed", summary.Claimed,
+				"sent", summary.Sent,
+				"retried", summary.Retried,
+				"dead", summary.Dead,
+			)
+		}
+		if summary.Claimed < app.Config.Outbox.BatchSize {
+			return nil
+		}
+	}
+	return nil
+}
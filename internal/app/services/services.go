@@ -1,25 +1,66 @@
 package appservices
 
 import (
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	auditservices "github.com/vitalfit/api/internal/audit/services"
 	authdomain "github.com/vitalfit/api/internal/auth/domain"
 	authservices "github.com/vitalfit/api/internal/auth/services"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	branchservices "github.com/vitalfit/api/internal/branches/services"
+	checkindomain "github.com/vitalfit/api/internal/checkins/domain"
+	checkinservices "github.com/vitalfit/api/internal/checkins/services"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	classservices "github.com/vitalfit/api/internal/classes/services"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	membershipservices "github.com/vitalfit/api/internal/membership/services"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	outboxservices "github.com/vitalfit/api/internal/outbox/services"
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+	scoringservices "github.com/vitalfit/api/internal/scoring/services"
 	logs "github.com/vitalfit/api/internal/shared/errors"
 	"github.com/vitalfit/api/internal/store"
 	"go.uber.org/zap"
 )
 
 type Services struct {
-	AuthServices authdomain.AuthServicesInterface
-	UserServices authdomain.UserServicesInterface
+	AuthServices       authdomain.AuthServicesInterface
+	UserServices       authdomain.UserServicesInterface
+	InstructorServices authdomain.InstructorServicesInterface
+	MFAServices        authdomain.MFAServicesInterface
+	LockoutServices    authdomain.LockoutServicesInterface
+	PermissionServices authdomain.PermissionServicesInterface
+	RoleServices       authdomain.RoleServicesInterface
+	BranchServices     branchdomain.BranchServicesInterface
+	MembershipServices membershipdomain.MembershipServicesInterface
+	CheckinServices    checkindomain.CheckinServicesInterface
+	ClassServices      classdomain.ClassServicesInterface
+	ScoringServices    scoringdomain.ScoringServicesInterface
+	OutboxServices     outboxdomain.OutboxServicesInterface
+	AuditServices      auditdomain.AuditServicesInterface
+	AuditLogger        auditdomain.AuditLogger
 	logs.LogErrors
 	Logger *zap.SugaredLogger
 }
 
 func NewServices(store store.Storage, logger *zap.SugaredLogger) Services {
+	audit := auditservices.NewAuditService(store, logger)
 	return Services{
-		AuthServices: authservices.NewAuthServices(store),
-		UserServices: authservices.NewUserService(store),
-		LogErrors:    logs.NewLogErrors(logger),
-		Logger:       logger,
+		AuthServices:       authservices.NewAuthServices(store),
+		UserServices:       authservices.NewUserService(store),
+		InstructorServices: authservices.NewInstructorService(store),
+		MFAServices:        authservices.NewMFAService(store),
+		LockoutServices:    authservices.NewLockoutService(store),
+		PermissionServices: authservices.NewPermissionService(store),
+		RoleServices:       authservices.NewRoleService(store),
+		BranchServices:     branchservices.NewBranchService(store),
+		MembershipServices: membershipservices.NewMembershipService(store),
+		CheckinServices:    checkinservices.NewCheckinService(store),
+		ClassServices:      classservices.NewClassService(store),
+		ScoringServices:    scoringservices.NewScoringService(store),
+		OutboxServices:     outboxservices.NewOutboxService(store),
+		AuditServices:      audit,
+		AuditLogger:        audit,
+		LogErrors:          logs.NewLogErrors(logger),
+		Logger:             logger,
 	}
 }
@@ -0,0 +1,121 @@
+package auditdomain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Target types of the audit events
+const (
+	TargetUser    = "user"
+	TargetRole    = "role"
+	TargetScoring = "scoring"
+	TargetOutbox  = "outbox"
+)
+
+// AuditEvents is append-only, rows are never updated or deleted
+type AuditEvents struct {
+	EventID    uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"event_id"`
+	OccurredAt time.Time  `gorm:"autoCreateTime" json:"occurred_at"`
+	ActorID    *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	ActorRole  string     `gorm:"type:varchar(50)" json:"actor_role"`
+	Action     string     `gorm:"type:varchar(64);not null" json:"action"`
+	TargetType string     `gorm:"type:varchar(32);not null" json:"target_type"`
+	TargetID   string     `gorm:"type:varchar(64)" json:"target_id"`
+	IPAddress  string     `gorm:"type:varchar(64)" json:"ip_address"`
+	UserAgent  string     `gorm:"type:text" json:"user_agent"`
+	RequestID  string     `gorm:"type:varchar(64)" json:"request_id"`
+	Changes    Changes    `gorm:"type:jsonb;not null" json:"changes"`
+}
+
+// Entry is what a handler records, the actor and the request details are taken from the request
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	// Before and After are marshalled to JSON and only the keys that differ are kept
+	Before interface{}
+	After  interface{}
+	// ActorID is used when nobody is authenticated, like a password reset by the user itself
+	ActorID *uuid.UUID
+}
+
+// Changes holds the fields of the target before and after the action
+type Changes struct {
+	Before map[string]interface{} `json:"before,omitempty"`
+	After  map[string]interface{} `json:"after,omitempty"`
+}
+
+// NewChanges keeps the top level keys whose values differ between before and after.
+// A nil before or after records a creation or a deletion.
+func NewChanges(before, after interface{}) (Changes, error) {
+	var changes Changes
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return changes, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return changes, err
+	}
+
+	for key, value := range beforeFields {
+		if other, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			if changes.Before == nil {
+				changes.Before = map[string]interface{}{}
+			}
+			changes.Before[key] = value
+		}
+	}
+	for key, value := range afterFields {
+		if other, ok := beforeFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			if changes.After == nil {
+				changes.After = map[string]interface{}{}
+			}
+			changes.After[key] = value
+		}
+	}
+	return changes, nil
+}
+
+func toFields(value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (c Changes) Value() (driver.Value, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *Changes) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*c = Changes{}
+		return nil
+	case []byte:
+		return json.Unmarshal(data, c)
+	case string:
+		return json.Unmarshal([]byte(data), c)
+	default:
+		return errors.New("unsupported type for audit changes")
+	}
+}
@@ -0,0 +1,79 @@
+package auditdomain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultEventsPageSize = 50
+	MaxEventsPageSize     = 200
+)
+
+// AuditEventQuery is bound from the audit log query string, from and to are RFC 3339 timestamps
+type AuditEventQuery struct {
+	ActorID    string    `form:"actor_id" binding:"omitempty,uuid"`
+	Action     string    `form:"action" binding:"max=64"`
+	TargetType string    `form:"target_type" binding:"max=32"`
+	TargetID   string    `form:"target_id" binding:"max=64"`
+	RequestID  string    `form:"request_id" binding:"max=64"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page       int       `form:"page" binding:"omitempty,min=1"`
+	PageSize   int       `form:"page_size" binding:"omitempty,min=1,max=200"`
+}
+
+type AuditEventFilter struct {
+	ActorID    *uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	From       time.Time
+	To         time.Time
+	Page       int
+	PageSize   int
+}
+
+// NewAuditEventFilter maps the query string into a filter with the pagination defaults applied
+func NewAuditEventFilter(query AuditEventQuery) (AuditEventFilter, error) {
+	filter := AuditEventFilter{
+		Action:     query.Action,
+		TargetType: query.TargetType,
+		TargetID:   query.TargetID,
+		RequestID:  query.RequestID,
+		From:       query.From,
+		To:         query.To,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+	}
+	if query.ActorID != "" {
+		actorID, err := uuid.Parse(query.ActorID)
+		if err != nil {
+			return filter, err
+		}
+		filter.ActorID = &actorID
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultEventsPageSize
+	}
+	if filter.PageSize > MaxEventsPageSize {
+		filter.PageSize = MaxEventsPageSize
+	}
+	return filter, nil
+}
+
+func (f AuditEventFilter) Offset() int {
+	return (f.Page - 1) * f.PageSize
+}
+
+type AuditEventPage struct {
+	Events   []AuditEvents `json:"events"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
@@ -0,0 +1,8 @@
+package auditdomain
+
+import "context"
+
+type AuditRepository interface {
+	Create(ctx context.Context, event *AuditEvents) error
+	List(ctx context.Context, filter AuditEventFilter) ([]AuditEvents, int64, error)
+}
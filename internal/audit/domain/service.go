@@ -0,0 +1,19 @@
+package auditdomain
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogger records audit events. Log takes the actor, IP, user agent and request id from the
+// request and never fails it, errors are logged instead.
+type AuditLogger interface {
+	Log(c *gin.Context, entry Entry)
+	Record(ctx context.Context, event *AuditEvents) error
+}
+
+type AuditServicesInterface interface {
+	AuditLogger
+	List(ctx context.Context, filter AuditEventFilter) (*AuditEventPage, error)
+}
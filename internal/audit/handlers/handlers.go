@@ -0,0 +1,62 @@
+package audithandlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	appservices "github.com/vitalfit/api/internal/app/services"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+type AuditHandlersInterface interface {
+	AuditRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+}
+
+type AuditHandlers struct {
+	services appservices.Services
+}
+
+func NewAuditHandlers(services appservices.Services) *AuditHandlers {
+	return &AuditHandlers{services: services}
+}
+
+// @Summary		List audit events
+// @Description	Returns the audit log, newest first. from (inclusive) and to (exclusive) are RFC 3339 timestamps.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			actor_id	query		string						false	"Actor user ID"
+// @Param			action		query		string						false	"Action, like user.status_change"
+// @Param			target_type	query		string						false	"Target type (user, role, scoring, outbox)"
+// @Param			target_id	query		string						false	"Target ID"
+// @Param			request_id	query		string						false	"Request ID"
+// @Param			from		query		string						false	"From timestamp"
+// @Param			to			query		string						false	"To timestamp"
+// @Param			page		query		int							false	"Page, starting at 1"
+// @Param			page_size	query		int							false	"Page size, up to 200"
+// @Success		200			{object}	auditdomain.AuditEventPage	"events"
+// @Failure		400			{object}	map[string]interface{}		"invalid filter"
+// @Failure		401			{object}	map[string]interface{}		"unauthorized"
+// @Failure		403			{object}	map[string]interface{}		"forbidden"
+// @Failure		500			{object}	map[string]interface{}		"internal server error"
+// @Router			/admin/audit-events [get]
+func (h *AuditHandlers) listEventsHandler(c *gin.Context) {
+	var query auditdomain.AuditEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	filter, err := auditdomain.NewAuditEventFilter(query)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	page, err := h.services.AuditServices.List(c.Request.Context(), filter)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,13 @@
+package audithandlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+func (r *AuditHandlers) AuditRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+	auditGroup := rg.Group("/admin/audit-events").Use(m.AuthJwtTokenMiddleware(), m.RequirePermission("audit:read"))
+	{ //private routes
+		auditGroup.GET("", r.listEventsHandler)
+	}
+}
@@ -0,0 +1,71 @@
+package auditrepository
+
+import (
+	"context"
+
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+)
+
+type AuditStore struct {
+	db *gorm.DB
+}
+
+func NewAuditStore(db *gorm.DB) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+func (s *AuditStore) Create(ctx context.Context, event *auditdomain.AuditEvents) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Create(event).Error
+}
+
+// List returns the events matching the filter, newest first
+func (s *AuditStore) List(ctx context.Context, filter auditdomain.AuditEventFilter) ([]auditdomain.AuditEvents, int64, error) {
+	var (
+		events []auditdomain.AuditEvents
+		total  int64
+	)
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&auditdomain.AuditEvents{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.
+		Order("occurred_at DESC, event_id").
+		Offset(filter.Offset()).
+		Limit(filter.PageSize).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
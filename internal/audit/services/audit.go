@@ -0,0 +1,76 @@
+package auditservices
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/internal/shared/middleware/requestid"
+	"github.com/vitalfit/api/internal/store"
+	"go.uber.org/zap"
+)
+
+type AuditService struct {
+	store  store.Storage
+	logger *zap.SugaredLogger
+}
+
+func NewAuditService(store store.Storage, logger *zap.SugaredLogger) *AuditService {
+	return &AuditService{store: store, logger: logger}
+}
+
+// Log records the entry with the actor and the details of the request. The action already
+// happened, so a failed write is logged with the whole event rather than failing the request.
+func (s *AuditService) Log(c *gin.Context, entry auditdomain.Entry) {
+	event := &auditdomain.AuditEvents{
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		RequestID:  requestid.Get(c),
+	}
+	if actor, ok := c.Value("user").(*authdomain.Users); ok && actor != nil {
+		event.ActorID = &actor.UserID
+		event.ActorRole = actor.Role.Name
+	}
+
+	changes, err := auditdomain.NewChanges(entry.Before, entry.After)
+	if err != nil {
+		s.logger.Errorw("audit changes", "action", entry.Action, "error", err.Error())
+	}
+	event.Changes = changes
+
+	// the write outlives a client that hangs up once the action is done
+	ctx := context.WithoutCancel(c.Request.Context())
+	if err := s.Record(ctx, event); err != nil {
+		s.logger.Errorw("audit write failed",
+			"error", err.Error(),
+			"action", event.Action,
+			"actor_id", event.ActorID,
+			"target_type", event.TargetType,
+			"target_id", event.TargetID,
+			"request_id", event.RequestID,
+			"changes", event.Changes,
+		)
+	}
+}
+
+func (s *AuditService) Record(ctx context.Context, event *auditdomain.AuditEvents) error {
+	return s.store.Audit.Create(ctx, event)
+}
+
+func (s *AuditService) List(ctx context.Context, filter auditdomain.AuditEventFilter) (*auditdomain.AuditEventPage, error) {
+	events, total, err := s.store.Audit.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &auditdomain.AuditEventPage{
+		Events:   events,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
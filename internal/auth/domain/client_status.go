@@ -0,0 +1,33 @@
+package authdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotClient       = errors.New("user is not a client")
+	ErrStatusUnchanged = errors.New("client already has the requested status")
+)
+
+// ClientStatusHistory records every block and unblock of a client
+type ClientStatusHistory struct {
+	HistoryID      uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"history_id"`
+	UserID         uuid.UUID        `gorm:"type:uuid;not null" json:"user_id"`
+	PreviousStatus ClientStatusEnum `gorm:"type:client_status;not null" json:"previous_status"`
+	NewStatus      ClientStatusEnum `gorm:"type:client_status;not null" json:"new_status"`
+	Justification  string           `gorm:"type:text;not null" json:"justification"`
+	ChangedBy      *uuid.UUID       `gorm:"type:uuid" json:"changed_by"`
+	ChangedAt      time.Time        `gorm:"autoCreateTime" json:"changed_at"`
+}
+
+type ClientStatusPayload struct {
+	Justification string `json:"justification" binding:"required,min=5,max=1000"`
+}
+
+// IsBlocked reports whether the user is a client that staff has blocked
+func (u *Users) IsBlocked() bool {
+	return u.ClientProfile.Status == ClientStatusBlocked
+}
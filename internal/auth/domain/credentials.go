@@ -0,0 +1,35 @@
+package authdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWrongPassword      = errors.New("current password is incorrect")
+	ErrSameEmail          = errors.New("new email is the current email")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+)
+
+// EmailChangeRequests holds the hashed confirmation code sent to the new address
+type EmailChangeRequests struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Token     string    `gorm:"type:varchar(255);not null" json:"-"`
+	NewEmail  string    `gorm:"type:citext;not null" json:"new_email"`
+	Expiry    time.Time `gorm:"not null" json:"expiry"`
+	Attempts  int       `gorm:"not null;default:0" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChangePasswordPayload struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Password        string `json:"password" binding:"required,min=8,max=72"`
+	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=Password"`
+}
+
+type ChangeEmailPayload struct {
+	NewEmail        string `json:"new_email" binding:"required,email,max=255"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
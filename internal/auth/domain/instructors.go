@@ -0,0 +1,62 @@
+package authdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrNotInstructor = errors.New("user is not an instructor")
+
+type Instructors struct {
+	InstructorID uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"instructor_id"`
+	UserID       uuid.UUID      `gorm:"type:uuid;unique;not null" json:"user_id"`
+	User         *Users         `gorm:"foreignKey:UserID;references:UserID" json:"-"`
+	Speciality   string         `gorm:"type:varchar(255)" json:"speciality"`
+	Biography    string         `gorm:"type:text" json:"biography"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// PublicInstructor is the trainer roster entry, without the private user data
+type PublicInstructor struct {
+	InstructorID      uuid.UUID `json:"instructor_id"`
+	FirstName         string    `json:"first_name"`
+	LastName          string    `json:"last_name"`
+	ProfilePictureURL string    `json:"profile_picture_url"`
+	Speciality        string    `json:"speciality"`
+	Biography         string    `json:"biography"`
+}
+
+func NewPublicInstructor(instructor *Instructors) PublicInstructor {
+	public := PublicInstructor{
+		InstructorID: instructor.InstructorID,
+		Speciality:   instructor.Speciality,
+		Biography:    instructor.Biography,
+	}
+	if instructor.User != nil {
+		public.FirstName = instructor.User.FirstName
+		public.LastName = instructor.User.LastName
+		public.ProfilePictureURL = instructor.User.ProfilePictureURL
+	}
+	return public
+}
+
+type InstructorFilter struct {
+	BranchID   *uuid.UUID
+	Speciality string
+}
+
+type InstructorPayload struct {
+	Speciality string `json:"speciality" binding:"max=255"`
+	Biography  string `json:"biography"`
+}
+
+type CreateInstructorPayload struct {
+	UserID     string `json:"user_id" binding:"required,uuid"`
+	Speciality string `json:"speciality" binding:"max=255"`
+	Biography  string `json:"biography"`
+}
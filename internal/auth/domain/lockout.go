@@ -0,0 +1,110 @@
+package authdomain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidOTP    = errors.New("invalid or expired code")
+	ErrAccountLocked = errors.New("too many failed attempts")
+)
+
+type AttemptScope string
+
+const (
+	AttemptScopeLogin         AttemptScope = "login"
+	AttemptScopeActivate      AttemptScope = "activate"
+	AttemptScopePasswordReset AttemptScope = "password_reset"
+	AttemptScopeMFA           AttemptScope = "mfa"
+)
+
+// AnyAddress keys the counter of an identifier across every address, it is checked along the
+// counter of the address so rotating addresses does not buy unlimited guesses
+const AnyAddress = "*"
+
+// LockoutPolicy sets how failed attempts slow down and lock an identifier
+type LockoutPolicy struct {
+	// FreeFailures are allowed before any delay, each further failure doubles the delay from BaseDelay up to MaxDelay
+	FreeFailures int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	// MaxFailures locks the identifier for LockoutDuration
+	MaxFailures     int
+	LockoutDuration time.Duration
+	// FailureWindow without failures resets the counter
+	FailureWindow time.Duration
+	// MaxOTPAttempts wrong guesses discard a one time code
+	MaxOTPAttempts int
+}
+
+// Delay returns how long the identifier must wait after its n-th consecutive failure and whether it is a lockout
+func (p LockoutPolicy) Delay(failures int) (time.Duration, bool) {
+	if p.MaxFailures > 0 && failures >= p.MaxFailures {
+		return p.LockoutDuration, true
+	}
+	if failures <= p.FreeFailures {
+		return 0, false
+	}
+	delay := p.BaseDelay
+	for i := p.FreeFailures + 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay), false
+}
+
+// LoginAttempts counts the consecutive failures of an identifier from an address within a scope.
+// Keying on the address too keeps anyone who knows an email from locking its owner out, the
+// AnyAddress row counts them from everywhere under a laxer policy.
+type LoginAttempts struct {
+	Scope         AttemptScope `gorm:"type:varchar(32);primaryKey" json:"scope"`
+	Identifier    string       `gorm:"type:citext;primaryKey" json:"identifier"`
+	IPAddress     string       `gorm:"type:varchar(64);primaryKey" json:"ip_address"`
+	Failures      int          `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time    `gorm:"not null" json:"last_failure_at"`
+	LockedUntil   *time.Time   `json:"locked_until"`
+}
+
+// AccountLockouts records every time an identifier reached the failure limit
+type AccountLockouts struct {
+	LockoutID   uuid.UUID    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"lockout_id"`
+	Scope       AttemptScope `gorm:"type:varchar(32);not null" json:"scope"`
+	Identifier  string       `gorm:"type:citext;not null" json:"identifier"`
+	UserID      *uuid.UUID   `gorm:"type:uuid" json:"user_id"`
+	Failures    int          `gorm:"not null" json:"failures"`
+	IPAddress   string       `gorm:"type:varchar(64)" json:"ip_address"`
+	LockedAt    time.Time    `gorm:"autoCreateTime" json:"locked_at"`
+	LockedUntil time.Time    `gorm:"not null" json:"locked_until"`
+	UnlockedAt  *time.Time   `json:"unlocked_at"`
+	UnlockedBy  *uuid.UUID   `gorm:"type:uuid" json:"unlocked_by"`
+}
+
+// LockedError is returned while an identifier has to wait before trying again
+type LockedError struct {
+	Until   time.Time
+	Lockout bool
+}
+
+func (e *LockedError) Error() string {
+	if e.Lockout {
+		return fmt.Sprintf("account locked after too many failed attempts until %s", e.Until.Format(time.RFC3339))
+	}
+	return "too many failed attempts, try again later"
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// RetryAfter rounds the remaining wait up to whole seconds
+func (e *LockedError) RetryAfter(now time.Time) time.Duration {
+	return max(e.Until.Sub(now).Truncate(time.Second)+time.Second, time.Second)
+}
+
+type ActivatePayload struct {
+	Email string `json:"email" binding:"omitempty,email,max=255"` // Optional, the user is found by the code when it is missing
+	Code  string `json:"code" binding:"required"`
+}
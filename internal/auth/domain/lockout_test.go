@@ -0,0 +1,46 @@
+package authdomain_test
+
+import (
+	"testing"
+	"time"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+)
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	policy := authdomain.LockoutPolicy{
+		FreeFailures:    3,
+		BaseDelay:       2 * time.Second,
+		MaxDelay:        time.Minute,
+		MaxFailures:     10,
+		LockoutDuration: 15 * time.Minute,
+	}
+	unlimited := policy
+	unlimited.MaxFailures = 0
+
+	tests := []struct {
+		name        string
+		policy      authdomain.LockoutPolicy
+		failures    int
+		wantDelay   time.Duration
+		wantLockout bool
+	}{
+		{name: "no failures", policy: policy, failures: 0},
+		{name: "last free failure", policy: policy, failures: 3},
+		{name: "first delayed failure", policy: policy, failures: 4, wantDelay: 2 * time.Second},
+		{name: "delay doubles", policy: policy, failures: 5, wantDelay: 4 * time.Second},
+		{name: "delay keeps doubling", policy: policy, failures: 8, wantDelay: 32 * time.Second},
+		{name: "delay capped", policy: policy, failures: 9, wantDelay: time.Minute},
+		{name: "failure limit locks", policy: policy, failures: 10, wantDelay: 15 * time.Minute, wantLockout: true},
+		{name: "past the limit stays locked", policy: policy, failures: 25, wantDelay: 15 * time.Minute, wantLockout: true},
+		{name: "no limit never locks", policy: unlimited, failures: 25, wantDelay: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, lockout := tt.policy.Delay(tt.failures)
+			if delay != tt.wantDelay || lockout != tt.wantLockout {
+				t.Errorf("Delay(%d) = %v, %v, want %v, %v", tt.failures, delay, lockout, tt.wantDelay, tt.wantLockout)
+			}
+		})
+	}
+}
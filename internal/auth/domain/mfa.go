@@ -0,0 +1,67 @@
+package authdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidMFACode       = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled        = errors.New("two-factor authentication is not enabled")
+	ErrMFAEnrollmentPending = errors.New("two-factor enrollment was not started")
+	ErrMFARequired          = errors.New("two-factor authentication is mandatory for this role")
+)
+
+// UserMFA holds the encrypted TOTP secret of a user. EnabledAt is nil while the enrollment is pending.
+type UserMFA struct {
+	UserID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Secret       []byte     `gorm:"type:bytea;not null" json:"-"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type MFARecoveryCodes struct {
+	CodeID    uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"code_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	CodeHash  []byte     `gorm:"type:bytea;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MFAEnrollment is shown once so the user can add the account to an authenticator app
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type MFAStatus struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+// MFAChallenge is returned by login instead of tokens when the account has two-factor enabled
+type MFAChallenge struct {
+	MFARequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int64  `json:"expires_in"`
+}
+
+type MFACodePayload struct {
+	Code string `json:"code" binding:"required,min=6,max=16"`
+}
+
+type MFALoginPayload struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,min=6,max=16"`
+}
+
+type DisableMFAPayload struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,min=6,max=16"`
+}
@@ -0,0 +1,43 @@
+package authdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuperAdminRole holds every permission without being mapped
+const SuperAdminRole = "super_admin"
+
+var ErrSuperAdminPermissions = errors.New("super_admin holds every permission, its grants cannot be edited")
+
+type Permissions struct {
+	PermissionID uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"permission_id"`
+	Name         string    `gorm:"type:varchar(100);unique;not null" json:"name"`
+	Description  string    `gorm:"type:text" json:"description"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RolePermissions struct {
+	RoleID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"role_id"`
+	PermissionID uuid.UUID  `gorm:"type:uuid;primaryKey" json:"permission_id"`
+	GrantedBy    *uuid.UUID `gorm:"type:uuid" json:"granted_by"`
+	GrantedAt    time.Time  `gorm:"autoCreateTime" json:"granted_at"`
+}
+
+// PermissionSet is the resolved set of permission names of a user
+type PermissionSet map[string]struct{}
+
+func NewPermissionSet(permissions []Permissions) PermissionSet {
+	set := make(PermissionSet, len(permissions))
+	for _, permission := range permissions {
+		set[permission.Name] = struct{}{}
+	}
+	return set
+}
+
+func (s PermissionSet) Has(name string) bool {
+	_, ok := s[name]
+	return ok
+}
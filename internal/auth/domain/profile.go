@@ -0,0 +1,94 @@
+package authdomain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vitalfit/api/pkg/notifier"
+)
+
+var (
+	ErrInvalidBirthDate = errors.New("birth date must be a past date in YYYY-MM-DD format")
+	ErrBlankName        = errors.New("first and last name cannot be blank")
+)
+
+// UpdateProfilePayload holds the fields a user can change on its own profile, omitted fields are kept
+type UpdateProfilePayload struct {
+	FirstName         *string `json:"first_name" binding:"omitempty,min=1,max=100"`
+	LastName          *string `json:"last_name" binding:"omitempty,min=1,max=100"`
+	Phone             *string `json:"phone" binding:"omitempty,min=7,max=50"`
+	BirthDate         *string `json:"birth_date" binding:"omitempty"`
+	Gender            *string `json:"gender" binding:"omitempty,oneof=male female prefer-not-to-say"`
+	PreferredLanguage *string `json:"preferred_language" binding:"omitempty,oneof=es en"`
+}
+
+// Apply copies the present fields of the payload into the user. Names are trimmed and the phone
+// normalized before they are checked, the user is left untouched when a field is invalid.
+func (p UpdateProfilePayload) Apply(user *Users, now time.Time) error {
+	var birthDate time.Time
+	if p.BirthDate != nil {
+		var err error
+		birthDate, err = time.Parse(time.DateOnly, *p.BirthDate)
+		if err != nil || !birthDate.Before(now) {
+			return ErrInvalidBirthDate
+		}
+	}
+	var firstName, lastName string
+	if p.FirstName != nil {
+		if firstName = strings.TrimSpace(*p.FirstName); firstName == "" {
+			return ErrBlankName
+		}
+	}
+	if p.LastName != nil {
+		if lastName = strings.TrimSpace(*p.LastName); lastName == "" {
+			return ErrBlankName
+		}
+	}
+	var phone string
+	if p.Phone != nil {
+		var err error
+		if phone, err = notifier.NormalizePhone(*p.Phone); err != nil {
+			return err
+		}
+	}
+
+	if p.BirthDate != nil {
+		user.BirthDate = birthDate
+	}
+	if p.FirstName != nil {
+		user.FirstName = firstName
+	}
+	if p.LastName != nil {
+		user.LastName = lastName
+	}
+	if p.Phone != nil {
+		user.Phone = phone
+	}
+	if p.Gender != nil {
+		user.Gender = GenderEnum(*p.Gender)
+	}
+	if p.PreferredLanguage != nil {
+		user.PreferredLanguage = *p.PreferredLanguage
+	}
+	return nil
+}
+
+// Languages are the ones emails and messages are written in
+var Languages = []string{"es", "en"}
+
+// LanguageFromHeader returns the first supported language of an Accept-Language header,
+// empty when there is none so the configured default applies
+func LanguageFromHeader(header string) string {
+	for _, tag := range strings.Split(header, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+		tag = strings.ToLower(tag)
+		for _, language := range Languages {
+			if tag == language {
+				return language
+			}
+		}
+	}
+	return ""
+}
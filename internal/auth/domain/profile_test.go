@@ -0,0 +1,45 @@
+package authdomain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/pkg/notifier"
+)
+
+func TestUpdateProfilePayloadApply(t *testing.T) {
+	text := func(s string) *string { return &s }
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payload authdomain.UpdateProfilePayload
+		err     error
+		want    authdomain.Users
+	}{
+		{name: "trimmed names", payload: authdomain.UpdateProfilePayload{FirstName: text("  Ana "), LastName: text(" Pérez")}, want: authdomain.Users{FirstName: "Ana", LastName: "Pérez", Phone: "+584121111111"}},
+		{name: "blank first name", payload: authdomain.UpdateProfilePayload{FirstName: text("   ")}, err: authdomain.ErrBlankName},
+		{name: "blank last name", payload: authdomain.UpdateProfilePayload{LastName: text("\t")}, err: authdomain.ErrBlankName},
+		{name: "normalized phone", payload: authdomain.UpdateProfilePayload{Phone: text("+58 (412) 123-4567")}, want: authdomain.Users{FirstName: "Old", LastName: "Name", Phone: "+584121234567"}},
+		{name: "local phone", payload: authdomain.UpdateProfilePayload{Phone: text("0412 123 4567")}, err: notifier.ErrInvalidPhone},
+		{name: "invalid phone keeps the names", payload: authdomain.UpdateProfilePayload{FirstName: text("Ana"), Phone: text("phone")}, err: notifier.ErrInvalidPhone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := authdomain.Users{FirstName: "Old", LastName: "Name", Phone: "+584121111111"}
+			err := tt.payload.Apply(&user, now)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Apply = %v, want %v", err, tt.err)
+			}
+			want := tt.want
+			if tt.err != nil {
+				want = authdomain.Users{FirstName: "Old", LastName: "Name", Phone: "+584121111111"}
+			}
+			if user.FirstName != want.FirstName || user.LastName != want.LastName || user.Phone != want.Phone {
+				t.Errorf("user = %q %q %q, want %q %q %q", user.FirstName, user.LastName, user.Phone, want.FirstName, want.LastName, want.Phone)
+			}
+		})
+	}
+}
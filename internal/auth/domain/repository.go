@@ -5,22 +5,85 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, tx *gorm.DB, user *Users) error
 	GetByID(ctx context.Context, userID uuid.UUID) (*Users, error)
-	CreateAndInvitate(ctx context.Context, user *Users, token string, invitationExp time.Duration) error
+	CreateAndInvitate(ctx context.Context, user *Users, branchIDs []uuid.UUID, token string, invitationExp time.Duration, message *outboxdomain.Outbox) error
 	Delete(ctx context.Context, userID uuid.UUID) error
-	Activate(ctx context.Context, code string) error
+	Activate(ctx context.Context, userID uuid.UUID, code string, maxAttempts int) error
+	InvitationOwner(ctx context.Context, email string, code string) (uuid.UUID, error)
+	ResetTokenOwner(ctx context.Context, email string, code string) (uuid.UUID, error)
 	GetByEmail(ctx context.Context, email string) (*Users, error)
 	Update(ctx context.Context, user *Users) error
-	CreatePasswordResetToken(ctx context.Context, userID uuid.UUID, key string, tokenExp time.Duration) error
+	UpdateProfile(ctx context.Context, user *Users) error
+	UpdatePassword(ctx context.Context, user *Users) error
+	CreateEmailChange(ctx context.Context, change *EmailChangeRequests, message *outboxdomain.Outbox) error
+	ConfirmEmailChange(ctx context.Context, userID uuid.UUID, token string, maxAttempts int) (*EmailChangeRequests, error)
+	CreatePasswordResetToken(ctx context.Context, userID uuid.UUID, key string, tokenExp time.Duration, message *outboxdomain.Outbox) error
 	DeleteResetToken(ctx context.Context, userID uuid.UUID) error
-	ResetUserPassword(ctx context.Context, key string, user *Users) error
+	ResetUserPassword(ctx context.Context, key string, user *Users, maxAttempts int) error
+	SetQRCode(ctx context.Context, userID uuid.UUID, code string) error
+	List(ctx context.Context, filter UserFilter) ([]Users, int64, error)
+	InBranches(ctx context.Context, userID uuid.UUID, branchIDs []uuid.UUID) (bool, error)
+	SetClientStatus(ctx context.Context, change *ClientStatusHistory) error
+	ListStatusHistory(ctx context.Context, userID uuid.UUID) ([]ClientStatusHistory, error)
+	ChangeRole(ctx context.Context, userID uuid.UUID, role *Roles) (*UserRoleChange, error)
 }
 
 type RolesRepository interface {
 	GetByName(ctx context.Context, name string) (*Roles, error)
+	GetByID(ctx context.Context, roleID uuid.UUID) (*Roles, error)
+	List(ctx context.Context) ([]Roles, error)
+	Create(ctx context.Context, role *Roles) error
+	Update(ctx context.Context, role *Roles) error
+	Delete(ctx context.Context, roleID uuid.UUID) error
+}
+
+type PermissionRepository interface {
+	List(ctx context.Context) ([]Permissions, error)
+	ListForRole(ctx context.Context, roleID uuid.UUID) ([]Permissions, error)
+	Grant(ctx context.Context, roleID uuid.UUID, name string, grantedBy uuid.UUID) error
+	Revoke(ctx context.Context, roleID uuid.UUID, name string) error
+}
+
+type InstructorRepository interface {
+	Create(ctx context.Context, instructor *Instructors) error
+	GetByID(ctx context.Context, instructorID uuid.UUID) (*Instructors, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*Instructors, error)
+	List(ctx context.Context, filter InstructorFilter) ([]Instructors, error)
+	Update(ctx context.Context, instructor *Instructors) error
+	Delete(ctx context.Context, instructorID uuid.UUID) error
+}
+
+type MFARepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*UserMFA, error)
+	SavePending(ctx context.Context, mfa *UserMFA) error
+	Enable(ctx context.Context, userID uuid.UUID, step int64, codeHashes [][]byte) error
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes [][]byte) error
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+	Disable(ctx context.Context, userID uuid.UUID) error
+}
+
+type LockoutRepository interface {
+	Get(ctx context.Context, scope AttemptScope, identifier string, ipAddress string) (*LoginAttempts, error)
+	RegisterFailure(ctx context.Context, scope AttemptScope, identifier string, ipAddress string, policy LockoutPolicy) (*LoginAttempts, error)
+	Clear(ctx context.Context, scope AttemptScope, identifier string, ipAddress string) error
+	Unlock(ctx context.Context, identifier string, unlockedBy uuid.UUID) error
+	ListLockouts(ctx context.Context, userID uuid.UUID) ([]AccountLockouts, error)
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshTokens) error
+	Rotate(ctx context.Context, tokenHash []byte, next *RefreshTokens) (*RefreshTokens, error)
+	GetByHash(ctx context.Context, tokenHash []byte) (*RefreshTokens, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	RevokeOthers(ctx context.Context, userID, keepFamilyID uuid.UUID) error
+	IsFamilyActive(ctx context.Context, familyID uuid.UUID) (bool, error)
 }
@@ -0,0 +1,90 @@
+package authdomain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRoleEscalation  = errors.New("cannot manage or assign a role above your own level")
+	ErrLastSuperAdmin  = errors.New("the last super_admin cannot be demoted")
+	ErrSystemRole      = errors.New("system roles cannot be renamed or deleted, and super_admin keeps its level")
+	ErrRoleInUse       = errors.New("role is still assigned to users")
+	ErrInvalidRoleName = errors.New("role name must be lowercase letters, digits and underscores, starting with a letter")
+	ErrRoleUnchanged   = errors.New("user already has the requested role")
+)
+
+// SystemRoles are referenced by name in the code, they keep their names and cannot be deleted
+var SystemRoles = map[string]bool{
+	SuperAdminRole: true,
+	"branch_admin": true,
+	"accountant":   true,
+	"data_analyst": true,
+	"instructor":   true,
+	"recepcionist": true,
+	"client":       true,
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+func ValidRoleName(name string) bool {
+	return roleNamePattern.MatchString(name)
+}
+
+// CanAssign reports whether the user may manage or hand out the role
+func (u *Users) CanAssign(role *Roles) bool {
+	return role.Level <= u.Role.Level
+}
+
+type CreateRolePayload struct {
+	Name        string `json:"name" binding:"required,min=2,max=50"`
+	Level       int16  `json:"level" binding:"required,min=1,max=99"`
+	Description string `json:"description" binding:"max=1000"`
+}
+
+// UpdateRolePayload holds the fields to change, omitted fields are kept
+type UpdateRolePayload struct {
+	Name        *string `json:"name" binding:"omitempty,min=2,max=50"`
+	Level       *int16  `json:"level" binding:"omitempty,min=1,max=99"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+}
+
+// Apply copies the present fields of the payload into the role
+func (p UpdateRolePayload) Apply(role *Roles) error {
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name != role.Name {
+			if SystemRoles[role.Name] {
+				return ErrSystemRole
+			}
+			if !ValidRoleName(name) {
+				return ErrInvalidRoleName
+			}
+			role.Name = name
+		}
+	}
+	if p.Level != nil {
+		if role.Name == SuperAdminRole && *p.Level != role.Level {
+			return ErrSystemRole
+		}
+		role.Level = *p.Level
+	}
+	if p.Description != nil {
+		role.Description = strings.TrimSpace(*p.Description)
+	}
+	return nil
+}
+
+type ChangeUserRolePayload struct {
+	RoleName string `json:"role_name" binding:"required"`
+}
+
+// UserRoleChange describes a role change of a user
+type UserRoleChange struct {
+	UserID       uuid.UUID `json:"user_id"`
+	PreviousRole string    `json:"previous_role"`
+	NewRole      string    `json:"new_role"`
+}
@@ -0,0 +1,37 @@
+package authdomain_test
+
+import (
+	"os"
+	"testing"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+// TestModelsMatchMigrations fails when a struct drifts from the table the migrations create,
+// add every new persisted model of the module here
+func TestModelsMatchMigrations(t *testing.T) {
+	db := testdb.New(t)
+
+	testdb.CheckModels(t, db,
+		&authdomain.Roles{},
+		&authdomain.Users{},
+		&authdomain.ClientProfiles{},
+		&authdomain.Instructors{},
+		&authdomain.UserInvitations{},
+		&authdomain.PasswordResetToken{},
+		&authdomain.RefreshTokens{},
+		&authdomain.ClientStatusHistory{},
+		&authdomain.EmailChangeRequests{},
+		&authdomain.UserMFA{},
+		&authdomain.MFARecoveryCodes{},
+		&authdomain.LoginAttempts{},
+		&authdomain.AccountLockouts{},
+		&authdomain.Permissions{},
+		&authdomain.RolePermissions{},
+	)
+}
@@ -2,10 +2,12 @@ package authdomain
 
 import (
 	"context"
+	"io"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/vitalfit/api/pkg/notifier"
 )
 
 type Authenticator interface {
@@ -14,22 +16,79 @@ type Authenticator interface {
 }
 
 type AuthServicesInterface interface {
-	RegisterUserClient(ctx context.Context, user *Users, token string) error
-	RegisterUserStaff(ctx context.Context, user *Users, token string, roleName string) error
+	RegisterUserClient(ctx context.Context, user *Users, code string, channel notifier.Channel) error
+	RegisterUserStaff(ctx context.Context, actor *Users, user *Users, code string, roleName string, branchIDs []uuid.UUID, channel notifier.Channel) error
 	Delete(context.Context, uuid.UUID) error
-	MailSender(ctx context.Context, user *Users, key string, template string) (int, error)
-	Activate(ctx context.Context, code string) error
-	GenerateToken(user *Users) (string, error)
+	Activate(ctx context.Context, payload ActivatePayload, ipAddress string) error
+	Authenticate(ctx context.Context, payload CreateUserTokenPayload, ipAddress string) (*Users, error)
+	GenerateToken(user *Users, sessionID uuid.UUID) (string, error)
 	ValidateToken(token string) (*jwt.Token, error)
-	CreatePasswordResetToken(ctx context.Context, email string, key string) error
+	IssueTokens(ctx context.Context, user *Users, meta SessionMeta) (*TokenPair, error)
+	RefreshTokens(ctx context.Context, refreshToken string, meta SessionMeta) (*TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	CreatePasswordResetToken(ctx context.Context, user *Users, code string, channel notifier.Channel) error
 	DeleteResetToken(context.Context, uuid.UUID) error
-	ResetPassword(ctx context.Context, key string, user *Users) error
+	ResetPassword(ctx context.Context, payload ResetPasswordPayload, ipAddress string) (*Users, error)
+	ChangePassword(ctx context.Context, user *Users, sessionID uuid.UUID, payload ChangePasswordPayload) error
+	RequestEmailChange(ctx context.Context, user *Users, payload ChangeEmailPayload) error
+	ConfirmEmailChange(ctx context.Context, user *Users, code string) (*EmailChangeRequests, error)
+	NotifyEmailChanged(ctx context.Context, user *Users, change *EmailChangeRequests, previousEmail string) error
+	NewMFAChallenge(user *Users) (*MFAChallenge, error)
+	CompleteMFALogin(ctx context.Context, payload MFALoginPayload, meta SessionMeta) (*TokenPair, error)
 }
 
 type UserServicesInterface interface {
 	GetByID(ctx context.Context, userID uuid.UUID) (*Users, error)
 	Update(ctx context.Context, user *Users) error
 	GetByEmail(ctx context.Context, email string) (*Users, error)
+	UpdateProfile(ctx context.Context, user *Users, payload UpdateProfilePayload) error
+	SetAvatar(ctx context.Context, user *Users, image io.Reader) error
+	AvatarUploadLimit() int64
 	GetUserFromContext(c *gin.Context) *Users
 	GetRoleByName(ctx context.Context, name string) (*Roles, error)
+	List(ctx context.Context, filter UserFilter) (*UserPage, error)
+	SetClientStatus(ctx context.Context, change *ClientStatusHistory) error
+	StatusHistory(ctx context.Context, userID uuid.UUID) ([]ClientStatusHistory, error)
+}
+
+type MFAServicesInterface interface {
+	Required(user *Users) bool
+	Status(ctx context.Context, user *Users) (*MFAStatus, error)
+	Enroll(ctx context.Context, user *Users) (*MFAEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, user *Users, code string) ([]string, error)
+	RegenerateRecoveryCodes(ctx context.Context, user *Users, code string) ([]string, error)
+	Disable(ctx context.Context, user *Users, payload DisableMFAPayload) error
+	Verify(ctx context.Context, user *Users, code string) error
+}
+
+type RoleServicesInterface interface {
+	List(ctx context.Context) ([]Roles, error)
+	Create(ctx context.Context, actor *Users, payload CreateRolePayload) (*Roles, error)
+	Update(ctx context.Context, actor *Users, roleID uuid.UUID, payload UpdateRolePayload) (*Roles, *Roles, error)
+	Delete(ctx context.Context, actor *Users, roleID uuid.UUID) (*Roles, error)
+	ChangeUserRole(ctx context.Context, actor *Users, userID uuid.UUID, roleName string) (*UserRoleChange, error)
+}
+
+type PermissionServicesInterface interface {
+	List(ctx context.Context) ([]Permissions, error)
+	Resolve(ctx context.Context, user *Users) (PermissionSet, error)
+	RolePermissions(ctx context.Context, roleID uuid.UUID) ([]Permissions, error)
+	Grant(ctx context.Context, actor *Users, roleID uuid.UUID, name string) error
+	Revoke(ctx context.Context, actor *Users, roleID uuid.UUID, name string) error
+}
+
+type LockoutServicesInterface interface {
+	Unlock(ctx context.Context, userID, unlockedBy uuid.UUID) error
+	ListLockouts(ctx context.Context, userID uuid.UUID) ([]AccountLockouts, error)
+}
+
+type InstructorServicesInterface interface {
+	Create(ctx context.Context, instructor *Instructors) error
+	GetByID(ctx context.Context, instructorID uuid.UUID) (*Instructors, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*Instructors, error)
+	List(ctx context.Context, filter InstructorFilter) ([]Instructors, error)
+	Update(ctx context.Context, instructor *Instructors) error
+	Delete(ctx context.Context, instructorID uuid.UUID) error
 }
@@ -0,0 +1,39 @@
+package authdomain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokens stores the hash of an opaque refresh token. Tokens issued from
+// the same login share a FamilyID, which identifies the session (device).
+type RefreshTokens struct {
+	TokenID    uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"token_id"`
+	TokenHash  []byte     `gorm:"type:bytea;unique;not null" json:"-"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	FamilyID   uuid.UUID  `gorm:"type:uuid;not null" json:"family_id"`
+	UserAgent  string     `gorm:"type:text" json:"user_agent"`
+	IPAddress  string     `gorm:"type:varchar(64)" json:"ip_address"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uuid.UUID `gorm:"type:uuid" json:"replaced_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// SessionMeta describes the client a session was opened from.
+type SessionMeta struct {
+	UserAgent string
+	IPAddress string
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
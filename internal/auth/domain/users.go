@@ -99,8 +99,11 @@ type Users struct {
 	BirthDate         time.Time      `gorm:"type:date" json:"birth_date"`
 	Gender            GenderEnum     `gorm:"type:gender_enum" json:"gender"`
 	ProfilePictureURL string         `gorm:"type:varchar(255)" json:"profile_picture_url"`
+	PreferredLanguage string         `gorm:"type:varchar(5);not null;default:es" json:"preferred_language"`
 	IsValidated       bool           `gorm:"default:false" json:"is_validated"`
+	MFAEnabled        bool           `gorm:"column:mfa_enabled;not null;default:false" json:"mfa_enabled"`
 	ClientProfile     ClientProfiles `gorm:"foreignKey:UserID;references:UserID"`
+	InstructorProfile *Instructors   `gorm:"foreignKey:UserID;references:UserID" json:"instructor_profile,omitempty"`
 
 	RoleID uuid.UUID `gorm:"type:uuid;not null" json:"role_id"`
 	Role   Roles     `gorm:"foreignKey:RoleID;references:RoleID" json:"role"`
@@ -113,7 +116,7 @@ type Users struct {
 type ClientProfiles struct {
 	// Primary Key and Foreign Key to Users
 	UserID             uuid.UUID          `gorm:"type:uuid;primaryKey" json:"user_id"`
-	QRCode             string             `gorm:"type:text" json:"qr_code"`
+	QRCode             string             `gorm:"type:text" json:"-"` // check-in credential, only handed out by the qr endpoint
 	Scoring            int                `gorm:"type:integer;default:0" json:"scoring"`
 	Status             ClientStatusEnum   `gorm:"type:client_status;not null;default:'Active'" json:"status"`
 	BlockJustification string             `gorm:"type:text" json:"block_justification"`
@@ -124,10 +127,12 @@ type ClientProfiles struct {
 }
 
 type UserInvitations struct {
-	Token  string `gorm:"type:varchar(255);unique;not null" json:"token"`
-	UserID uuid.UUID
-	Users  Users     `gorm:"foreignKey:UserID" json:"user"`
-	Expiry time.Time `gorm:"expiry"`
+	// Token is the hex sha256 of the code, stored as bytea
+	Token    string    `gorm:"type:bytea;primaryKey" json:"token"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null"`
+	Users    Users     `gorm:"foreignKey:UserID" json:"user"`
+	Expiry   time.Time `gorm:"not null"`
+	Attempts int       `gorm:"not null;default:0" json:"-"`
 }
 
 type CreateUserClientPayload struct {
@@ -140,6 +145,9 @@ type CreateUserClientPayload struct {
 	BirthDate         string `json:"birth_date" binding:"required"`
 	Gender            string `json:"gender" binding:"required"`
 	ProfilePictureURL string `json:"profile_picture_url"`
+	PreferredLanguage string `json:"preferred_language" binding:"omitempty,oneof=es en"`
+	// Channel is where the activation code is sent, sms and whatsapp use the phone
+	Channel string `json:"channel" binding:"omitempty,oneof=email sms whatsapp"`
 }
 
 type CreateUserStaffPayload struct {
@@ -153,6 +161,12 @@ type CreateUserStaffPayload struct {
 	BirthDate         string `json:"birth_date" binding:"required"`
 	Gender            string `json:"gender" binding:"required"`
 	ProfilePictureURL string `json:"profile_picture_url"`
+	PreferredLanguage string `json:"preferred_language" binding:"omitempty,oneof=es en"`
+	// Channel is where the activation code is sent, sms and whatsapp use the phone
+	Channel    string   `json:"channel" binding:"omitempty,oneof=email sms whatsapp"`
+	BranchIDs  []string `json:"branch_ids" binding:"omitempty,dive,uuid"`
+	Speciality string   `json:"speciality" binding:"max=255"`
+	Biography  string   `json:"biography"`
 }
 
 type CodePayload struct {
@@ -165,17 +179,22 @@ type CreateUserTokenPayload struct {
 }
 
 type PasswordResetToken struct {
-	Token  string `gorm:"type:varchar(255);unique;not null" json:"token"`
-	UserID uuid.UUID
-	Users  Users     `gorm:"foreignKey:UserID" json:"user"`
-	Expiry time.Time `gorm:"expiry"`
+	// Token is the hex sha256 of the code, stored as bytea
+	Token    string    `gorm:"type:bytea;primaryKey" json:"token"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null"`
+	Users    Users     `gorm:"foreignKey:UserID" json:"user"`
+	Expiry   time.Time `gorm:"not null"`
+	Attempts int       `gorm:"not null;default:0" json:"-"`
 }
 
 type ForgotPasswordPayload struct {
 	Email string `json:"email" binding:"required,email,max=255"`
+	// Channel is where the reset code is sent, sms and whatsapp use the phone of the account
+	Channel string `json:"channel" binding:"omitempty,oneof=email sms whatsapp"`
 }
 
 type ResetPasswordPayload struct {
+	Email           string `json:"email" binding:"omitempty,email,max=255"` // Optional, the user is found by the token when it is missing
 	Password        string `json:"password" binding:"required,min=8"`
 	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=Password"` // Valida en el backend
 	Token           string `json:"token" binding:"required"`
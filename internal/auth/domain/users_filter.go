@@ -0,0 +1,123 @@
+package authdomain
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultUsersPageSize = 20
+	MaxUsersPageSize     = 100
+)
+
+// userSortColumns maps the accepted sort keys to their columns
+var userSortColumns = map[string]string{
+	"created_at": "users.created_at",
+	"first_name": "users.first_name",
+	"last_name":  "users.last_name",
+	"email":      "users.email",
+}
+
+// UserListQuery is bound from the admin listing query string
+type UserListQuery struct {
+	Role      string `form:"role"`
+	Validated *bool  `form:"validated"`
+	Status    string `form:"status" binding:"omitempty,oneof=Active Blocked"`
+	Category  string `form:"category" binding:"omitempty,oneof=VIP Regular New AtRisk"`
+	BranchID  string `form:"branch_id" binding:"omitempty,uuid"`
+	Search    string `form:"q" binding:"max=200"`
+	Sort      string `form:"sort" binding:"omitempty,oneof=created_at -created_at first_name -first_name last_name -last_name email -email"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type UserFilter struct {
+	RoleName  string
+	Validated *bool
+	Status    ClientStatusEnum
+	Category  ClientCategoryEnum
+	BranchID  *uuid.UUID
+	Search    string
+	Sort      string
+	Page      int
+	PageSize  int
+	// Scoped limits the page to the users of BranchScope, an empty scope matches nobody
+	Scoped      bool
+	BranchScope []uuid.UUID
+}
+
+// NewUserFilter maps the query string into a filter with the pagination defaults applied
+func NewUserFilter(query UserListQuery) (UserFilter, error) {
+	filter := UserFilter{
+		RoleName:  query.Role,
+		Validated: query.Validated,
+		Status:    ClientStatusEnum(query.Status),
+		Category:  ClientCategoryEnum(query.Category),
+		Search:    query.Search,
+		Sort:      query.Sort,
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+	}
+	if query.BranchID != "" {
+		branchID, err := uuid.Parse(query.BranchID)
+		if err != nil {
+			return filter, err
+		}
+		filter.BranchID = &branchID
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultUsersPageSize
+	}
+	if filter.PageSize > MaxUsersPageSize {
+		filter.PageSize = MaxUsersPageSize
+	}
+	return filter, nil
+}
+
+func (f UserFilter) Offset() int {
+	return (f.Page - 1) * f.PageSize
+}
+
+// OrderBy returns the ORDER BY clause for the sort key, newest first by default.
+// The user id breaks ties so pages are stable.
+func (f UserFilter) OrderBy() string {
+	key, direction := strings.TrimPrefix(f.Sort, "-"), "ASC"
+	if strings.HasPrefix(f.Sort, "-") {
+		direction = "DESC"
+	}
+	column, ok := userSortColumns[key]
+	if !ok {
+		column, direction = userSortColumns["created_at"], "DESC"
+	}
+	return column + " " + direction + ", users.user_id"
+}
+
+// SearchQuery turns the free text search into a prefix tsquery where every word must match.
+// It returns an empty string when there is nothing to search for.
+func (f UserFilter) SearchQuery() string {
+	var terms []string
+	for _, word := range strings.Fields(f.Search) {
+		term := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '@' || r == '.' || r == '-' || r == '_' || r == '+' {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, word)
+		if term != "" {
+			terms = append(terms, "'"+term+"':*")
+		}
+	}
+	return strings.Join(terms, " & ")
+}
+
+type UserPage struct {
+	Users    []Users `json:"users"`
+	Total    int64   `json:"total"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"page_size"`
+}
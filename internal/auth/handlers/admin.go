@@ -0,0 +1,285 @@
+package authhandlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+// @Summary		List users
+// @Description	Returns a page of users. Staff below super_admin only see the staff of their branches and the clients who checked in at them. Filters combine with AND; q searches name, email, phone and identity document by word prefix. Sort accepts created_at, first_name, last_name or email, prefixed with - for descending order (default -created_at).
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			role		query		string					false	"Role name"
+// @Param			validated	query		bool					false	"Validation state"
+// @Param			status		query		string					false	"Client status (Active, Blocked)"
+// @Param			category	query		string					false	"Client category (VIP, Regular, New, AtRisk)"
+// @Param			branch_id	query		string					false	"Branch the staff member is assigned to"
+// @Param			q			query		string					false	"Search text"
+// @Param			sort		query		string					false	"Sort key"
+// @Param			page		query		int						false	"Page number, starting at 1"
+// @Param			page_size	query		int						false	"Page size, up to 100 (default 20)"
+// @Success		200			{object}	map[string]interface{}	"users page"
+// @Failure		400			{object}	map[string]interface{}	"invalid filter"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/users [get]
+func (h *AuthHandlers) listUsersHandler(c *gin.Context) {
+	var query authdomain.UserListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	filter, err := authdomain.NewUserFilter(query)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	actor := h.services.UserServices.GetUserFromContext(c)
+	filter.BranchScope, filter.Scoped, err = h.services.BranchServices.BranchScope(ctx, actor)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+
+	page, err := h.services.UserServices.List(ctx, filter)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary		Get user
+// @Description	Returns a user with its role, client profile, instructor profile and assigned branches. Staff below super_admin can only get the users of their branches.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			user_id	path		string					true	"User ID"
+// @Success		200		{object}	map[string]interface{}	"user and branch_ids"
+// @Failure		400		{object}	map[string]interface{}	"invalid user id"
+// @Failure		403		{object}	map[string]interface{}	"forbidden"
+// @Failure		404		{object}	map[string]interface{}	"user not found"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/users/{user_id} [get]
+func (h *AuthHandlers) getUserHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user, err := h.services.UserServices.GetByID(ctx, userID)
+	if err != nil {
+		switch err {
+		case shared_errors.ErrNotFound:
+			h.services.LogErrors.NotFoundResponse(c)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+
+	instructor, err := h.services.InstructorServices.GetByUserID(ctx, userID)
+	switch err {
+	case nil:
+		user.InstructorProfile = instructor
+	case shared_errors.ErrNotFound:
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+
+	branchIDs, err := h.services.BranchServices.GetStaffBranchIDs(ctx, userID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"user":       user,
+		"branch_ids": branchIDs,
+	})
+}
+
+// @Summary		Block client
+// @Description	Blocks a client with a mandatory justification and revokes all of its sessions. Blocked clients are refused at login, check-in and booking with code CLIENT_BLOCKED.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			user_id	path		string							true	"User ID"
+// @Param			payload	body		authdomain.ClientStatusPayload	true	"Justification"
+// @Success		200		{object}	authdomain.ClientStatusHistory	"status change"
+// @Failure		400		{object}	map[string]interface{}			"bad request or user is not a client"
+// @Failure		403		{object}	map[string]interface{}			"forbidden"
+// @Failure		404		{object}	map[string]interface{}			"user not found"
+// @Failure		409		{object}	map[string]interface{}			"client is already blocked"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/admin/users/{user_id}/block [post]
+func (h *AuthHandlers) blockClientHandler(c *gin.Context) {
+	h.setClientStatus(c, authdomain.ClientStatusBlocked)
+}
+
+// @Summary		Unblock client
+// @Description	Restores a blocked client with a mandatory justification.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			user_id	path		string							true	"User ID"
+// @Param			payload	body		authdomain.ClientStatusPayload	true	"Justification"
+// @Success		200		{object}	authdomain.ClientStatusHistory	"status change"
+// @Failure		400		{object}	map[string]interface{}			"bad request or user is not a client"
+// @Failure		403		{object}	map[string]interface{}			"forbidden"
+// @Failure		404		{object}	map[string]interface{}			"user not found"
+// @Failure		409		{object}	map[string]interface{}			"client is not blocked"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/admin/users/{user_id}/unblock [post]
+func (h *AuthHandlers) unblockClientHandler(c *gin.Context) {
+	h.setClientStatus(c, authdomain.ClientStatusActive)
+}
+
+// @Summary		Client status history
+// @Description	Returns every block and unblock of a client, newest first, with the staff member who made it and the justification.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			user_id	path		string					true	"User ID"
+// @Success		200		{object}	map[string]interface{}	"history"
+// @Failure		400		{object}	map[string]interface{}	"invalid user id"
+// @Failure		403		{object}	map[string]interface{}	"forbidden"
+// @Failure		404		{object}	map[string]interface{}	"user not found"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/users/{user_id}/status-history [get]
+func (h *AuthHandlers) clientStatusHistoryHandler(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	history, err := h.services.UserServices.StatusHistory(c.Request.Context(), userID)
+	if err != nil {
+		h.clientStatusErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+	})
+}
+
+// @Summary		Unlock account
+// @Description	Lifts the delays and lockouts caused by failed login, activation, password reset and two-factor attempts on the account.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Param			user_id	path	string	true	"User ID"
+// @Success		204		"Account unlocked. No content returned."
+// @Failure		400		{object}	map[string]interface{}	"invalid user id"
+// @Failure		403		{object}	map[string]interface{}	"forbidden"
+// @Failure		404		{object}	map[string]interface{}	"user not found"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/users/{user_id}/unlock [post]
+func (h *AuthHandlers) unlockUserHandler(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	staff := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.LockoutServices.Unlock(c.Request.Context(), userID, staff.UserID); err != nil {
+		h.clientStatusErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.unlock",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   userID.String(),
+	})
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Account lockouts
+// @Description	Returns every time the account was locked after too many failed attempts, newest first, and who unlocked it.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			user_id	path		string					true	"User ID"
+// @Success		200		{object}	map[string]interface{}	"lockouts"
+// @Failure		400		{object}	map[string]interface{}	"invalid user id"
+// @Failure		403		{object}	map[string]interface{}	"forbidden"
+// @Failure		404		{object}	map[string]interface{}	"user not found"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/users/{user_id}/lockouts [get]
+func (h *AuthHandlers) userLockoutsHandler(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	lockouts, err := h.services.LockoutServices.ListLockouts(c.Request.Context(), userID)
+	if err != nil {
+		h.clientStatusErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"lockouts": lockouts,
+	})
+}
+
+func (h *AuthHandlers) setClientStatus(c *gin.Context, status authdomain.ClientStatusEnum) {
+	var payload authdomain.ClientStatusPayload
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	staff := h.services.UserServices.GetUserFromContext(c)
+	change := &authdomain.ClientStatusHistory{
+		UserID:        userID,
+		NewStatus:     status,
+		Justification: strings.TrimSpace(payload.Justification),
+		ChangedBy:     &staff.UserID,
+	}
+	if err := h.services.UserServices.SetClientStatus(c.Request.Context(), change); err != nil {
+		h.clientStatusErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.status_change",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   userID.String(),
+		Before:     gin.H{"status": change.PreviousStatus},
+		After:      gin.H{"status": change.NewStatus, "justification": change.Justification},
+	})
+	c.JSON(http.StatusOK, change)
+}
+
+func (h *AuthHandlers) clientStatusErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, authdomain.ErrStatusUnchanged):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, authdomain.ErrNotClient):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,134 @@
+package authhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+// @Summary		Change my password
+// @Description	Replaces the password of the authenticated user after checking the current one. Every other session is signed out; the current one stays active.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body	authdomain.ChangePasswordPayload	true	"Current and new password"
+// @Success		204		"Password changed. No content returned."
+// @Failure		400		{object}	map[string]interface{}	"invalid payload or wrong current password"
+// @Failure		401		{object}	map[string]interface{}	"unauthorized"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/user/me/password [post]
+func (h *AuthHandlers) changePasswordHandler(c *gin.Context) {
+	var payload authdomain.ChangePasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	sessionID, _ := c.Value("session_id").(uuid.UUID)
+	if err := h.services.AuthServices.ChangePassword(c.Request.Context(), user, sessionID, payload); err != nil {
+		h.credentialsErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.password_change",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   user.UserID.String(),
+	})
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Request an email change
+// @Description	Sends a confirmation code to the new address. The account keeps its current email until the code is confirmed.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		authdomain.ChangeEmailPayload	true	"New email and current password"
+// @Success		202		{object}	map[string]interface{}			"confirmation code sent"
+// @Failure		400		{object}	map[string]interface{}			"invalid payload, wrong current password or same email"
+// @Failure		401		{object}	map[string]interface{}			"unauthorized"
+// @Failure		409		{object}	map[string]interface{}			"email already in use"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/user/me/email [post]
+func (h *AuthHandlers) requestEmailChangeHandler(c *gin.Context) {
+	var payload authdomain.ChangeEmailPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.AuthServices.RequestEmailChange(c.Request.Context(), user, payload); err != nil {
+		h.credentialsErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "confirmation code sent",
+	})
+}
+
+// @Summary		Confirm an email change
+// @Description	Switches the account email to the pending address once the code sent to it is confirmed. The previous address is notified of the change.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		authdomain.CodePayload	true	"Confirmation code"
+// @Success		200		{object}	map[string]interface{}	"email"
+// @Failure		400		{object}	map[string]interface{}	"invalid or expired code, the code is discarded after too many wrong tries"
+// @Failure		401		{object}	map[string]interface{}	"unauthorized"
+// @Failure		409		{object}	map[string]interface{}	"email already in use"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/user/me/email/confirm [post]
+func (h *AuthHandlers) confirmEmailChangeHandler(c *gin.Context) {
+	var payload authdomain.CodePayload
+	ctx := c.Request.Context()
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	previousEmail := user.Email
+	change, err := h.services.AuthServices.ConfirmEmailChange(ctx, user, payload.Code)
+	if err != nil {
+		h.credentialsErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.email_change",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   user.UserID.String(),
+		Before:     gin.H{"email": previousEmail},
+		After:      gin.H{"email": user.Email},
+	})
+	// the change is already committed, a failed notification must not fail the request
+	if err := h.services.AuthServices.NotifyEmailChanged(ctx, user, change, previousEmail); err != nil {
+		h.services.Logger.Errorw("error notifying previous email of the change", "error", err, "user_id", user.UserID)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"email": user.Email,
+	})
+}
+
+func (h *AuthHandlers) credentialsErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.BadRequestResponse(c, errors.New("invalid or expired code"))
+	case errors.Is(err, shared_errors.ErrConflict):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, authdomain.ErrWrongPassword),
+		errors.Is(err, authdomain.ErrSameEmail),
+		errors.Is(err, authdomain.ErrInvalidOTP):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
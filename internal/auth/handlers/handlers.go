@@ -1,24 +1,27 @@
 package authhandlers
 
 import (
-	"context"
-	"crypto/sha256"
-	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	appservices "github.com/vitalfit/api/internal/app/services"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
 	authdomain "github.com/vitalfit/api/internal/auth/domain"
 	shared_errors "github.com/vitalfit/api/internal/shared/errors"
 	"github.com/vitalfit/api/internal/shared/middleware/auth"
-	"github.com/vitalfit/api/pkg/mailer"
+	"github.com/vitalfit/api/pkg/notifier"
 	otp "github.com/vitalfit/api/pkg/otp"
 )
 
 type AuthHandlersInterface interface {
 	AuthRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
 	UserRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+	InstructorRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+	AdminRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
 }
 
 type AuthHandlers struct {
@@ -30,7 +33,7 @@ func NewAuthHandlers(services appservices.Services) *AuthHandlers {
 }
 
 // @Summary		Register New User
-// @Description	Register a new user in the system with client role
+// @Description	Register a new user in the system with client role. The activation code goes by email unless channel asks for sms or whatsapp, which need a phone in international format.
 // @Tags			Auth
 // @Accept			json
 // @Produce		json
@@ -64,6 +67,7 @@ func (h *AuthHandlers) registerUserClientHandler(c *gin.Context) {
 		BirthDate:         birthdate,
 		Gender:            authdomain.GenderEnum(payload.Gender),
 		ProfilePictureURL: payload.ProfilePictureURL,
+		PreferredLanguage: preferredLanguage(c, payload.PreferredLanguage),
 	}
 
 	if err := user.PasswordHash.Set(payload.Password); err != nil {
@@ -71,17 +75,15 @@ func (h *AuthHandlers) registerUserClientHandler(c *gin.Context) {
 		return
 	}
 
-	//store the user
+	//store the user, the invitation email is queued with it
 	key, err := otp.GenerateCode(6)
 	if err != nil {
 		h.services.InternalServerError(c, err)
 		return
 	}
-	hash := sha256.Sum256([]byte(key))
-	hashedKey := hex.EncodeToString(hash[:])
-	if err := h.services.AuthServices.RegisterUserClient(ctx, user, hashedKey); err != nil {
+	if err := h.services.AuthServices.RegisterUserClient(ctx, user, key, notifier.Channel(payload.Channel)); err != nil {
 		switch err {
-		case shared_errors.ErrNotFound:
+		case shared_errors.ErrNotFound, notifier.ErrInvalidPhone, notifier.ErrChannelUnavailable:
 			h.services.LogErrors.BadRequestResponse(c, err)
 		case shared_errors.ErrConflict:
 			h.services.LogErrors.ConflictResponse(c, err)
@@ -91,20 +93,21 @@ func (h *AuthHandlers) registerUserClientHandler(c *gin.Context) {
 		return
 	}
 
-	status, err := h.registerEmail(ctx, user, key)
-	if err != nil {
-		h.services.LogErrors.InternalServerError(c, err)
-		return
-	}
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "user created",
-		"status":  status,
-		"code":    key,
 	})
 }
 
+// preferredLanguage is the language asked for in the payload, else the first supported one of the Accept-Language header
+func preferredLanguage(c *gin.Context, requested string) string {
+	if requested != "" {
+		return requested
+	}
+	return authdomain.LanguageFromHeader(c.GetHeader("Accept-Language"))
+}
+
 // @Summary		Register New User Staff
-// @Description	Register a new user in the system with and specific role
+// @Description	Register a new user in the system with and specific role. Staff below super_admin must be assigned to at least one branch the requester can manage. Instructors get a profile with the given speciality and biography.
 // @Tags			Auth
 // @Security		ApiKeyAuth
 // @Accept			json
@@ -112,6 +115,7 @@ func (h *AuthHandlers) registerUserClientHandler(c *gin.Context) {
 // @Param			user	body		authdomain.CreateUserStaffPayload	true	"Register user data"
 // @Success		201		{object}	map[string]interface{}				"message: user created"
 // @Failure		400		{object}	map[string]interface{}				"bad response"
+// @Failure		403		{object}	map[string]interface{}				"role is above the level of the requester"
 // @Failure		500		{object}	map[string]interface{}				"internal server error"
 // @Router			/auth/register-staff [post]
 func (h *AuthHandlers) registerUserStaffHandler(c *gin.Context) {
@@ -130,6 +134,11 @@ func (h *AuthHandlers) registerUserStaffHandler(c *gin.Context) {
 		}
 	}
 
+	branchIDs, ok := h.staffBranches(c, payload)
+	if !ok {
+		return
+	}
+
 	user := &authdomain.Users{
 		FirstName:         payload.FirstName,
 		LastName:          payload.LastName,
@@ -139,6 +148,11 @@ func (h *AuthHandlers) registerUserStaffHandler(c *gin.Context) {
 		BirthDate:         birthdate,
 		Gender:            authdomain.GenderEnum(payload.Gender),
 		ProfilePictureURL: payload.ProfilePictureURL,
+		PreferredLanguage: preferredLanguage(c, payload.PreferredLanguage),
+		InstructorProfile: &authdomain.Instructors{
+			Speciality: payload.Speciality,
+			Biography:  payload.Biography,
+		},
 	}
 
 	if err := user.PasswordHash.Set(payload.Password); err != nil {
@@ -146,18 +160,19 @@ func (h *AuthHandlers) registerUserStaffHandler(c *gin.Context) {
 		return
 	}
 
-	//store the user
+	//store the user with its branches, the invitation email is queued with it
 	key, err := otp.GenerateCode(6)
 	if err != nil {
 		h.services.InternalServerError(c, err)
 		return
 	}
-	hash := sha256.Sum256([]byte(key))
-	hashedKey := hex.EncodeToString(hash[:])
-	if err := h.services.AuthServices.RegisterUserStaff(ctx, user, hashedKey, payload.RoleName); err != nil {
+	requester := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.AuthServices.RegisterUserStaff(ctx, requester, user, key, payload.RoleName, branchIDs, notifier.Channel(payload.Channel)); err != nil {
 		switch err {
-		case shared_errors.ErrNotFound:
+		case shared_errors.ErrNotFound, notifier.ErrInvalidPhone, notifier.ErrChannelUnavailable:
 			h.services.LogErrors.BadRequestResponse(c, err)
+		case authdomain.ErrRoleEscalation:
+			h.services.LogErrors.ForbiddenResponse(c)
 		case shared_errors.ErrConflict:
 			h.services.LogErrors.ConflictResponse(c, err)
 		default:
@@ -166,40 +181,42 @@ func (h *AuthHandlers) registerUserStaffHandler(c *gin.Context) {
 		return
 	}
 
-	//send main
-	status, err := h.registerEmail(ctx, user, key)
-	if err != nil {
-		h.services.LogErrors.InternalServerError(c, err)
-		return
-	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.register_staff",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   user.UserID.String(),
+		After: gin.H{
+			"email":    user.Email,
+			"role":     payload.RoleName,
+			"branches": branchIDs,
+		},
+	})
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "user created",
-		"status":  status,
-		"code":    key,
 	})
 }
 
 // @Summary		Activate user account
-// @Description	Activates a user's account using the invitation code/token.
+// @Description	Activates a user's account using the invitation code. The email is optional, without it the account is the one the code was sent to. Wrong codes are counted: the code is discarded after too many tries, and repeated failures delay and then temporarily lock further attempts for the email from the same address, and past a higher limit from every address. Attempts without an email share that higher limit.
 // @Tags			User
 // @Accept			json
 // @Produce		json
-// @Param			payload	body	authdomain.CodePayload	true	"Activation Code"
+// @Param			payload	body	authdomain.ActivatePayload	true	"Activation code and optional email"
 // @Success		204		"User successfully activated. No content returned."
-// @Failure		400		{object}	map[string]interface{}	"Bad request (e.g., invalid JSON payload)"
-// @Failure		404		{object}	map[string]interface{}	"Code is invalid or expired (handled by the service layer returning ErrNotFound)"
+// @Failure		400		{object}	map[string]interface{}	"Bad request (invalid JSON payload, or the code is invalid or expired)"
+// @Failure		429		{object}	map[string]interface{}	"Too many failed attempts (code TOO_MANY_ATTEMPTS or ACCOUNT_LOCKED), see Retry-After"
 // @Failure		500		{object}	map[string]interface{}	"Internal server error (e.g., database connection issue)"
 // @Router			/auth/activate [put]
 func (h *AuthHandlers) activateUserHandler(c *gin.Context) {
-	var payload authdomain.CodePayload
+	var payload authdomain.ActivatePayload
 	ctx := c.Request.Context()
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		h.services.LogErrors.BadRequestResponse(c, err)
 		return
 	}
-	if err := h.services.AuthServices.Activate(ctx, payload.Code); err != nil {
-		h.services.LogErrors.InternalServerError(c, err)
+	if err := h.services.AuthServices.Activate(ctx, payload, c.ClientIP()); err != nil {
+		h.otpErrorResponse(c, err)
 		return
 	}
 
@@ -208,15 +225,18 @@ func (h *AuthHandlers) activateUserHandler(c *gin.Context) {
 }
 
 // @Summary		Logs in a user and issues a JWT token
-// @Description	Authenticates the user with email and password, returning an access token upon success.
+// @Description	Authenticates the user with email and password, returning a short-lived access token and a refresh token upon success. Accounts with two-factor enabled get a 202 with a challenge token instead, to be completed at /auth/login/mfa.
 // @Tags			Auth
 // @Accept			json
 // @Produce		json
-// @Param			credentials	body		authdomain.CreateUserTokenPayload	true		"User login credentials (email and password)"
-// @Success		200			{object}	map[string]string					"token"		"Successfully generated JWT access token"
+// @Param			credentials	body		authdomain.CreateUserTokenPayload	true	"User login credentials (email and password)"
+// @Success		200			{object}	authdomain.TokenPair				"Successfully generated access and refresh tokens"
+// @Success		202			{object}	authdomain.MFAChallenge				"Two-factor code required"
 // @Failure		400			{object}	map[string]string					"error":	"Invalid request body"
-// @Failure		401			{object}	map[string]string					"error":	"Unauthorized"						"Invalid credentials (password mismatch)"
-// @Failure		404			{object}	map[string]string					"error":	"not found"							"User with the given email not found"
+// @Failure		401			{object}	map[string]string					"error":	"Unauthorized"		"Invalid credentials (password mismatch)"
+// @Failure		403			{object}	map[string]string					"error":	"client is blocked"	"The client was blocked by staff (code CLIENT_BLOCKED)"
+// @Failure		404			{object}	map[string]string					"error":	"not found"			"User with the given email not found"
+// @Failure		429			{object}	map[string]interface{}				"Too many failed attempts for the email (code TOO_MANY_ATTEMPTS or ACCOUNT_LOCKED), see Retry-After"
 // @Failure		500			{object}	map[string]string					"error":	"the server encountered a problem"	"Internal server error during token generation or hashing"
 // @Router			/auth/login [post]
 func (h *AuthHandlers) loginHandler(c *gin.Context) {
@@ -227,33 +247,170 @@ func (h *AuthHandlers) loginHandler(c *gin.Context) {
 		return
 	}
 
-	user, err := h.services.UserServices.GetByEmail(ctx, payload.Email)
+	user, err := h.services.AuthServices.Authenticate(ctx, payload, c.ClientIP())
 	if err != nil {
-		switch err {
-		case shared_errors.ErrNotFound:
+		switch {
+		case errors.Is(err, shared_errors.ErrNotFound):
 			h.services.LogErrors.NotFoundResponse(c)
+		case errors.Is(err, authdomain.ErrInvalidCredentials):
+			h.services.LogErrors.UnauthorizedErrorResponse(c, err)
 		default:
-			h.services.LogErrors.InternalServerError(c, err)
+			h.loginErrorResponse(c, err)
 		}
 		return
 	}
 
-	match, err := user.PasswordHash.Matches(payload.Password)
-	if err != nil || !match {
-		h.services.LogErrors.UnauthorizedErrorResponse(c, err)
+	if user.MFAEnabled {
+		challenge, err := h.services.AuthServices.NewMFAChallenge(user)
+		if err != nil {
+			h.loginErrorResponse(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	tokens, err := h.services.AuthServices.IssueTokens(ctx, user, sessionMeta(c))
+	if err != nil {
+		h.loginErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+
+}
+
+// @Summary		Completes a two-factor login
+// @Description	Exchanges the challenge token returned by /auth/login and a TOTP or recovery code for an access token and a refresh token. Each code is accepted only once.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		authdomain.MFALoginPayload	true	"Challenge token and two-factor code"
+// @Success		200		{object}	authdomain.TokenPair		"Successfully generated access and refresh tokens"
+// @Failure		400		{object}	map[string]interface{}		"Invalid request body"
+// @Failure		401		{object}	map[string]interface{}		"Challenge token expired or invalid, or wrong code"
+// @Failure		403		{object}	map[string]interface{}		"Client is blocked (code CLIENT_BLOCKED)"
+// @Failure		429		{object}	map[string]interface{}		"Too many wrong codes (code TOO_MANY_ATTEMPTS or ACCOUNT_LOCKED), see Retry-After"
+// @Failure		500		{object}	map[string]interface{}		"Internal server error"
+// @Router			/auth/login/mfa [post]
+func (h *AuthHandlers) loginMFAHandler(c *gin.Context) {
+	var payload authdomain.MFALoginPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
 		return
 	}
 
-	token, err := h.services.AuthServices.GenerateToken(user)
+	tokens, err := h.services.AuthServices.CompleteMFALogin(c.Request.Context(), payload, sessionMeta(c))
 	if err != nil {
+		h.loginErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandlers) loginErrorResponse(c *gin.Context, err error) {
+	var locked *authdomain.LockedError
+	switch {
+	case errors.As(err, &locked):
+		h.services.LogErrors.TooManyAttemptsResponse(c, locked.RetryAfter(time.Now()), locked.Lockout)
+	case errors.Is(err, shared_errors.ErrClientBlocked):
+		h.services.LogErrors.ClientBlockedResponse(c)
+	case errors.Is(err, shared_errors.ErrInvalidToken),
+		errors.Is(err, shared_errors.ErrNotFound),
+		errors.Is(err, authdomain.ErrInvalidMFACode),
+		errors.Is(err, authdomain.ErrMFANotEnabled):
+		h.services.LogErrors.UnauthorizedErrorResponse(c, err)
+	default:
 		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
+
+// @Summary		Refresh the session tokens
+// @Description	Exchanges a refresh token for a new access token and a new refresh token. The presented refresh token is revoked; presenting it again revokes the whole session.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		authdomain.RefreshTokenPayload	true	"Refresh token"
+// @Success		200		{object}	authdomain.TokenPair			"New token pair"
+// @Failure		400		{object}	map[string]interface{}			"Invalid request body"
+// @Failure		401		{object}	map[string]interface{}			"Refresh token is invalid, expired or was already used"
+// @Failure		403		{object}	map[string]interface{}			"Client is blocked (code CLIENT_BLOCKED)"
+// @Failure		500		{object}	map[string]interface{}			"Internal server error"
+// @Router			/auth/refresh [post]
+func (h *AuthHandlers) refreshTokenHandler(c *gin.Context) {
+	var payload authdomain.RefreshTokenPayload
+	ctx := c.Request.Context()
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-	})
+	tokens, err := h.services.AuthServices.RefreshTokens(ctx, payload.RefreshToken, sessionMeta(c))
+	if err != nil {
+		switch err {
+		case shared_errors.ErrNotFound, shared_errors.ErrInvalidToken, shared_errors.ErrTokenReused:
+			h.services.LogErrors.UnauthorizedErrorResponse(c, err)
+		case shared_errors.ErrClientBlocked:
+			h.services.LogErrors.ClientBlockedResponse(c)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary		Log out the current session
+// @Description	Revokes the session the given refresh token belongs to.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body	authdomain.RefreshTokenPayload	true	"Refresh token"
+// @Success		204		"Session revoked. No content returned."
+// @Failure		400		{object}	map[string]interface{}	"Invalid request body"
+// @Failure		401		{object}	map[string]interface{}	"Refresh token is invalid"
+// @Failure		500		{object}	map[string]interface{}	"Internal server error"
+// @Router			/auth/logout [post]
+func (h *AuthHandlers) logoutHandler(c *gin.Context) {
+	var payload authdomain.RefreshTokenPayload
+	ctx := c.Request.Context()
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.AuthServices.Logout(ctx, payload.RefreshToken); err != nil {
+		switch err {
+		case shared_errors.ErrNotFound:
+			h.services.LogErrors.UnauthorizedErrorResponse(c, err)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
 
+// @Summary		Log out all devices
+// @Description	Revokes every session of the authenticated user.
+// @Tags			Auth
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		204	"All sessions revoked. No content returned."
+// @Failure		401	{object}	map[string]interface{}	"Missing or invalid JWT token"
+// @Failure		500	{object}	map[string]interface{}	"Internal server error"
+// @Router			/auth/logout-all [post]
+func (h *AuthHandlers) logoutAllHandler(c *gin.Context) {
+	user := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.AuthServices.LogoutAll(c.Request.Context(), user.UserID); err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
 }
 
 // @Summary		Get current user profile
@@ -272,14 +429,14 @@ func (h *AuthHandlers) whoami(c *gin.Context) {
 }
 
 // @Summary		Solicitar token de reseteo de contraseña
-// @Description	Envía un código OTP al correo electrónico proporcionado para iniciar el proceso de reseteo de contraseña.
+// @Description	Envía un código OTP al correo electrónico proporcionado para iniciar el proceso de reseteo de contraseña. Con channel sms o whatsapp el código se envía al teléfono de la cuenta.
 // @Tags			Auth
 // @Accept			json
 // @Produce		json
 // @Param			email	body		authdomain.ForgotPasswordPayload	true	"Estructura que contiene el correo del usuario"
 // @Success		200		{object}	map[string]interface{}				"Si el correo existe, el proceso de token ha sido exitoso (por seguridad, el mensaje no confirma la existencia del correo)."
 // @Failure		400		{object}	map[string]interface{}				"Bad Request - Datos de entrada inválidos (ej. formato de email incorrecto)"
-// @Failure		500		{object}	map[string]interface{}				"Internal Server Error - Error al generar el token, al acceder a la DB, o al enviar el correo."
+// @Failure		500		{object}	map[string]interface{}				"Internal Server Error - Error al generar el token o al acceder a la DB. El correo se envía en segundo plano."
 // @Router			/auth/password/forgot [post]
 func (h *AuthHandlers) forgotPasswordHandler(c *gin.Context) {
 	var payload authdomain.ForgotPasswordPayload
@@ -294,9 +451,6 @@ func (h *AuthHandlers) forgotPasswordHandler(c *gin.Context) {
 		h.services.InternalServerError(c, err)
 		return
 	}
-	hash := sha256.Sum256([]byte(key))
-	hashedKey := hex.EncodeToString(hash[:])
-
 	user, err := h.services.UserServices.GetByEmail(ctx, payload.Email)
 	if err != nil {
 		switch err {
@@ -308,84 +462,110 @@ func (h *AuthHandlers) forgotPasswordHandler(c *gin.Context) {
 		return
 	}
 
-	//Sends otp key to user if exists
-	err = h.services.AuthServices.CreatePasswordResetToken(ctx, user.Email, hashedKey)
-	if err != nil {
+	//stores the otp key and queues the email carrying it
+	if err := h.services.AuthServices.CreatePasswordResetToken(ctx, user, key, notifier.Channel(payload.Channel)); err != nil {
 		switch err {
-		case shared_errors.ErrNotFound:
-			h.services.LogErrors.NotFoundResponse(c)
+		case notifier.ErrInvalidPhone, notifier.ErrChannelUnavailable:
+			h.services.LogErrors.BadRequestResponse(c, err)
 		default:
 			h.services.LogErrors.InternalServerError(c, err)
 		}
 		return
 	}
-	//send email -> error -> rollback
-	status, err := h.services.AuthServices.MailSender(ctx, user, key, mailer.UserResetPwsTemplate)
-	if err != nil {
-		h.services.Logger.Errorw("error sending reset token password to email", "error", err)
-		if err := h.services.AuthServices.DeleteResetToken(ctx, user.UserID); err != nil {
-			h.services.Logger.Errorw("error deleting user reset token password ", "error", err)
-			return
-		}
-		h.services.LogErrors.InternalServerError(c, err)
-		return
-	}
 
-	c.JSON(status, gin.H{
+	c.JSON(http.StatusOK, gin.H{
 		"message": "reset key created",
-		"code":    key,
 	})
 
 }
 
 // @Summary		Execute Password Reset
-// @Description	Finalizes the password reset process by validating the token and updating the user's password hash.
+// @Description	Finalizes the password reset process by validating the token and updating the user's password hash. The email is optional, without it the account is the one the token was sent to. Wrong tokens are counted: the token is discarded after too many tries, and repeated failures delay and then temporarily lock further attempts for the email from the same address, and past a higher limit from every address. Attempts without an email share that higher limit.
 // @Tags			Auth
 // @Accept			json
 // @Produce		json
-// @Param			body	body		authdomain.ResetPasswordPayload	true	"Reset token and new password data (must include password confirmation)."
+// @Param			body	body		authdomain.ResetPasswordPayload	true	"Reset token, optional email and new password data (must include password confirmation)."
 // @Success		200		{object}	map[string]interface{}			"Password updated successfully."
 // @Failure		400		{object}	map[string]interface{}			"Bad Request - Invalid data (expired token, token not found, or passwords do not match)."
+// @Failure		429		{object}	map[string]interface{}			"Too many failed attempts (code TOO_MANY_ATTEMPTS or ACCOUNT_LOCKED), see Retry-After"
 // @Failure		500		{object}	map[string]interface{}			"Internal Server Error - Error while hashing the password or executing the DB transaction."
 // @Router			/auth/password/reset [post]
 func (h *AuthHandlers) resetPasswordHandler(c *gin.Context) {
 	var payload authdomain.ResetPasswordPayload
-	var user authdomain.Users
 	ctx := c.Request.Context()
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		h.services.LogErrors.BadRequestResponse(c, err)
 		return
 	}
 
-	if err := user.PasswordHash.Set(payload.Password); err != nil {
-		h.services.LogErrors.InternalServerError(c, err)
+	user, err := h.services.AuthServices.ResetPassword(ctx, payload, c.ClientIP())
+	if err != nil {
+		h.otpErrorResponse(c, err)
 		return
 	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.password_reset",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   user.UserID.String(),
+		ActorID:    &user.UserID,
+	})
 
-	err := h.services.AuthServices.ResetPassword(ctx, payload.Token, &user)
-	if err != nil {
-		switch err {
-		case shared_errors.ErrNotFound:
-			h.services.LogErrors.BadRequestResponse(c, err)
-		default:
-			h.services.LogErrors.InternalServerError(c, err)
-		}
-		return
+}
+
+// otpErrorResponse answers the failures of the endpoints that take a one time code
+func (h *AuthHandlers) otpErrorResponse(c *gin.Context, err error) {
+	var locked *authdomain.LockedError
+	switch {
+	case errors.As(err, &locked):
+		h.services.LogErrors.TooManyAttemptsResponse(c, locked.RetryAfter(time.Now()), locked.Lockout)
+	case errors.Is(err, authdomain.ErrInvalidOTP):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
 	}
+}
 
+func sessionMeta(c *gin.Context) authdomain.SessionMeta {
+	return authdomain.SessionMeta{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
 }
 
-func (h *AuthHandlers) registerEmail(ctx context.Context, user *authdomain.Users, key string) (int, error) {
-	status, err := h.services.AuthServices.MailSender(ctx, user, key, mailer.UserWelcomeTemplate)
-	if err != nil {
-		h.services.Logger.Errorw("error sending welcome email", "error", err)
+// staffBranches validates the branches a new staff member is assigned to
+func (h *AuthHandlers) staffBranches(c *gin.Context, payload authdomain.CreateUserStaffPayload) ([]uuid.UUID, bool) {
+	if payload.RoleName != "super_admin" && len(payload.BranchIDs) == 0 {
+		h.services.LogErrors.BadRequestResponse(c, errors.New("staff must be assigned to at least one branch"))
+		return nil, false
+	}
 
-		// Attempt to rollback user creation
-		if rollbackErr := h.services.AuthServices.Delete(ctx, user.UserID); rollbackErr != nil {
-			h.services.Logger.Errorw("critical: failed to rollback user creation after email failure", "original_error", err, "rollback_error", rollbackErr, "user_id", user.UserID)
+	requester := h.services.UserServices.GetUserFromContext(c)
+	branchIDs := make([]uuid.UUID, 0, len(payload.BranchIDs))
+	for _, id := range payload.BranchIDs {
+		branchID, err := uuid.Parse(id)
+		if err != nil {
+			h.services.LogErrors.BadRequestResponse(c, err)
+			return nil, false
+		}
+		if _, err := h.services.BranchServices.GetByID(c.Request.Context(), branchID); err != nil {
+			switch err {
+			case shared_errors.ErrNotFound:
+				h.services.LogErrors.BadRequestResponse(c, fmt.Errorf("branch %s not found", branchID))
+			default:
+				h.services.LogErrors.InternalServerError(c, err)
+			}
+			return nil, false
+		}
+		allowed, err := h.services.BranchServices.CanAccessBranch(c.Request.Context(), requester, branchID)
+		if err != nil {
+			h.services.LogErrors.InternalServerError(c, err)
+			return nil, false
 		}
-		return http.StatusInternalServerError, err // Return original error
+		if !allowed {
+			h.services.LogErrors.ForbiddenResponse(c)
+			return nil, false
+		}
+		branchIDs = append(branchIDs, branchID)
 	}
-
-	return status, nil
+	return branchIDs, true
 }
@@ -0,0 +1,293 @@
+package authhandlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+// @Summary		List instructors
+// @Description	Returns the public trainer roster, optionally only the instructors of a branch or whose speciality contains the given text.
+// @Tags			Instructors
+// @Produce		json
+// @Param			branch_id	query		string					false	"Branch ID"
+// @Param			speciality	query		string					false	"Speciality"
+// @Success		200			{object}	map[string]interface{}	"instructors"
+// @Failure		400			{object}	map[string]interface{}	"invalid branch id"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/instructors [get]
+func (h *AuthHandlers) listInstructorsHandler(c *gin.Context) {
+	filter := authdomain.InstructorFilter{
+		Speciality: strings.TrimSpace(c.Query("speciality")),
+	}
+	if branch := c.Query("branch_id"); branch != "" {
+		branchID, err := uuid.Parse(branch)
+		if err != nil {
+			h.services.LogErrors.BadRequestResponse(c, err)
+			return
+		}
+		filter.BranchID = &branchID
+	}
+
+	instructors, err := h.services.InstructorServices.List(c.Request.Context(), filter)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	roster := make([]authdomain.PublicInstructor, 0, len(instructors))
+	for i := range instructors {
+		roster = append(roster, authdomain.NewPublicInstructor(&instructors[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"instructors": roster,
+	})
+}
+
+// @Summary		Get instructor
+// @Description	Returns the public profile of an instructor.
+// @Tags			Instructors
+// @Produce		json
+// @Param			instructor_id	path		string						true	"Instructor ID"
+// @Success		200				{object}	authdomain.PublicInstructor	"instructor"
+// @Failure		400				{object}	map[string]interface{}		"invalid instructor id"
+// @Failure		404				{object}	map[string]interface{}		"instructor not found"
+// @Failure		500				{object}	map[string]interface{}		"internal server error"
+// @Router			/instructors/{instructor_id} [get]
+func (h *AuthHandlers) getInstructorHandler(c *gin.Context) {
+	instructorID, err := uuid.Parse(c.Param("instructor_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	instructor, err := h.services.InstructorServices.GetByID(c.Request.Context(), instructorID)
+	if err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, authdomain.NewPublicInstructor(instructor))
+}
+
+// @Summary		Create instructor profile
+// @Description	Creates the profile of an existing user with the instructor role. New instructors registered through register-staff already get one.
+// @Tags			Instructors
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			instructor	body		authdomain.CreateInstructorPayload	true	"Instructor profile"
+// @Success		201			{object}	authdomain.PublicInstructor			"instructor created"
+// @Failure		400			{object}	map[string]interface{}				"bad request or user is not an instructor"
+// @Failure		403			{object}	map[string]interface{}				"forbidden"
+// @Failure		404			{object}	map[string]interface{}				"user not found"
+// @Failure		409			{object}	map[string]interface{}				"instructor profile already exists"
+// @Failure		500			{object}	map[string]interface{}				"internal server error"
+// @Router			/instructors [post]
+func (h *AuthHandlers) createInstructorHandler(c *gin.Context) {
+	var payload authdomain.CreateInstructorPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	instructor := &authdomain.Instructors{
+		UserID:     userID,
+		Speciality: payload.Speciality,
+		Biography:  payload.Biography,
+	}
+	if !h.canManageInstructor(c, instructor) {
+		return
+	}
+	if err := h.services.InstructorServices.Create(c.Request.Context(), instructor); err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, authdomain.NewPublicInstructor(instructor))
+}
+
+// @Summary		Update instructor profile
+// @Description	Replaces the speciality and biography of an instructor. Branch admins can only update instructors of their branches.
+// @Tags			Instructors
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			instructor_id	path		string							true	"Instructor ID"
+// @Param			instructor		body		authdomain.InstructorPayload	true	"Instructor profile"
+// @Success		200				{object}	authdomain.PublicInstructor		"instructor updated"
+// @Failure		400				{object}	map[string]interface{}			"bad request"
+// @Failure		403				{object}	map[string]interface{}			"forbidden"
+// @Failure		404				{object}	map[string]interface{}			"instructor not found"
+// @Failure		500				{object}	map[string]interface{}			"internal server error"
+// @Router			/instructors/{instructor_id} [put]
+func (h *AuthHandlers) updateInstructorHandler(c *gin.Context) {
+	var payload authdomain.InstructorPayload
+	instructorID, err := uuid.Parse(c.Param("instructor_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	instructor, err := h.services.InstructorServices.GetByID(c.Request.Context(), instructorID)
+	if err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	if !h.canManageInstructor(c, instructor) {
+		return
+	}
+	h.saveInstructor(c, instructor, payload)
+}
+
+// @Summary		Delete instructor profile
+// @Description	Removes an instructor from the public roster. The user account is kept.
+// @Tags			Instructors
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			instructor_id	path	string	true	"Instructor ID"
+// @Success		204				"Instructor deleted. No content returned."
+// @Failure		400				{object}	map[string]interface{}	"invalid instructor id"
+// @Failure		403				{object}	map[string]interface{}	"forbidden"
+// @Failure		404				{object}	map[string]interface{}	"instructor not found"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/instructors/{instructor_id} [delete]
+func (h *AuthHandlers) deleteInstructorHandler(c *gin.Context) {
+	instructorID, err := uuid.Parse(c.Param("instructor_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	instructor, err := h.services.InstructorServices.GetByID(c.Request.Context(), instructorID)
+	if err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	if !h.canManageInstructor(c, instructor) {
+		return
+	}
+	if err := h.services.InstructorServices.Delete(c.Request.Context(), instructorID); err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Get my instructor profile
+// @Description	Returns the profile of the authenticated instructor.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	authdomain.PublicInstructor	"instructor"
+// @Failure		401	{object}	map[string]interface{}		"unauthorized"
+// @Failure		404	{object}	map[string]interface{}		"user has no instructor profile"
+// @Failure		500	{object}	map[string]interface{}		"internal server error"
+// @Router			/user/instructor [get]
+func (h *AuthHandlers) myInstructorHandler(c *gin.Context) {
+	user := h.services.UserServices.GetUserFromContext(c)
+
+	instructor, err := h.services.InstructorServices.GetByUserID(c.Request.Context(), user.UserID)
+	if err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, authdomain.NewPublicInstructor(instructor))
+}
+
+// @Summary		Update my instructor profile
+// @Description	Replaces the speciality and biography of the authenticated instructor.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			instructor	body		authdomain.InstructorPayload	true	"Instructor profile"
+// @Success		200			{object}	authdomain.PublicInstructor		"instructor updated"
+// @Failure		400			{object}	map[string]interface{}			"bad request"
+// @Failure		401			{object}	map[string]interface{}			"unauthorized"
+// @Failure		404			{object}	map[string]interface{}			"user has no instructor profile"
+// @Failure		500			{object}	map[string]interface{}			"internal server error"
+// @Router			/user/instructor [put]
+func (h *AuthHandlers) updateMyInstructorHandler(c *gin.Context) {
+	var payload authdomain.InstructorPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	instructor, err := h.services.InstructorServices.GetByUserID(c.Request.Context(), user.UserID)
+	if err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	h.saveInstructor(c, instructor, payload)
+}
+
+func (h *AuthHandlers) saveInstructor(c *gin.Context, instructor *authdomain.Instructors, payload authdomain.InstructorPayload) {
+	instructor.Speciality = payload.Speciality
+	instructor.Biography = payload.Biography
+	if err := h.services.InstructorServices.Update(c.Request.Context(), instructor); err != nil {
+		h.instructorErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, authdomain.NewPublicInstructor(instructor))
+}
+
+// canManageInstructor reports whether the requester manages a branch of the instructor.
+// Instructors without branches can only be managed by super admins.
+func (h *AuthHandlers) canManageInstructor(c *gin.Context, instructor *authdomain.Instructors) bool {
+	ctx := c.Request.Context()
+	requester := h.services.UserServices.GetUserFromContext(c)
+
+	superAdmin, err := h.services.UserServices.GetRoleByName(ctx, "super_admin")
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return false
+	}
+	if requester.Role.Level >= superAdmin.Level {
+		return true
+	}
+
+	branchIDs, err := h.services.BranchServices.GetStaffBranchIDs(ctx, instructor.UserID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return false
+	}
+	for _, branchID := range branchIDs {
+		allowed, err := h.services.BranchServices.CanAccessBranch(ctx, requester, branchID)
+		if err != nil {
+			h.services.LogErrors.InternalServerError(c, err)
+			return false
+		}
+		if allowed {
+			return true
+		}
+	}
+	h.services.LogErrors.ForbiddenResponse(c)
+	return false
+}
+
+func (h *AuthHandlers) instructorErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, shared_errors.ErrConflict):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, authdomain.ErrNotInstructor):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
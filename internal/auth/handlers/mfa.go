@@ -0,0 +1,171 @@
+package authhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+)
+
+// @Summary		Get my two-factor status
+// @Description	Returns whether two-factor is enabled, whether the role of the user makes it mandatory and how many recovery codes are left.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	authdomain.MFAStatus	"Two-factor status"
+// @Failure		401	{object}	map[string]interface{}	"unauthorized"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/user/mfa [get]
+func (h *AuthHandlers) mfaStatusHandler(c *gin.Context) {
+	user := h.services.UserServices.GetUserFromContext(c)
+	status, err := h.services.MFAServices.Status(c.Request.Context(), user)
+	if err != nil {
+		h.mfaErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// @Summary		Start two-factor enrollment
+// @Description	Generates a new TOTP secret and its otpauth URI to add the account to an authenticator app. Two-factor stays disabled until the enrollment is confirmed; calling it again replaces the pending secret.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	authdomain.MFAEnrollment	"Secret and otpauth URI"
+// @Failure		400	{object}	map[string]interface{}		"two-factor is already enabled"
+// @Failure		401	{object}	map[string]interface{}		"unauthorized"
+// @Failure		500	{object}	map[string]interface{}		"internal server error"
+// @Router			/user/mfa/enroll [post]
+func (h *AuthHandlers) enrollMFAHandler(c *gin.Context) {
+	user := h.services.UserServices.GetUserFromContext(c)
+	enrollment, err := h.services.MFAServices.Enroll(c.Request.Context(), user)
+	if err != nil {
+		h.mfaErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// @Summary		Confirm two-factor enrollment
+// @Description	Enables two-factor once a code from the authenticator app is verified. The recovery codes are returned only in this response.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		authdomain.MFACodePayload	true	"Code from the authenticator app"
+// @Success		200		{object}	map[string]interface{}		"recovery_codes"
+// @Failure		400		{object}	map[string]interface{}		"invalid code, enrollment not started or already enabled"
+// @Failure		401		{object}	map[string]interface{}		"unauthorized"
+// @Failure		500		{object}	map[string]interface{}		"internal server error"
+// @Router			/user/mfa/enroll/confirm [post]
+func (h *AuthHandlers) confirmMFAHandler(c *gin.Context) {
+	var payload authdomain.MFACodePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	codes, err := h.services.MFAServices.ConfirmEnrollment(c.Request.Context(), user, payload.Code)
+	if err != nil {
+		h.mfaErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.mfa_enable",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   user.UserID.String(),
+		Before:     gin.H{"mfa_enabled": false},
+		After:      gin.H{"mfa_enabled": true},
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// @Summary		Regenerate my recovery codes
+// @Description	Replaces every recovery code after verifying a current two-factor code. The new codes are returned only in this response.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		authdomain.MFACodePayload	true	"Current two-factor code"
+// @Success		200		{object}	map[string]interface{}		"recovery_codes"
+// @Failure		400		{object}	map[string]interface{}		"invalid code or two-factor not enabled"
+// @Failure		401		{object}	map[string]interface{}		"unauthorized"
+// @Failure		500		{object}	map[string]interface{}		"internal server error"
+// @Router			/user/mfa/recovery-codes [post]
+func (h *AuthHandlers) regenerateRecoveryCodesHandler(c *gin.Context) {
+	var payload authdomain.MFACodePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	codes, err := h.services.MFAServices.RegenerateRecoveryCodes(c.Request.Context(), user, payload.Code)
+	if err != nil {
+		h.mfaErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.mfa_recovery_codes",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   user.UserID.String(),
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// @Summary		Disable two-factor
+// @Description	Turns two-factor off after checking the password and a current code. Not allowed for roles where two-factor is mandatory.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body	authdomain.DisableMFAPayload	true	"Password and two-factor code"
+// @Success		204		"Two-factor disabled. No content returned."
+// @Failure		400		{object}	map[string]interface{}	"invalid code, wrong password or two-factor not enabled"
+// @Failure		401		{object}	map[string]interface{}	"unauthorized"
+// @Failure		403		{object}	map[string]interface{}	"two-factor is mandatory for the role"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/user/mfa [delete]
+func (h *AuthHandlers) disableMFAHandler(c *gin.Context) {
+	var payload authdomain.DisableMFAPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.MFAServices.Disable(c.Request.Context(), user, payload); err != nil {
+		h.mfaErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.mfa_disable",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   user.UserID.String(),
+		Before:     gin.H{"mfa_enabled": true},
+		After:      gin.H{"mfa_enabled": false},
+	})
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *AuthHandlers) mfaErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, authdomain.ErrInvalidMFACode),
+		errors.Is(err, authdomain.ErrMFAAlreadyEnabled),
+		errors.Is(err, authdomain.ErrMFANotEnabled),
+		errors.Is(err, authdomain.ErrMFAEnrollmentPending),
+		errors.Is(err, authdomain.ErrWrongPassword):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	case errors.Is(err, authdomain.ErrMFARequired):
+		h.services.LogErrors.ForbiddenResponse(c)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,140 @@
+package authhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+// @Summary		List permissions
+// @Description	Returns the catalog of permissions that can be granted to roles.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"permissions"
+// @Failure		403	{object}	map[string]interface{}	"forbidden"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/permissions [get]
+func (h *AuthHandlers) listPermissionsHandler(c *gin.Context) {
+	permissions, err := h.services.PermissionServices.List(c.Request.Context())
+	if err != nil {
+		h.permissionErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"permissions": permissions,
+	})
+}
+
+// @Summary		List role permissions
+// @Description	Returns the permissions granted to the role. super_admin holds every permission.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			role_id	path		string					true	"Role ID"
+// @Success		200		{object}	map[string]interface{}	"permissions"
+// @Failure		400		{object}	map[string]interface{}	"invalid role id"
+// @Failure		403		{object}	map[string]interface{}	"forbidden"
+// @Failure		404		{object}	map[string]interface{}	"role not found"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/roles/{role_id}/permissions [get]
+func (h *AuthHandlers) rolePermissionsHandler(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("role_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	permissions, err := h.services.PermissionServices.RolePermissions(c.Request.Context(), roleID)
+	if err != nil {
+		h.permissionErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"permissions": permissions,
+	})
+}
+
+// @Summary		Grant a permission to a role
+// @Description	Grants the permission to every user of the role. The requester must hold the permission and be at or above the level of the role. Granting a permission the role already has is a no-op.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Param			role_id		path	string	true	"Role ID"
+// @Param			permission	path	string	true	"Permission name, e.g. users:read"
+// @Success		204			"Permission granted. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"invalid role id or role is super_admin"
+// @Failure		403			{object}	map[string]interface{}	"forbidden, permission not held or role above the requester"
+// @Failure		404			{object}	map[string]interface{}	"role or permission not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/roles/{role_id}/permissions/{permission} [put]
+func (h *AuthHandlers) grantPermissionHandler(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("role_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.PermissionServices.Grant(c.Request.Context(), actor, roleID, c.Param("permission")); err != nil {
+		h.permissionErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "role.permission_grant",
+		TargetType: auditdomain.TargetRole,
+		TargetID:   roleID.String(),
+		After:      gin.H{"permission": c.Param("permission")},
+	})
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Revoke a permission from a role
+// @Description	Removes the permission from every user of the role. The requester must be at or above the level of the role.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Param			role_id		path	string	true	"Role ID"
+// @Param			permission	path	string	true	"Permission name, e.g. users:read"
+// @Success		204			"Permission revoked. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"invalid role id or role is super_admin"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"role or permission not found, or permission not granted"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/roles/{role_id}/permissions/{permission} [delete]
+func (h *AuthHandlers) revokePermissionHandler(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("role_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.PermissionServices.Revoke(c.Request.Context(), actor, roleID, c.Param("permission")); err != nil {
+		h.permissionErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "role.permission_revoke",
+		TargetType: auditdomain.TargetRole,
+		TargetID:   roleID.String(),
+		Before:     gin.H{"permission": c.Param("permission")},
+	})
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *AuthHandlers) permissionErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, authdomain.ErrRoleEscalation):
+		h.services.LogErrors.ForbiddenResponse(c)
+	case errors.Is(err, authdomain.ErrSuperAdminPermissions):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,93 @@
+package authhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/pkg/images"
+	"github.com/vitalfit/api/pkg/notifier"
+)
+
+// @Summary		Update my profile
+// @Description	Updates the name, phone, birth date (YYYY-MM-DD) or gender of the authenticated user. Omitted fields are kept. Names cannot be blank and the phone must be in international format, e.g. +584121234567.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			profile	body		authdomain.UpdateProfilePayload	true	"Profile fields"
+// @Success		200		{object}	map[string]interface{}			"user"
+// @Failure		400		{object}	map[string]interface{}			"invalid profile"
+// @Failure		401		{object}	map[string]interface{}			"unauthorized"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/user/me [patch]
+func (h *AuthHandlers) updateMeHandler(c *gin.Context) {
+	var payload authdomain.UpdateProfilePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.UserServices.UpdateProfile(c.Request.Context(), user, payload); err != nil {
+		switch {
+		case errors.Is(err, authdomain.ErrInvalidBirthDate), errors.Is(err, authdomain.ErrBlankName), errors.Is(err, notifier.ErrInvalidPhone):
+			h.services.LogErrors.BadRequestResponse(c, err)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"user": user,
+	})
+}
+
+// @Summary		Upload my profile picture
+// @Description	Accepts a jpeg, png or webp image up to 5 MB in the avatar form field. The image is cropped to a square, resized and stored as the profile picture of the authenticated user.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Accept			multipart/form-data
+// @Produce		json
+// @Param			avatar	formData	file					true	"Profile picture"
+// @Success		200		{object}	map[string]interface{}	"profile_picture_url"
+// @Failure		400		{object}	map[string]interface{}	"missing, invalid or too large image"
+// @Failure		401		{object}	map[string]interface{}	"unauthorized"
+// @Failure		413		{object}	map[string]interface{}	"request body too large"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/user/me/avatar [put]
+func (h *AuthHandlers) updateAvatarHandler(c *gin.Context) {
+	// cap the body before the multipart form is parsed, it would otherwise be spooled to disk whole
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.services.UserServices.AvatarUploadLimit())
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.services.LogErrors.RequestTooLargeResponse(c, err)
+			return
+		}
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	defer file.Close()
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.UserServices.SetAvatar(c.Request.Context(), user, file); err != nil {
+		switch {
+		case errors.Is(err, images.ErrUnsupportedType), errors.Is(err, images.ErrTooLarge):
+			h.services.LogErrors.BadRequestResponse(c, err)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"profile_picture_url": user.ProfilePictureURL,
+	})
+}
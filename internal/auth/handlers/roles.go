@@ -0,0 +1,207 @@
+package authhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+// @Summary		List roles
+// @Description	Returns every role, highest level first.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"roles"
+// @Failure		403	{object}	map[string]interface{}	"forbidden"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/roles [get]
+func (h *AuthHandlers) listRolesHandler(c *gin.Context) {
+	roles, err := h.services.RoleServices.List(c.Request.Context())
+	if err != nil {
+		h.roleErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"roles": roles,
+	})
+}
+
+// @Summary		Create role
+// @Description	Creates a role. The level cannot be above the level of the requester.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		authdomain.CreateRolePayload	true	"Role"
+// @Success		201		{object}	authdomain.Roles				"created role"
+// @Failure		400		{object}	map[string]interface{}			"invalid payload or role name"
+// @Failure		403		{object}	map[string]interface{}			"forbidden or level above the requester"
+// @Failure		409		{object}	map[string]interface{}			"role name already exists"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/admin/roles [post]
+func (h *AuthHandlers) createRoleHandler(c *gin.Context) {
+	var payload authdomain.CreateRolePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	role, err := h.services.RoleServices.Create(c.Request.Context(), actor, payload)
+	if err != nil {
+		h.roleErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "role.create",
+		TargetType: auditdomain.TargetRole,
+		TargetID:   role.RoleID.String(),
+		After:      role,
+	})
+	c.JSON(http.StatusCreated, role)
+}
+
+// @Summary		Update role
+// @Description	Changes the name, level or description of a role. System roles keep their names and super_admin keeps its level. Neither the current nor the new level can be above the level of the requester.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			role_id	path		string							true	"Role ID"
+// @Param			payload	body		authdomain.UpdateRolePayload	true	"Fields to change"
+// @Success		200		{object}	authdomain.Roles				"updated role"
+// @Failure		400		{object}	map[string]interface{}			"invalid payload, role name or system role"
+// @Failure		403		{object}	map[string]interface{}			"forbidden or level above the requester"
+// @Failure		404		{object}	map[string]interface{}			"role not found"
+// @Failure		409		{object}	map[string]interface{}			"role name already exists"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/admin/roles/{role_id} [patch]
+func (h *AuthHandlers) updateRoleHandler(c *gin.Context) {
+	var payload authdomain.UpdateRolePayload
+	roleID, err := uuid.Parse(c.Param("role_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	previous, role, err := h.services.RoleServices.Update(c.Request.Context(), actor, roleID, payload)
+	if err != nil {
+		h.roleErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "role.update",
+		TargetType: auditdomain.TargetRole,
+		TargetID:   role.RoleID.String(),
+		Before:     previous,
+		After:      role,
+	})
+	c.JSON(http.StatusOK, role)
+}
+
+// @Summary		Delete role
+// @Description	Deletes a role no user has. System roles cannot be deleted.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Param			role_id	path	string	true	"Role ID"
+// @Success		204		"Role deleted. No content returned."
+// @Failure		400		{object}	map[string]interface{}	"invalid role id or system role"
+// @Failure		403		{object}	map[string]interface{}	"forbidden or level above the requester"
+// @Failure		404		{object}	map[string]interface{}	"role not found"
+// @Failure		409		{object}	map[string]interface{}	"role is still assigned to users"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/roles/{role_id} [delete]
+func (h *AuthHandlers) deleteRoleHandler(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("role_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	role, err := h.services.RoleServices.Delete(c.Request.Context(), actor, roleID)
+	if err != nil {
+		h.roleErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "role.delete",
+		TargetType: auditdomain.TargetRole,
+		TargetID:   role.RoleID.String(),
+		Before:     role,
+	})
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Change user role
+// @Description	Moves the user to another role. The requester must be at or above both the current and the new role of the user. The last super_admin cannot be demoted.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			user_id	path		string								true	"User ID"
+// @Param			payload	body		authdomain.ChangeUserRolePayload	true	"New role"
+// @Success		200		{object}	authdomain.UserRoleChange			"role change"
+// @Failure		400		{object}	map[string]interface{}				"invalid payload"
+// @Failure		403		{object}	map[string]interface{}				"forbidden or role above the requester"
+// @Failure		404		{object}	map[string]interface{}				"user or role not found"
+// @Failure		409		{object}	map[string]interface{}				"user already has the role, or last super_admin"
+// @Failure		500		{object}	map[string]interface{}				"internal server error"
+// @Router			/admin/users/{user_id}/role [put]
+func (h *AuthHandlers) changeUserRoleHandler(c *gin.Context) {
+	var payload authdomain.ChangeUserRolePayload
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	change, err := h.services.RoleServices.ChangeUserRole(c.Request.Context(), actor, userID, payload.RoleName)
+	if err != nil {
+		h.roleErrorResponse(c, err)
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "user.role_change",
+		TargetType: auditdomain.TargetUser,
+		TargetID:   change.UserID.String(),
+		Before:     gin.H{"role": change.PreviousRole},
+		After:      gin.H{"role": change.NewRole},
+	})
+	c.JSON(http.StatusOK, change)
+}
+
+func (h *AuthHandlers) roleErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, shared_errors.ErrConflict):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, authdomain.ErrRoleEscalation):
+		h.services.LogErrors.ForbiddenResponse(c)
+	case errors.Is(err, authdomain.ErrLastSuperAdmin),
+		errors.Is(err, authdomain.ErrRoleInUse),
+		errors.Is(err, authdomain.ErrRoleUnchanged):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, authdomain.ErrSystemRole),
+		errors.Is(err, authdomain.ErrInvalidRoleName):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
@@ -12,6 +12,10 @@ func (r *AuthHandlers) AuthRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
 		authGroup.POST("/register", r.registerUserClientHandler)
 		authGroup.PUT("/activate", r.activateUserHandler)
 		authGroup.POST("/login", r.loginHandler)
+		authGroup.POST("/login/mfa", r.loginMFAHandler)
+		authGroup.POST("/refresh", r.refreshTokenHandler)
+		authGroup.POST("/logout", r.logoutHandler)
+		authGroup.POST("/logout-all", m.AuthJwtTokenMiddleware(), r.logoutAllHandler)
 
 		passwordGroup := authGroup.Group("/password")
 		{
@@ -19,7 +23,7 @@ func (r *AuthHandlers) AuthRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
 			passwordGroup.POST("/reset", r.resetPasswordHandler)
 		}
 
-		protectedGroup := authGroup.Group("/").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("branch_admin"))
+		protectedGroup := authGroup.Group("/").Use(m.AuthJwtTokenMiddleware(), m.RequirePermission("users:write"))
 		{
 			protectedGroup.POST("/register-staff", r.registerUserStaffHandler)
 		}
@@ -32,5 +36,65 @@ func (r *AuthHandlers) UserRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
 	userGroup := rg.Group("/user").Use(m.AuthJwtTokenMiddleware())
 	{ //private routes
 		userGroup.GET("/whoami", r.whoami)
+		userGroup.PATCH("/me", r.updateMeHandler)
+		userGroup.PUT("/me/avatar", r.updateAvatarHandler)
+		userGroup.POST("/me/password", r.changePasswordHandler)
+		userGroup.POST("/me/email", r.requestEmailChangeHandler)
+		userGroup.POST("/me/email/confirm", r.confirmEmailChangeHandler)
+		userGroup.POST("/mfa/recovery-codes", r.regenerateRecoveryCodesHandler)
+		userGroup.DELETE("/mfa", r.disableMFAHandler)
+	}
+
+	// staff whose role requires two-factor reach these before enrolling
+	enrollmentGroup := rg.Group("/user").Use(m.AuthMFAEnrollmentMiddleware())
+	{ //private routes
+		enrollmentGroup.GET("/mfa", r.mfaStatusHandler)
+		enrollmentGroup.POST("/mfa/enroll", r.enrollMFAHandler)
+		enrollmentGroup.POST("/mfa/enroll/confirm", r.confirmMFAHandler)
+	}
+}
+
+func (r *AuthHandlers) InstructorRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+	instructorGroup := rg.Group("/instructors")
+	{ //public routes
+		instructorGroup.GET("", r.listInstructorsHandler)
+		instructorGroup.GET("/:instructor_id", r.getInstructorHandler)
+
+		protectedGroup := instructorGroup.Group("").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("branch_admin"))
+		{
+			protectedGroup.POST("", r.createInstructorHandler)
+			protectedGroup.PUT("/:instructor_id", r.updateInstructorHandler)
+			protectedGroup.DELETE("/:instructor_id", r.deleteInstructorHandler)
+		}
+	}
+
+	userGroup := rg.Group("/user").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("instructor"))
+	{ //private routes
+		userGroup.GET("/instructor", r.myInstructorHandler)
+		userGroup.PUT("/instructor", r.updateMyInstructorHandler)
+	}
+}
+
+func (r *AuthHandlers) AdminRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+	adminGroup := rg.Group("/admin").Use(m.AuthJwtTokenMiddleware())
+	{ //private routes
+		adminGroup.GET("/users", m.RequirePermission("users:read"), r.listUsersHandler)
+		adminGroup.GET("/users/:user_id", m.RequirePermission("users:read"), m.CheckUserAccess("user_id"), r.getUserHandler)
+		adminGroup.POST("/users/:user_id/block", m.RequirePermission("users:write"), m.CheckUserAccess("user_id"), r.blockClientHandler)
+		adminGroup.POST("/users/:user_id/unblock", m.RequirePermission("users:write"), m.CheckUserAccess("user_id"), r.unblockClientHandler)
+		adminGroup.GET("/users/:user_id/status-history", m.RequirePermission("users:read"), m.CheckUserAccess("user_id"), r.clientStatusHistoryHandler)
+		adminGroup.POST("/users/:user_id/unlock", m.RequirePermission("users:write"), m.CheckUserAccess("user_id"), r.unlockUserHandler)
+		adminGroup.GET("/users/:user_id/lockouts", m.RequirePermission("users:read"), m.CheckUserAccess("user_id"), r.userLockoutsHandler)
+
+		adminGroup.PUT("/users/:user_id/role", m.RequirePermission("roles:manage"), r.changeUserRoleHandler)
+
+		adminGroup.GET("/roles", m.RequirePermission("roles:manage"), r.listRolesHandler)
+		adminGroup.POST("/roles", m.RequirePermission("roles:manage"), r.createRoleHandler)
+		adminGroup.PATCH("/roles/:role_id", m.RequirePermission("roles:manage"), r.updateRoleHandler)
+		adminGroup.DELETE("/roles/:role_id", m.RequirePermission("roles:manage"), r.deleteRoleHandler)
+		adminGroup.GET("/permissions", m.RequirePermission("roles:manage"), r.listPermissionsHandler)
+		adminGroup.GET("/roles/:role_id/permissions", m.RequirePermission("roles:manage"), r.rolePermissionsHandler)
+		adminGroup.PUT("/roles/:role_id/permissions/:permission", m.RequirePermission("roles:manage"), r.grantPermissionHandler)
+		adminGroup.DELETE("/roles/:role_id/permissions/:permission", m.RequirePermission("roles:manage"), r.revokePermissionHandler)
 	}
 }
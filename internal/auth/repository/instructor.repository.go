@@ -0,0 +1,158 @@
+package authrepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InstructorStore struct {
+	db *gorm.DB
+}
+
+func NewInstructorStore(db *gorm.DB) *InstructorStore {
+	return &InstructorStore{db: db}
+}
+
+// Create saves the profile of the user. A profile deleted before is restored with the new data
+// instead, the user id is unique and the past classes keep pointing at the same instructor id.
+func (s *InstructorStore) Create(ctx context.Context, instructor *authdomain.Instructors) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		var deleted authdomain.Instructors
+		err := tx.WithContext(ctx).
+			Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND deleted_at IS NOT NULL", instructor.UserID).
+			Take(&deleted).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.WithContext(ctx).Omit(clause.Associations).Create(instructor).Error
+		}
+		if err != nil {
+			return err //rollback
+		}
+
+		instructor.InstructorID = deleted.InstructorID
+		instructor.CreatedAt = deleted.CreatedAt
+		instructor.UpdatedAt = time.Now()
+		return tx.WithContext(ctx).
+			Unscoped().
+			Model(&deleted).
+			Updates(map[string]interface{}{
+				"speciality": instructor.Speciality,
+				"biography":  instructor.Biography,
+				"updated_at": instructor.UpdatedAt,
+				"deleted_at": nil,
+			}).Error
+	})
+	if err != nil {
+		return mapInstructorError(err)
+	}
+	return nil
+}
+
+func (s *InstructorStore) GetByID(ctx context.Context, instructorID uuid.UUID) (*authdomain.Instructors, error) {
+	return s.get(ctx, "instructors.instructor_id = ?", instructorID)
+}
+
+func (s *InstructorStore) GetByUserID(ctx context.Context, userID uuid.UUID) (*authdomain.Instructors, error) {
+	return s.get(ctx, "instructors.user_id = ?", userID)
+}
+
+// List returns the instructors of active accounts, optionally only the ones assigned to a branch
+// or whose speciality contains the given text
+func (s *InstructorStore) List(ctx context.Context, filter authdomain.InstructorFilter) ([]authdomain.Instructors, error) {
+	var instructors []authdomain.Instructors
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.withUser(s.db.WithContext(ctx)).Order(`"User".last_name, "User".first_name`)
+	if filter.BranchID != nil {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM staff_branches sb WHERE sb.user_id = instructors.user_id AND sb.branch_id = ?)",
+			*filter.BranchID,
+		)
+	}
+	if filter.Speciality != "" {
+		query = query.Where("instructors.speciality ILIKE ?", "%"+filter.Speciality+"%")
+	}
+	if err := query.Find(&instructors).Error; err != nil {
+		return nil, err
+	}
+	return instructors, nil
+}
+
+func (s *InstructorStore) Update(ctx context.Context, instructor *authdomain.Instructors) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Model(instructor).
+		Select("speciality", "biography").
+		Updates(instructor)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *InstructorStore) Delete(ctx context.Context, instructorID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&authdomain.Instructors{}, instructorID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *InstructorStore) get(ctx context.Context, query string, id uuid.UUID) (*authdomain.Instructors, error) {
+	var instructor authdomain.Instructors
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.withUser(s.db.WithContext(ctx)).Where(query, id).First(&instructor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &instructor, nil
+}
+
+// withUser joins the user account so deleted accounts are left out
+func (s *InstructorStore) withUser(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Joins("User").
+		Where(`"User".deleted_at IS NULL`)
+}
+
+func mapInstructorError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return shared_errors.ErrConflict
+		case "23503":
+			return shared_errors.ErrNotFound
+		}
+	}
+	return err
+}
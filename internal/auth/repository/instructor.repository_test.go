@@ -0,0 +1,78 @@
+package authrepository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	authrepository "github.com/vitalfit/api/internal/auth/repository"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/internal/migrate/testdb/factory"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+func TestInstructorStoreCreateRestoresDeletedProfile(t *testing.T) {
+	f := factory.New(t, testdb.New(t))
+	store := authrepository.NewInstructorStore(f.DB)
+	ctx := context.Background()
+
+	user := f.Staff("instructor")
+	first := &authdomain.Instructors{UserID: user.UserID, Speciality: "Yoga"}
+	if err := store.Create(ctx, first); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Delete(ctx, first.InstructorID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	again := &authdomain.Instructors{UserID: user.UserID, Speciality: "Pilates", Biography: "Back again"}
+	if err := store.Create(ctx, again); err != nil {
+		t.Fatalf("Create after delete: %v", err)
+	}
+	if again.InstructorID != first.InstructorID {
+		t.Errorf("instructor id = %s, want the restored %s", again.InstructorID, first.InstructorID)
+	}
+
+	got, err := store.GetByUserID(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+	if got.Speciality != "Pilates" || got.Biography != "Back again" {
+		t.Errorf("profile = %q, %q, want the new data", got.Speciality, got.Biography)
+	}
+}
+
+func TestChangeRoleKeepsTheInstructorProfileInStep(t *testing.T) {
+	f := factory.New(t, testdb.New(t))
+	users := authrepository.NewUserRepositoryDAO(f.DB)
+	instructors := authrepository.NewInstructorStore(f.DB)
+	ctx := context.Background()
+
+	user := f.Staff("recepcionist")
+	if _, err := users.ChangeRole(ctx, user.UserID, f.Role("instructor")); err != nil {
+		t.Fatalf("ChangeRole to instructor: %v", err)
+	}
+	profile, err := instructors.GetByUserID(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("GetByUserID after becoming an instructor: %v", err)
+	}
+
+	if _, err := users.ChangeRole(ctx, user.UserID, f.Role("recepcionist")); err != nil {
+		t.Fatalf("ChangeRole away from instructor: %v", err)
+	}
+	if _, err := instructors.GetByUserID(ctx, user.UserID); !errors.Is(err, shared_errors.ErrNotFound) {
+		t.Errorf("GetByUserID after leaving = %v, want ErrNotFound", err)
+	}
+
+	if _, err := users.ChangeRole(ctx, user.UserID, f.Role("instructor")); err != nil {
+		t.Fatalf("ChangeRole back to instructor: %v", err)
+	}
+	restored, err := instructors.GetByUserID(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("GetByUserID after coming back: %v", err)
+	}
+	if restored.InstructorID != profile.InstructorID {
+		t.Errorf("instructor id = %s, want the restored %s", restored.InstructorID, profile.InstructorID)
+	}
+}
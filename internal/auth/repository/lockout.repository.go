@@ -0,0 +1,171 @@
+package authrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+)
+
+// registerFailureQuery counts a failure, the counter starts over when the previous one is older than the window
+const registerFailureQuery = `
+INSERT INTO login_attempts (scope, identifier, ip_address, failures, last_failure_at)
+VALUES (@scope, @identifier, @ip_address, 1, @now)
+ON CONFLICT (scope, identifier, ip_address) DO UPDATE SET
+	failures = CASE WHEN login_attempts.last_failure_at < @window_start THEN 1 ELSE login_attempts.failures + 1 END,
+	last_failure_at = EXCLUDED.last_failure_at
+RETURNING scope, identifier, ip_address, failures, last_failure_at, locked_until`
+
+type LockoutStore struct {
+	db *gorm.DB
+}
+
+func NewLockoutStore(db *gorm.DB) *LockoutStore {
+	return &LockoutStore{db: db}
+}
+
+func (s *LockoutStore) Get(ctx context.Context, scope authdomain.AttemptScope, identifier string, ipAddress string) (*authdomain.LoginAttempts, error) {
+	var attempts authdomain.LoginAttempts
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Where("scope = ? AND identifier = ? AND ip_address = ?", scope, identifier, ipAddress).
+		First(&attempts).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+// RegisterFailure counts a failed attempt and applies the delay the policy sets for it.
+// Reaching the failure limit records a lockout.
+func (s *LockoutStore) RegisterFailure(ctx context.Context, scope authdomain.AttemptScope, identifier string, ipAddress string, policy authdomain.LockoutPolicy) (*authdomain.LoginAttempts, error) {
+	var attempts authdomain.LoginAttempts
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	now := time.Now()
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		err := tx.WithContext(ctx).
+			Raw(registerFailureQuery,
+				sql.Named("scope", scope),
+				sql.Named("identifier", identifier),
+				sql.Named("ip_address", ipAddress),
+				sql.Named("now", now),
+				sql.Named("window_start", now.Add(-policy.FailureWindow)),
+			).
+			Scan(&attempts).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		delay, lockout := policy.Delay(attempts.Failures)
+		if delay <= 0 {
+			return nil //commit
+		}
+		lockedUntil := now.Add(delay)
+		attempts.LockedUntil = &lockedUntil
+		err = tx.WithContext(ctx).
+			Model(&authdomain.LoginAttempts{}).
+			Where("scope = ? AND identifier = ? AND ip_address = ?", scope, identifier, ipAddress).
+			Update("locked_until", lockedUntil).Error
+		if err != nil {
+			return err //rollback
+		}
+		if !lockout {
+			return nil //commit
+		}
+
+		lock := &authdomain.AccountLockouts{
+			Scope:       scope,
+			Identifier:  identifier,
+			Failures:    attempts.Failures,
+			IPAddress:   ipAddress,
+			LockedUntil: lockedUntil,
+		}
+		var userID uuid.UUID
+		err = tx.WithContext(ctx).
+			Model(&authdomain.Users{}).
+			Select("user_id").
+			Where("email = ?", identifier).
+			Limit(1).
+			Scan(&userID).Error
+		if err != nil {
+			return err //rollback
+		}
+		if userID != uuid.Nil {
+			lock.UserID = &userID
+		}
+		if err := tx.WithContext(ctx).Create(lock).Error; err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+// Clear forgets the failures of the identifier from the address after a successful attempt
+func (s *LockoutStore) Clear(ctx context.Context, scope authdomain.AttemptScope, identifier string, ipAddress string) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.WithContext(ctx).
+		Where("scope = ? AND identifier = ? AND ip_address = ?", scope, identifier, ipAddress).
+		Delete(&authdomain.LoginAttempts{}).Error
+}
+
+// Unlock clears the failures of the identifier in every scope and from every address and closes its active lockouts
+func (s *LockoutStore) Unlock(ctx context.Context, identifier string, unlockedBy uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		err := tx.WithContext(ctx).
+			Where("identifier = ?", identifier).
+			Delete(&authdomain.LoginAttempts{}).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		err = tx.WithContext(ctx).
+			Model(&authdomain.AccountLockouts{}).
+			Where("identifier = ? AND unlocked_at IS NULL AND locked_until > ?", identifier, time.Now()).
+			Updates(map[string]interface{}{
+				"unlocked_at": time.Now(),
+				"unlocked_by": unlockedBy,
+			}).Error
+		if err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
+
+// ListLockouts returns the lockouts of the user, newest first
+func (s *LockoutStore) ListLockouts(ctx context.Context, userID uuid.UUID) ([]authdomain.AccountLockouts, error) {
+	var lockouts []authdomain.AccountLockouts
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("locked_at DESC").
+		Find(&lockouts).Error
+	if err != nil {
+		return nil, err
+	}
+	return lockouts, nil
+}
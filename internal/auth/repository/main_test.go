@@ -0,0 +1,12 @@
+package authrepository_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/vitalfit/api/internal/migrate/testdb"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
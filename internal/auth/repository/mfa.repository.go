@@ -0,0 +1,195 @@
+package authrepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFAStore struct {
+	db *gorm.DB
+}
+
+func NewMFAStore(db *gorm.DB) *MFAStore {
+	return &MFAStore{db: db}
+}
+
+func (s *MFAStore) Get(ctx context.Context, userID uuid.UUID) (*authdomain.UserMFA, error) {
+	var mfa authdomain.UserMFA
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&mfa).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// SavePending stores a new secret waiting for confirmation, replacing a previous pending one
+func (s *MFAStore) SavePending(ctx context.Context, mfa *authdomain.UserMFA) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "updated_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_mfa.enabled_at IS NULL"}}},
+		}).
+		Create(mfa)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return authdomain.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// Enable confirms the enrollment and stores the first set of recovery codes
+func (s *MFAStore) Enable(ctx context.Context, userID uuid.UUID, step int64, codeHashes [][]byte) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		result := tx.WithContext(ctx).
+			Model(&authdomain.UserMFA{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Updates(map[string]interface{}{
+				"enabled_at":     time.Now(),
+				"last_used_step": step,
+				"updated_at":     time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error //rollback
+		}
+		if result.RowsAffected == 0 {
+			return authdomain.ErrMFAAlreadyEnabled //rollback
+		}
+
+		err := tx.WithContext(ctx).
+			Model(&authdomain.Users{}).
+			Where("user_id = ?", userID).
+			Update("mfa_enabled", true).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		if err := s.replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
+
+// UseStep records the time step of an accepted code. It fails if the step, or a later one, was already used.
+func (s *MFAStore) UseStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Model(&authdomain.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]interface{}{
+			"last_used_step": step,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return authdomain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// UseRecoveryCode burns an unused recovery code
+func (s *MFAStore) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Model(&authdomain.MFARecoveryCodes{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return authdomain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *MFAStore) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes [][]byte) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		return s.replaceRecoveryCodes(ctx, tx, userID, codeHashes)
+	})
+}
+
+// CountRecoveryCodes returns how many recovery codes the user has left
+func (s *MFAStore) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Model(&authdomain.MFARecoveryCodes{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Disable removes the secret and the recovery codes of the user
+func (s *MFAStore) Disable(ctx context.Context, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&authdomain.MFARecoveryCodes{}).Error; err != nil {
+			return err //rollback
+		}
+		if err := tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&authdomain.UserMFA{}).Error; err != nil {
+			return err //rollback
+		}
+		err := tx.WithContext(ctx).
+			Model(&authdomain.Users{}).
+			Where("user_id = ?", userID).
+			Update("mfa_enabled", false).Error
+		if err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
+
+func (s *MFAStore) replaceRecoveryCodes(ctx context.Context, tx *gorm.DB, userID uuid.UUID, codeHashes [][]byte) error {
+	if err := tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&authdomain.MFARecoveryCodes{}).Error; err != nil {
+		return err
+	}
+	codes := make([]authdomain.MFARecoveryCodes, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, authdomain.MFARecoveryCodes{
+			UserID:   userID,
+			CodeHash: hash,
+		})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(&codes).Error
+}
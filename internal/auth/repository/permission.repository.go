@@ -0,0 +1,98 @@
+package authrepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PermissionStore struct {
+	db *gorm.DB
+}
+
+func NewPermissionStore(db *gorm.DB) *PermissionStore {
+	return &PermissionStore{db: db}
+}
+
+func (s *PermissionStore) List(ctx context.Context) ([]authdomain.Permissions, error) {
+	var permissions []authdomain.Permissions
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (s *PermissionStore) ListForRole(ctx context.Context, roleID uuid.UUID) ([]authdomain.Permissions, error) {
+	var permissions []authdomain.Permissions
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Joins("JOIN role_permissions rp ON rp.permission_id = permissions.permission_id").
+		Where("rp.role_id = ?", roleID).
+		Order("permissions.name").
+		Find(&permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// Grant maps the permission to the role, granting it again is a no-op
+func (s *PermissionStore) Grant(ctx context.Context, roleID uuid.UUID, name string, grantedBy uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	permission, err := s.getByName(ctx, s.db, name)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&authdomain.RolePermissions{
+			RoleID:       roleID,
+			PermissionID: permission.PermissionID,
+			GrantedBy:    &grantedBy,
+		}).Error
+}
+
+func (s *PermissionStore) Revoke(ctx context.Context, roleID uuid.UUID, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	permission, err := s.getByName(ctx, s.db, name)
+	if err != nil {
+		return err
+	}
+	result := s.db.WithContext(ctx).
+		Where("role_id = ? AND permission_id = ?", roleID, permission.PermissionID).
+		Delete(&authdomain.RolePermissions{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *PermissionStore) getByName(ctx context.Context, tx *gorm.DB, name string) (*authdomain.Permissions, error) {
+	var permission authdomain.Permissions
+	err := tx.WithContext(ctx).Where("name = ?", name).First(&permission).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &permission, nil
+}
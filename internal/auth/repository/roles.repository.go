@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	authdomain "github.com/vitalfit/api/internal/auth/domain"
 	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RoleStore struct {
@@ -32,3 +36,101 @@ func (s *RoleStore) GetByName(ctx context.Context, name string) (*authdomain.Rol
 
 	return &role, nil
 }
+
+func (s *RoleStore) GetByID(ctx context.Context, roleID uuid.UUID) (*authdomain.Roles, error) {
+	var role authdomain.Roles
+
+	err := s.db.WithContext(ctx).Where("role_id = ?", roleID).First(&role).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// List returns every role, highest level first
+func (s *RoleStore) List(ctx context.Context) ([]authdomain.Roles, error) {
+	var roles []authdomain.Roles
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Order("level DESC, name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (s *RoleStore) Create(ctx context.Context, role *authdomain.Roles) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(role).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return shared_errors.ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *RoleStore) Update(ctx context.Context, role *authdomain.Roles) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Model(role).
+		Select("name", "level", "description", "updated_at").
+		Updates(role).Error
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return shared_errors.ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes the role if no user, deleted or not, still has it
+func (s *RoleStore) Delete(ctx context.Context, roleID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		var role authdomain.Roles
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("role_id = ?", roleID).
+			First(&role).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shared_errors.ErrNotFound //rollback
+			}
+			return err //rollback
+		}
+
+		var users int64
+		err = tx.WithContext(ctx).
+			Unscoped().
+			Model(&authdomain.Users{}).
+			Where("role_id = ?", roleID).
+			Count(&users).Error
+		if err != nil {
+			return err //rollback
+		}
+		if users > 0 {
+			return authdomain.ErrRoleInUse //rollback
+		}
+
+		if err := tx.WithContext(ctx).Delete(&role).Error; err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
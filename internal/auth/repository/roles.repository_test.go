@@ -0,0 +1,101 @@
+package authrepository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	authrepository "github.com/vitalfit/api/internal/auth/repository"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/internal/migrate/testdb/factory"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+func newRoleStore(t *testing.T) (*authrepository.RoleStore, *factory.Factory) {
+	f := factory.New(t, testdb.New(t))
+	return authrepository.NewRoleStore(f.DB), f
+}
+
+func TestRoleStoreSeededRoles(t *testing.T) {
+	store, _ := newRoleStore(t)
+	ctx := context.Background()
+
+	roles, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(roles) != 7 {
+		t.Fatalf("seeded roles = %d, want 7", len(roles))
+	}
+	if roles[0].Name != authdomain.SuperAdminRole {
+		t.Errorf("first role = %s, want the highest level %s", roles[0].Name, authdomain.SuperAdminRole)
+	}
+	for i := 1; i < len(roles); i++ {
+		if roles[i].Level > roles[i-1].Level {
+			t.Errorf("roles not ordered by level: %s (%d) after %s (%d)", roles[i].Name, roles[i].Level, roles[i-1].Name, roles[i-1].Level)
+		}
+	}
+
+	client, err := store.GetByName(ctx, "client")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	byID, err := store.GetByID(ctx, client.RoleID)
+	if err != nil || byID.Name != "client" {
+		t.Errorf("GetByID = %v, %v, want the client role", byID, err)
+	}
+
+	if _, err := store.GetByName(ctx, "missing"); !errors.Is(err, shared_errors.ErrNotFound) {
+		t.Errorf("GetByName of a missing role = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRoleStoreCreateConflict(t *testing.T) {
+	store, f := newRoleStore(t)
+	ctx := context.Background()
+
+	role := f.NewRole()
+	if err := store.Create(ctx, role); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, f.NewRole(func(r *authdomain.Roles) { r.Name = role.Name })); !errors.Is(err, shared_errors.ErrConflict) {
+		t.Errorf("Create with a taken name = %v, want ErrConflict", err)
+	}
+
+	other := f.CreateRole()
+	other.Name = role.Name
+	if err := store.Update(ctx, other); !errors.Is(err, shared_errors.ErrConflict) {
+		t.Errorf("Update to a taken name = %v, want ErrConflict", err)
+	}
+}
+
+func TestRoleStoreDelete(t *testing.T) {
+	store, f := newRoleStore(t)
+	ctx := context.Background()
+
+	inUse := f.CreateRole()
+	user := f.User(func(u *authdomain.Users) { u.RoleID = inUse.RoleID })
+	if err := store.Delete(ctx, inUse.RoleID); !errors.Is(err, authdomain.ErrRoleInUse) {
+		t.Errorf("Delete of an assigned role = %v, want ErrRoleInUse", err)
+	}
+
+	// soft deleted users still hold the role
+	if err := f.DB.Delete(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, inUse.RoleID); !errors.Is(err, authdomain.ErrRoleInUse) {
+		t.Errorf("Delete of a role of a deleted user = %v, want ErrRoleInUse", err)
+	}
+
+	unused := f.CreateRole()
+	if err := store.Delete(ctx, unused.RoleID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.GetByID(ctx, unused.RoleID); !errors.Is(err, shared_errors.ErrNotFound) {
+		t.Errorf("GetByID after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, unused.RoleID); !errors.Is(err, shared_errors.ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+}
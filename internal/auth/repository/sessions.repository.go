@@ -0,0 +1,135 @@
+package authrepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenStore struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenStore(db *gorm.DB) *RefreshTokenStore {
+	return &RefreshTokenStore{db: db}
+}
+
+func (s *RefreshTokenStore) Create(ctx context.Context, token *authdomain.RefreshTokens) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+	return s.db.WithContext(ctx).Create(token).Error
+}
+
+func (s *RefreshTokenStore) GetByHash(ctx context.Context, tokenHash []byte) (*authdomain.RefreshTokens, error) {
+	var token authdomain.RefreshTokens
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate revokes the token identified by tokenHash and stores next in its place.
+// Presenting a token that was already rotated or revoked revokes the whole family.
+func (s *RefreshTokenStore) Rotate(ctx context.Context, tokenHash []byte, next *authdomain.RefreshTokens) (*authdomain.RefreshTokens, error) {
+	var current authdomain.RefreshTokens
+
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", tokenHash).
+			First(&current).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shared_errors.ErrNotFound
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			return shared_errors.ErrTokenReused //rollback
+		}
+		if time.Now().After(current.ExpiresAt) {
+			return shared_errors.ErrInvalidToken //rollback
+		}
+
+		now := time.Now()
+		next.UserID = current.UserID
+		next.FamilyID = current.FamilyID
+		if err := tx.WithContext(ctx).Create(next).Error; err != nil {
+			return err
+		}
+
+		return tx.WithContext(ctx).Model(&current).Updates(map[string]any{
+			"revoked_at":  now,
+			"replaced_by": next.TokenID,
+		}).Error
+	})
+
+	if errors.Is(err, shared_errors.ErrTokenReused) {
+		if revokeErr := s.RevokeFamily(ctx, current.FamilyID); revokeErr != nil {
+			return nil, revokeErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &current, nil
+}
+
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+	return s.db.WithContext(ctx).
+		Model(&authdomain.RefreshTokens{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (s *RefreshTokenStore) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+	return s.db.WithContext(ctx).
+		Model(&authdomain.RefreshTokens{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeOthers revokes every session of the user except the given one
+func (s *RefreshTokenStore) RevokeOthers(ctx context.Context, userID, keepFamilyID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+	return s.db.WithContext(ctx).
+		Model(&authdomain.RefreshTokens{}).
+		Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", userID, keepFamilyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// IsFamilyActive reports whether the session still holds a usable refresh token.
+func (s *RefreshTokenStore) IsFamilyActive(ctx context.Context, familyID uuid.UUID) (bool, error) {
+	var count int64
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Model(&authdomain.RefreshTokens{}).
+		Where("family_id = ? AND revoked_at IS NULL AND expires_at > ?", familyID, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -10,18 +10,24 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	outboxrepository "github.com/vitalfit/api/internal/outbox/repository"
 	shared_errors "github.com/vitalfit/api/internal/shared/errors"
 	"github.com/vitalfit/api/pkg/db"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepositoryDAO struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *outboxrepository.OutboxStore
 }
 
 func NewUserRepositoryDAO(db *gorm.DB) *UserRepositoryDAO {
 	return &UserRepositoryDAO{
-		db: db,
+		db:     db,
+		outbox: outboxrepository.NewOutboxStore(db),
 	}
 }
 
@@ -50,6 +56,7 @@ func (s *UserRepositoryDAO) GetByID(ctx context.Context, userID uuid.UUID) (*aut
 
 	result := s.db.WithContext(ctx).
 		Preload("Role").
+		Preload("ClientProfile").
 		Where("user_id = ?", userID).
 		First(&user)
 
@@ -62,7 +69,9 @@ func (s *UserRepositoryDAO) GetByID(ctx context.Context, userID uuid.UUID) (*aut
 	return &user, nil
 }
 
-func (s *UserRepositoryDAO) CreateAndInvitate(ctx context.Context, user *authdomain.Users, token string, invitationExp time.Duration) error {
+// CreateAndInvitate creates the user with its invitation, assigns it to the branches and queues the
+// invitation email in the same transaction, the email is sent by the outbox dispatcher
+func (s *UserRepositoryDAO) CreateAndInvitate(ctx context.Context, user *authdomain.Users, branchIDs []uuid.UUID, token string, invitationExp time.Duration, message *outboxdomain.Outbox) error {
 	//transacction
 	return db.WithTX(s.db, func(tx *gorm.DB) error {
 
@@ -70,16 +79,44 @@ func (s *UserRepositoryDAO) CreateAndInvitate(ctx context.Context, user *authdom
 			return err //rollback
 		}
 
+		if err := s.assignBranches(ctx, tx, user.UserID, branchIDs); err != nil {
+			return err //rollback
+		}
+
 		if err := s.createUserInvitation(ctx, tx, token, user.UserID, invitationExp); err != nil {
 			return err //rollback
 		}
 
+		message.AttachTo(user.UserID, token)
+		if err := s.outbox.Enqueue(ctx, tx, message); err != nil {
+			return err //rollback
+		}
+
 		return nil //commit
 	})
 }
 
+func (s *UserRepositoryDAO) assignBranches(ctx context.Context, tx *gorm.DB, userID uuid.UUID, branchIDs []uuid.UUID) error {
+	if len(branchIDs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	assignments := make([]branchdomain.StaffBranches, 0, len(branchIDs))
+	for _, branchID := range branchIDs {
+		assignments = append(assignments, branchdomain.StaffBranches{UserID: userID, BranchID: branchID})
+	}
+	return tx.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&assignments).Error
+}
+
 func (s *UserRepositoryDAO) Delete(ctx context.Context, userID uuid.UUID) error {
 	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		if err := s.deleteInstructorProfile(ctx, tx, userID); err != nil {
+			return err //rollback
+		}
 		if err := s.delete(ctx, tx, userID); err != nil {
 			return err //rollback
 		}
@@ -90,31 +127,86 @@ func (s *UserRepositoryDAO) Delete(ctx context.Context, userID uuid.UUID) error
 	})
 }
 
-func (s *UserRepositoryDAO) Activate(ctx context.Context, code string) error {
-	return db.WithTX(s.db, func(tx *gorm.DB) error {
-		user, err := s.getUserFromInvitation(ctx, tx, code)
-		if err != nil {
-			return err
+// Activate validates the account if the code matches one of its invitations. A wrong code is
+// counted against every pending invitation and discards those that reach maxAttempts.
+func (s *UserRepositoryDAO) Activate(ctx context.Context, userID uuid.UUID, code string, maxAttempts int) error {
+	var invalid error
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		if err := s.spendOTP(ctx, tx, "user_invitations", userID, code, maxAttempts); err != nil {
+			if errors.Is(err, authdomain.ErrInvalidOTP) {
+				invalid = err
+				return nil //commit the spent attempt
+			}
+			return err //rollback
 		}
-		user.IsValidated = true
-		if err := s.Update(ctx, user); err != nil {
-			return err
+
+		err := tx.WithContext(ctx).
+			Model(&authdomain.Users{}).
+			Where("user_id = ?", userID).
+			Update("is_validated", true).Error
+		if err != nil {
+			return err //rollback
 		}
 
-		if err := s.deleteUserInvitations(ctx, tx, user.UserID); err != nil {
-			return err
+		if err := s.deleteUserInvitations(ctx, tx, userID); err != nil {
+			return err //rollback
 		}
 
-		return nil
+		return nil //commit
 	})
+	if err != nil {
+		return err
+	}
+	return invalid
+}
+
+// InvitationOwner returns the user of the email when it is given, or else the user the pending
+// activation code was sent to. The code itself is checked by Activate.
+func (s *UserRepositoryDAO) InvitationOwner(ctx context.Context, email string, code string) (uuid.UUID, error) {
+	return s.codeOwner(ctx, "user_invitations", email, code)
+}
 
+// ResetTokenOwner returns the user of the email when it is given, or else the user the pending
+// reset code was sent to. The code itself is checked by ResetUserPassword.
+func (s *UserRepositoryDAO) ResetTokenOwner(ctx context.Context, email string, code string) (uuid.UUID, error) {
+	return s.codeOwner(ctx, "password_reset_token", email, code)
+}
+
+// codeOwner finds the user that has a code pending in the table, by email or by the code
+func (s *UserRepositoryDAO) codeOwner(ctx context.Context, table string, email string, code string) (uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).
+		Table(table).
+		Joins("JOIN users ON users.user_id = " + table + ".user_id AND users.deleted_at IS NULL")
+	if email != "" {
+		query = query.Where("users.email = ?", email)
+	} else {
+		hash := sha256.Sum256([]byte(code))
+		query = query.Where(table+".token = ? AND "+table+".expiry > ?", hex.EncodeToString(hash[:]), time.Now())
+	}
+	err := query.Limit(1).Pluck(table+".user_id", &userIDs).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(userIDs) == 0 {
+		return uuid.Nil, shared_errors.ErrNotFound
+	}
+	return userIDs[0], nil
 }
 
 func (s *UserRepositoryDAO) GetByEmail(ctx context.Context, email string) (*authdomain.Users, error) {
 	var user authdomain.Users
 	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
 	defer cancel()
-	err := s.db.WithContext(ctx).Where("email = ?", email).Where("is_validated = ?", true).First(&user).Error
+	err := s.db.WithContext(ctx).
+		Preload("Role").
+		Preload("ClientProfile").
+		Where("email = ?", email).
+		Where("is_validated = ?", true).
+		First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, shared_errors.ErrNotFound
@@ -132,6 +224,344 @@ func (s *UserRepositoryDAO) Update(ctx context.Context, user *authdomain.Users)
 	return nil
 }
 
+// UpdateProfile saves the self-service profile fields of the user
+func (s *UserRepositoryDAO) UpdateProfile(ctx context.Context, user *authdomain.Users) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.WithContext(ctx).
+		Model(user).
+		Select("first_name", "last_name", "phone", "birth_date", "gender", "profile_picture_url", "preferred_language").
+		Updates(user).Error
+}
+
+func (s *UserRepositoryDAO) UpdatePassword(ctx context.Context, user *authdomain.Users) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.WithContext(ctx).Model(user).Select("password_hash").Updates(user).Error
+}
+
+// CreateEmailChange replaces the pending change of the user and queues the email with its code
+func (s *UserRepositoryDAO) CreateEmailChange(ctx context.Context, change *authdomain.EmailChangeRequests, message *outboxdomain.Outbox) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		err := tx.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"token", "new_email", "expiry", "attempts", "created_at"}),
+			}).
+			Create(change).Error
+		if err != nil {
+			return err //rollback
+		}
+		message.AttachTo(change.UserID, change.Token)
+		if err := s.outbox.Enqueue(ctx, tx, message); err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
+
+// ConfirmEmailChange switches the email of the user to the pending one if the code matches.
+// The request is discarded once maxAttempts wrong codes were tried.
+func (s *UserRepositoryDAO) ConfirmEmailChange(ctx context.Context, userID uuid.UUID, token string, maxAttempts int) (*authdomain.EmailChangeRequests, error) {
+	var change authdomain.EmailChangeRequests
+	var invalid error
+
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		if err := s.spendOTP(ctx, tx, "email_change_requests", userID, token, maxAttempts); err != nil {
+			if errors.Is(err, authdomain.ErrInvalidOTP) {
+				invalid = err
+				return nil //commit the spent attempt
+			}
+			return err //rollback
+		}
+
+		err := tx.WithContext(ctx).
+			Where("user_id = ?", userID).
+			First(&change).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		err = tx.WithContext(ctx).
+			Model(&authdomain.Users{}).
+			Where("user_id = ?", userID).
+			Update("email", change.NewEmail).Error
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return shared_errors.ErrConflict //rollback
+			}
+			return err //rollback
+		}
+
+		if err := tx.WithContext(ctx).Delete(&change).Error; err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+	if err != nil {
+		return nil, err
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+	return &change, nil
+}
+
+// SetQRCode stores the last check-in payload issued to the client
+func (s *UserRepositoryDAO) SetQRCode(ctx context.Context, userID uuid.UUID, code string) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Model(&authdomain.ClientProfiles{}).
+		Where("user_id = ?", userID).
+		Update("qr_code", code)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+// SetClientStatus changes the status of the client and records the change in its history
+func (s *UserRepositoryDAO) SetClientStatus(ctx context.Context, change *authdomain.ClientStatusHistory) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		var profile authdomain.ClientProfiles
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", change.UserID).
+			First(&profile).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return authdomain.ErrNotClient //rollback
+			}
+			return err //rollback
+		}
+		if profile.Status == change.NewStatus {
+			return authdomain.ErrStatusUnchanged //rollback
+		}
+		// read before Updates, which writes the new status back into profile
+		change.PreviousStatus = profile.Status
+
+		justification := ""
+		if change.NewStatus == authdomain.ClientStatusBlocked {
+			justification = change.Justification
+		}
+		err = tx.WithContext(ctx).
+			Model(&profile).
+			Updates(map[string]interface{}{
+				"status":              change.NewStatus,
+				"block_justification": justification,
+			}).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		if err := tx.WithContext(ctx).Create(change).Error; err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
+
+// ChangeRole gives the user a new role. Demoting a super_admin locks the super_admin role row
+// before counting its users, so concurrent demotions cannot leave the system without one.
+func (s *UserRepositoryDAO) ChangeRole(ctx context.Context, userID uuid.UUID, role *authdomain.Roles) (*authdomain.UserRoleChange, error) {
+	var user authdomain.Users
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		err := tx.WithContext(ctx).
+			Preload("Role").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).
+			First(&user).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shared_errors.ErrNotFound //rollback
+			}
+			return err //rollback
+		}
+		if user.RoleID == role.RoleID {
+			return authdomain.ErrRoleUnchanged //rollback
+		}
+
+		if user.Role.Name == authdomain.SuperAdminRole {
+			err := tx.WithContext(ctx).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("role_id = ?", user.RoleID).
+				First(&authdomain.Roles{}).Error
+			if err != nil {
+				return err //rollback
+			}
+			var superAdmins int64
+			err = tx.WithContext(ctx).
+				Model(&authdomain.Users{}).
+				Where("role_id = ?", user.RoleID).
+				Count(&superAdmins).Error
+			if err != nil {
+				return err //rollback
+			}
+			if superAdmins <= 1 {
+				return authdomain.ErrLastSuperAdmin //rollback
+			}
+		}
+
+		err = tx.WithContext(ctx).
+			Model(&authdomain.Users{}).
+			Where("user_id = ?", userID).
+			Update("role_id", role.RoleID).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		// instructors need their public profile, as when they are registered. A profile deleted
+		// when the user stopped being an instructor is restored.
+		if role.Name == "instructor" {
+			err := tx.WithContext(ctx).
+				Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "user_id"}},
+					DoUpdates: clause.Assignments(map[string]interface{}{"deleted_at": nil}),
+				}).
+				Create(&authdomain.Instructors{UserID: userID}).Error
+			if err != nil {
+				return err //rollback
+			}
+		}
+		// and lose it when they stop being one, it is soft deleted as the schedules still point to it
+		if user.Role.Name == "instructor" {
+			err := tx.WithContext(ctx).
+				Where("user_id = ?", userID).
+				Delete(&authdomain.Instructors{}).Error
+			if err != nil {
+				return err //rollback
+			}
+		}
+		return nil //commit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authdomain.UserRoleChange{
+		UserID:       userID,
+		PreviousRole: user.Role.Name,
+		NewRole:      role.Name,
+	}, nil
+}
+
+// ListStatusHistory returns the status changes of the client, newest first
+func (s *UserRepositoryDAO) ListStatusHistory(ctx context.Context, userID uuid.UUID) ([]authdomain.ClientStatusHistory, error) {
+	var history []authdomain.ClientStatusHistory
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("changed_at DESC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// branchScopeCondition matches the users that belong to one of the branches: staff assigned to
+// them and clients who checked in at them. It takes the branch ids twice.
+const branchScopeCondition = `(
+	EXISTS (SELECT 1 FROM staff_branches sb WHERE sb.user_id = users.user_id AND sb.branch_id IN (?))
+	OR EXISTS (SELECT 1 FROM checkins c WHERE c.user_id = users.user_id AND c.branch_id IN (?))
+)`
+
+// InBranches reports whether the user belongs to one of the branches
+func (s *UserRepositoryDAO) InBranches(ctx context.Context, userID uuid.UUID, branchIDs []uuid.UUID) (bool, error) {
+	if len(branchIDs) == 0 {
+		return false, nil
+	}
+	var count int64
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Model(&authdomain.Users{}).
+		Where("users.user_id = ?", userID).
+		Where(branchScopeCondition, branchIDs, branchIDs).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// List returns a page of users matching the filter and the total number of matches
+func (s *UserRepositoryDAO) List(ctx context.Context, filter authdomain.UserFilter) ([]authdomain.Users, int64, error) {
+	var (
+		users []authdomain.Users
+		total int64
+	)
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).
+		Model(&authdomain.Users{}).
+		Joins("JOIN roles ON roles.role_id = users.role_id").
+		Joins("LEFT JOIN client_profiles ON client_profiles.user_id = users.user_id AND client_profiles.deleted_at IS NULL")
+	if filter.RoleName != "" {
+		query = query.Where("roles.name = ?", filter.RoleName)
+	}
+	if filter.Validated != nil {
+		query = query.Where("users.is_validated = ?", *filter.Validated)
+	}
+	if filter.Status != "" {
+		query = query.Where("client_profiles.status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		query = query.Where("client_profiles.category = ?", filter.Category)
+	}
+	if filter.BranchID != nil {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM staff_branches sb WHERE sb.user_id = users.user_id AND sb.branch_id = ?)",
+			*filter.BranchID,
+		)
+	}
+	if filter.Scoped {
+		query = query.Where(branchScopeCondition, filter.BranchScope, filter.BranchScope)
+	}
+	if search := filter.SearchQuery(); search != "" {
+		query = query.Where("users.search_vector @@ to_tsquery('simple', ?)", search)
+	}
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.
+		Preload("Role").
+		Preload("ClientProfile").
+		Order(filter.OrderBy()).
+		Offset(filter.Offset()).
+		Limit(filter.PageSize).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 func (s *UserRepositoryDAO) delete(ctx context.Context, tx *gorm.DB, userID uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
 	defer cancel()
@@ -144,6 +574,14 @@ func (s *UserRepositoryDAO) delete(ctx context.Context, tx *gorm.DB, userID uuid
 	return nil
 }
 
+// deleteInstructorProfile removes the instructor row, its foreign key does not cascade
+func (s *UserRepositoryDAO) deleteInstructorProfile(ctx context.Context, tx *gorm.DB, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return tx.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&authdomain.Instructors{}).Error
+}
+
 // func (s *UserRepositoryDAO) softDelete(ctx context.Context, tx *gorm.DB, userID uuid.UUID) error {
 // 	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
 // 	defer cancel()
@@ -177,36 +615,59 @@ func (s *UserRepositoryDAO) createUserInvitation(ctx context.Context, tx *gorm.D
 	}).Error
 }
 
-func (s *UserRepositoryDAO) getUserFromInvitation(ctx context.Context, tx *gorm.DB, code string) (*authdomain.Users, error) {
-
-	var invitation authdomain.UserInvitations
+// otpAttempt is a pending code after spending one of its attempts
+type otpAttempt struct {
+	Matched  bool
+	Attempts int
+}
 
+// spendOTP consumes an attempt of every pending, unexpired code of the user and checks the code
+// against them. Updating first serializes concurrent guesses on the row locks. When no code
+// matches, those that reached maxAttempts are discarded and ErrInvalidOTP is returned.
+func (s *UserRepositoryDAO) spendOTP(ctx context.Context, tx *gorm.DB, table string, userID uuid.UUID, code string, maxAttempts int) error {
+	var attempts []otpAttempt
 	hash := sha256.Sum256([]byte(code))
 	hashCode := hex.EncodeToString(hash[:])
 
 	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
 	defer cancel()
 
-	result := tx.WithContext(ctx).
-		Preload("Users").
-		Where("token = ? AND expiry > ?", hashCode, time.Now()).
-		First(&invitation)
-
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, shared_errors.ErrNotFound
+	err := tx.WithContext(ctx).
+		Raw("UPDATE "+table+" SET attempts = attempts + 1 WHERE user_id = ? AND expiry > ? RETURNING token = ? AS matched, attempts",
+			userID, time.Now(), hashCode).
+		Scan(&attempts).Error
+	if err != nil {
+		return err
+	}
+	if len(attempts) == 0 {
+		return authdomain.ErrInvalidOTP
+	}
+	for _, attempt := range attempts {
+		if attempt.Matched && (maxAttempts <= 0 || attempt.Attempts <= maxAttempts) {
+			return nil
 		}
-		return nil, result.Error
 	}
 
-	return &invitation.Users, nil
+	if maxAttempts > 0 {
+		err := tx.WithContext(ctx).
+			Exec("DELETE FROM "+table+" WHERE user_id = ? AND attempts >= ?", userID, maxAttempts).Error
+		if err != nil {
+			return err
+		}
+	}
+	return authdomain.ErrInvalidOTP
 }
 
-func (s *UserRepositoryDAO) CreatePasswordResetToken(ctx context.Context, userID uuid.UUID, key string, tokenExp time.Duration) error {
+// CreatePasswordResetToken stores the reset token and queues the email carrying it
+func (s *UserRepositoryDAO) CreatePasswordResetToken(ctx context.Context, userID uuid.UUID, key string, tokenExp time.Duration, message *outboxdomain.Outbox) error {
 	return db.WithTX(s.db, func(tx *gorm.DB) error {
 		if err := s.userResetToken(ctx, tx, userID, key, tokenExp); err != nil {
 			return err //rollback
 		}
+		message.AttachTo(userID, key)
+		if err := s.outbox.Enqueue(ctx, tx, message); err != nil {
+			return err //rollback
+		}
 		return nil //commit
 
 	})
@@ -222,21 +683,30 @@ func (s *UserRepositoryDAO) DeleteResetToken(ctx context.Context, userID uuid.UU
 	})
 }
 
-func (s *UserRepositoryDAO) ResetUserPassword(ctx context.Context, key string, user *authdomain.Users) error {
-	return db.WithTX(s.db, func(tx *gorm.DB) error {
-		userReset, err := s.getUserResetToken(ctx, tx, key)
-		if err != nil {
-			return err
+// ResetUserPassword sets the password of user.UserID if the key matches one of its reset tokens.
+// A wrong key is counted against every pending token and discards those that reach maxAttempts.
+func (s *UserRepositoryDAO) ResetUserPassword(ctx context.Context, key string, user *authdomain.Users, maxAttempts int) error {
+	var invalid error
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		if err := s.spendOTP(ctx, tx, "password_reset_token", user.UserID, key, maxAttempts); err != nil {
+			if errors.Is(err, authdomain.ErrInvalidOTP) {
+				invalid = err
+				return nil //commit the spent attempt
+			}
+			return err //rollback
 		}
-		user.UserID = userReset.UserID
 		if err := tx.WithContext(ctx).Model(user).Select("password_hash").Updates(user).Error; err != nil {
-			return err
+			return err //rollback
 		}
 		if err := s.deleteUserReset(ctx, tx, user.UserID); err != nil {
-			return err
+			return err //rollback
 		}
-		return nil
+		return nil //commit
 	})
+	if err != nil {
+		return err
+	}
+	return invalid
 }
 
 func (s *UserRepositoryDAO) userResetToken(ctx context.Context, tx *gorm.DB, userID uuid.UUID, key string, tokenExp time.Duration) error {
@@ -263,26 +733,3 @@ func (s *UserRepositoryDAO) deleteUserReset(ctx context.Context, tx *gorm.DB, us
 	}
 	return nil
 }
-
-func (s *UserRepositoryDAO) getUserResetToken(ctx context.Context, tx *gorm.DB, key string) (*authdomain.Users, error) {
-	var resetToken authdomain.PasswordResetToken
-	hash := sha256.Sum256([]byte(key))
-	hashCode := hex.EncodeToString(hash[:])
-
-	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
-	defer cancel()
-
-	result := tx.WithContext(ctx).
-		Preload("Users").
-		Where("token = ? AND expiry > ?", hashCode, time.Now()).
-		First(&resetToken)
-
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, shared_errors.ErrNotFound
-		}
-		return nil, result.Error
-	}
-
-	return &resetToken.Users, nil
-}
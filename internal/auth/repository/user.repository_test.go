@@ -0,0 +1,463 @@
+package authrepository_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	authrepository "github.com/vitalfit/api/internal/auth/repository"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/internal/migrate/testdb/factory"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+func newUserRepository(t *testing.T) (*authrepository.UserRepositoryDAO, *factory.Factory) {
+	f := factory.New(t, testdb.New(t))
+	return authrepository.NewUserRepositoryDAO(f.DB), f
+}
+
+func TestCreateAndInvitate(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+
+	user := f.NewUser(func(u *authdomain.Users) { u.IsValidated = false })
+	token := factory.HashCode("123456")
+	message := f.Message("user_invitation", map[string]string{"Code": "123456"})
+
+	if err := repo.CreateAndInvitate(ctx, user, nil, token, time.Hour, message); err != nil {
+		t.Fatalf("CreateAndInvitate: %v", err)
+	}
+
+	if got := f.Count(&authdomain.Users{}, "user_id = ?", user.UserID); got != 1 {
+		t.Errorf("users = %d, want 1", got)
+	}
+	if got := f.Count(&authdomain.ClientProfiles{}, "user_id = ?", user.UserID); got != 1 {
+		t.Errorf("client profiles = %d, want 1", got)
+	}
+	if got := f.Count(&authdomain.UserInvitations{}, "user_id = ? AND expiry > now()", user.UserID); got != 1 {
+		t.Errorf("pending invitations = %d, want 1", got)
+	}
+	if got := f.Count(&outboxdomain.Outbox{}, "user_id = ?", user.UserID); got != 1 {
+		t.Errorf("queued messages = %d, want 1", got)
+	}
+}
+
+func TestCreateAndInvitateAssignsBranches(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+
+	branch := f.Branch()
+	user := f.NewUser(func(u *authdomain.Users) {
+		u.RoleID = f.Role("recepcionist").RoleID
+		u.ClientProfile = authdomain.ClientProfiles{}
+	})
+	message := f.Message("user_invitation", map[string]string{"Code": "123456"})
+
+	// an unknown branch rolls back the whole registration
+	err := repo.CreateAndInvitate(ctx, user, []uuid.UUID{branch.BranchID, uuid.New()}, factory.HashCode("123456"), time.Hour, message)
+	if err == nil {
+		t.Fatal("CreateAndInvitate with an unknown branch succeeded")
+	}
+	for _, model := range []interface{}{&authdomain.Users{}, &authdomain.UserInvitations{}, &outboxdomain.Outbox{}, &branchdomain.StaffBranches{}} {
+		if got := f.Count(model, "user_id = ?", user.UserID); got != 0 {
+			t.Errorf("%T rows = %d after the rollback, want 0", model, got)
+		}
+	}
+
+	user.UserID = uuid.Nil
+	message = f.Message("user_invitation", map[string]string{"Code": "123456"})
+	if err := repo.CreateAndInvitate(ctx, user, []uuid.UUID{branch.BranchID}, factory.HashCode("123456"), time.Hour, message); err != nil {
+		t.Fatalf("CreateAndInvitate: %v", err)
+	}
+	if got := f.Count(&branchdomain.StaffBranches{}, "user_id = ? AND branch_id = ?", user.UserID, branch.BranchID); got != 1 {
+		t.Errorf("branch assignments = %d, want 1", got)
+	}
+}
+
+func TestCreateAndInvitateRollsBackOnConflict(t *testing.T) {
+	tests := []struct {
+		name      string
+		duplicate func(existing *authdomain.Users) func(*authdomain.Users)
+	}{
+		{
+			name: "email",
+			duplicate: func(existing *authdomain.Users) func(*authdomain.Users) {
+				return func(u *authdomain.Users) { u.Email = existing.Email }
+			},
+		},
+		{
+			name: "email with another case",
+			duplicate: func(existing *authdomain.Users) func(*authdomain.Users) {
+				return func(u *authdomain.Users) { u.Email = strings.ToUpper(existing.Email) }
+			},
+		},
+		{
+			name: "identity document",
+			duplicate: func(existing *authdomain.Users) func(*authdomain.Users) {
+				return func(u *authdomain.Users) { u.IdentityDocument = existing.IdentityDocument }
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, f := newUserRepository(t)
+			ctx := context.Background()
+
+			existing := f.User()
+			user := f.NewUser(tt.duplicate(existing))
+			message := f.Message("user_invitation", map[string]string{"Code": "654321"})
+
+			err := repo.CreateAndInvitate(ctx, user, nil, factory.HashCode("654321"), time.Hour, message)
+			if !errors.Is(err, shared_errors.ErrConflict) {
+				t.Fatalf("CreateAndInvitate = %v, want ErrConflict", err)
+			}
+
+			if got := f.Count(&authdomain.Users{}, "true"); got != 1 {
+				t.Errorf("users = %d, want only the existing one", got)
+			}
+			if got := f.Count(&authdomain.ClientProfiles{}, "true"); got != 1 {
+				t.Errorf("client profiles = %d, want only the existing one", got)
+			}
+			if got := f.Count(&authdomain.UserInvitations{}, "true"); got != 0 {
+				t.Errorf("invitations = %d, want 0", got)
+			}
+			if got := f.Count(&outboxdomain.Outbox{}, "true"); got != 0 {
+				t.Errorf("queued messages = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestEmailIsCaseInsensitive(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+
+	user := f.User(func(u *authdomain.Users) { u.Email = "Ana.Perez@VitalFit.test" })
+
+	found, err := repo.GetByEmail(ctx, "ana.perez@vitalfit.test")
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	if found.UserID != user.UserID {
+		t.Errorf("GetByEmail returned %s, want %s", found.UserID, user.UserID)
+	}
+
+	err = repo.Create(ctx, f.DB, f.NewUser(func(u *authdomain.Users) { u.Email = "ANA.PEREZ@vitalfit.TEST" }))
+	if !errors.Is(err, shared_errors.ErrConflict) {
+		t.Errorf("Create with the email in another case = %v, want ErrConflict", err)
+	}
+}
+
+func TestActivate(t *testing.T) {
+	const code = "482913"
+
+	tests := []struct {
+		name        string
+		expiresIn   time.Duration
+		code        string
+		wantErr     error
+		validated   bool
+		invitations int64
+	}{
+		{name: "valid code", expiresIn: time.Hour, code: code, validated: true, invitations: 0},
+		{name: "expired code", expiresIn: -time.Minute, code: code, wantErr: authdomain.ErrInvalidOTP, invitations: 1},
+		{name: "wrong code", expiresIn: time.Hour, code: "000000", wantErr: authdomain.ErrInvalidOTP, invitations: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, f := newUserRepository(t)
+			ctx := context.Background()
+
+			user := f.User(func(u *authdomain.Users) { u.IsValidated = false })
+			f.Invitation(user, code, tt.expiresIn)
+
+			err := repo.Activate(ctx, user.UserID, tt.code, 5)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Activate = %v, want %v", err, tt.wantErr)
+			}
+
+			validated := f.Count(&authdomain.Users{}, "user_id = ? AND is_validated", user.UserID) == 1
+			if validated != tt.validated {
+				t.Errorf("validated = %v, want %v", validated, tt.validated)
+			}
+			if got := f.Count(&authdomain.UserInvitations{}, "user_id = ?", user.UserID); got != tt.invitations {
+				t.Errorf("invitations = %d, want %d", got, tt.invitations)
+			}
+		})
+	}
+}
+
+func TestActivateDiscardsCodeAfterMaxAttempts(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+	const code, maxAttempts = "482913", 3
+
+	user := f.User(func(u *authdomain.Users) { u.IsValidated = false })
+	f.Invitation(user, code, time.Hour)
+
+	for i := 0; i < maxAttempts; i++ {
+		if err := repo.Activate(ctx, user.UserID, "000000", maxAttempts); !errors.Is(err, authdomain.ErrInvalidOTP) {
+			t.Fatalf("wrong attempt %d = %v, want ErrInvalidOTP", i+1, err)
+		}
+	}
+	if got := f.Count(&authdomain.UserInvitations{}, "user_id = ?", user.UserID); got != 0 {
+		t.Fatalf("invitations after %d wrong codes = %d, want 0", maxAttempts, got)
+	}
+
+	if err := repo.Activate(ctx, user.UserID, code, maxAttempts); !errors.Is(err, authdomain.ErrInvalidOTP) {
+		t.Errorf("Activate with the discarded code = %v, want ErrInvalidOTP", err)
+	}
+}
+
+func TestResetUserPassword(t *testing.T) {
+	const code = "731904"
+
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		code      string
+		wantErr   error
+		changed   bool
+		tokens    int64
+	}{
+		{name: "valid code", expiresIn: time.Hour, code: code, changed: true, tokens: 0},
+		{name: "expired code", expiresIn: -time.Minute, code: code, wantErr: authdomain.ErrInvalidOTP, tokens: 1},
+		{name: "wrong code", expiresIn: time.Hour, code: "000000", wantErr: authdomain.ErrInvalidOTP, tokens: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, f := newUserRepository(t)
+			ctx := context.Background()
+
+			user := f.User()
+			f.ResetToken(user, code, tt.expiresIn)
+
+			if err := user.PasswordHash.Set("NewPassword456!"); err != nil {
+				t.Fatal(err)
+			}
+			err := repo.ResetUserPassword(ctx, tt.code, user, 5)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResetUserPassword = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, err := repo.GetByID(ctx, user.UserID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changed, err := stored.PasswordHash.Matches("NewPassword456!")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.changed {
+				t.Errorf("password changed = %v, want %v", changed, tt.changed)
+			}
+			if got := f.Count(&authdomain.PasswordResetToken{}, "user_id = ?", user.UserID); got != tt.tokens {
+				t.Errorf("reset tokens = %d, want %d", got, tt.tokens)
+			}
+		})
+	}
+}
+
+func TestDeleteRemovesPendingCodes(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+
+	user := f.User()
+	f.Invitation(user, "111111", time.Hour)
+	f.ResetToken(user, "222222", time.Hour)
+
+	if err := repo.Delete(ctx, user.UserID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, user.UserID); !errors.Is(err, shared_errors.ErrNotFound) {
+		t.Errorf("GetByID after Delete = %v, want ErrNotFound", err)
+	}
+	if got := f.Count(&authdomain.UserInvitations{}, "user_id = ?", user.UserID); got != 0 {
+		t.Errorf("invitations = %d, want 0", got)
+	}
+	if got := f.Count(&authdomain.PasswordResetToken{}, "user_id = ?", user.UserID); got != 0 {
+		t.Errorf("reset tokens = %d, want 0", got)
+	}
+}
+
+func TestListBranchScope(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+
+	branch, other := f.Branch(), f.Branch()
+	staff := f.Staff("recepcionist")
+	f.AssignStaff(staff, branch)
+	client := f.User()
+	f.Checkin(client, branch)
+	outsider := f.User()
+	f.Checkin(outsider, other)
+
+	tests := []struct {
+		name  string
+		scope []uuid.UUID
+		want  []uuid.UUID
+	}{
+		{name: "branch", scope: []uuid.UUID{branch.BranchID}, want: []uuid.UUID{staff.UserID, client.UserID}},
+		{name: "other branch", scope: []uuid.UUID{other.BranchID}, want: []uuid.UUID{outsider.UserID}},
+		{name: "no branches", scope: nil, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := authdomain.NewUserFilter(authdomain.UserListQuery{})
+			if err != nil {
+				t.Fatalf("NewUserFilter: %v", err)
+			}
+			filter.Scoped, filter.BranchScope = true, tt.scope
+
+			users, total, err := repo.List(ctx, filter)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if total != int64(len(tt.want)) {
+				t.Errorf("total = %d, want %d", total, len(tt.want))
+			}
+			got := make(map[uuid.UUID]bool, len(users))
+			for _, user := range users {
+				got[user.UserID] = true
+			}
+			for _, id := range tt.want {
+				if !got[id] {
+					t.Errorf("user %s missing from the page", id)
+				}
+				ok, err := repo.InBranches(ctx, id, tt.scope)
+				if err != nil || !ok {
+					t.Errorf("InBranches(%s) = %v, %v, want true", id, ok, err)
+				}
+			}
+		})
+	}
+
+	ok, err := repo.InBranches(ctx, outsider.UserID, []uuid.UUID{branch.BranchID})
+	if err != nil || ok {
+		t.Errorf("InBranches(outsider) = %v, %v, want false", ok, err)
+	}
+}
+
+func TestInvitationOwner(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+	const code = "482913"
+
+	user := f.User(func(u *authdomain.Users) { u.IsValidated = false })
+	f.Invitation(user, code, time.Hour)
+	expired := f.User(func(u *authdomain.Users) { u.IsValidated = false })
+	f.Invitation(expired, "731502", -time.Minute)
+
+	tests := []struct {
+		name    string
+		email   string
+		code    string
+		want    uuid.UUID
+		wantErr error
+	}{
+		{name: "by email", email: user.Email, code: "000000", want: user.UserID},
+		{name: "by code", code: code, want: user.UserID},
+		{name: "unknown code", code: "000000", wantErr: shared_errors.ErrNotFound},
+		{name: "expired code", code: "731502", wantErr: shared_errors.ErrNotFound},
+		{name: "email without invitation", email: f.User().Email, code: code, wantErr: shared_errors.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.InvitationOwner(ctx, tt.email, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InvitationOwner = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("InvitationOwner = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetClientStatusRecordsThePreviousStatus(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+	client := f.User()
+
+	steps := []struct {
+		status   authdomain.ClientStatusEnum
+		previous authdomain.ClientStatusEnum
+	}{
+		{status: authdomain.ClientStatusBlocked, previous: authdomain.ClientStatusActive},
+		{status: authdomain.ClientStatusActive, previous: authdomain.ClientStatusBlocked},
+	}
+	for _, step := range steps {
+		change := &authdomain.ClientStatusHistory{UserID: client.UserID, NewStatus: step.status, Justification: "repeated no-shows"}
+		if err := repo.SetClientStatus(ctx, change); err != nil {
+			t.Fatalf("SetClientStatus(%s): %v", step.status, err)
+		}
+		if change.PreviousStatus != step.previous {
+			t.Errorf("previous status = %s, want %s", change.PreviousStatus, step.previous)
+		}
+	}
+
+	history, err := repo.ListStatusHistory(ctx, client.UserID)
+	if err != nil {
+		t.Fatalf("ListStatusHistory: %v", err)
+	}
+	// both changes fall in the same second, so the rows are compared in any order
+	recorded := map[authdomain.ClientStatusEnum]authdomain.ClientStatusEnum{}
+	for _, row := range history {
+		recorded[row.NewStatus] = row.PreviousStatus
+	}
+	if len(history) != len(steps) {
+		t.Fatalf("history rows = %d, want %d", len(history), len(steps))
+	}
+	for _, step := range steps {
+		if recorded[step.status] != step.previous {
+			t.Errorf("history %s -> %s missing, got %v", step.previous, step.status, recorded)
+		}
+	}
+}
+
+func TestCreateEmailChangeResetsAttempts(t *testing.T) {
+	repo, f := newUserRepository(t)
+	ctx := context.Background()
+	const maxAttempts = 3
+
+	user := f.User()
+	request := func(code string) {
+		change := &authdomain.EmailChangeRequests{
+			UserID:   user.UserID,
+			Token:    factory.HashCode(code),
+			NewEmail: "new-" + user.Email,
+			Expiry:   time.Now().Add(time.Hour),
+		}
+		if err := repo.CreateEmailChange(ctx, change, f.Message("email_change", map[string]string{"Code": code})); err != nil {
+			t.Fatalf("CreateEmailChange: %v", err)
+		}
+	}
+
+	request("111111")
+	for i := 0; i < maxAttempts-1; i++ {
+		if _, err := repo.ConfirmEmailChange(ctx, user.UserID, "000000", maxAttempts); !errors.Is(err, authdomain.ErrInvalidOTP) {
+			t.Fatalf("wrong attempt %d = %v, want ErrInvalidOTP", i+1, err)
+		}
+	}
+
+	// the new code starts with every attempt available again
+	request("222222")
+	if got := f.Count(&authdomain.EmailChangeRequests{}, "user_id = ? AND attempts = 0", user.UserID); got != 1 {
+		t.Fatalf("requests with no attempts spent = %d, want 1", got)
+	}
+	if _, err := repo.ConfirmEmailChange(ctx, user.UserID, "000000", maxAttempts); !errors.Is(err, authdomain.ErrInvalidOTP) {
+		t.Fatalf("wrong attempt on the new code = %v, want ErrInvalidOTP", err)
+	}
+	if _, err := repo.ConfirmEmailChange(ctx, user.UserID, "222222", maxAttempts); err != nil {
+		t.Errorf("ConfirmEmailChange with the new code: %v", err)
+	}
+}
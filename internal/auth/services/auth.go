@@ -2,14 +2,28 @@ package authservices
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
 	"github.com/vitalfit/api/internal/store"
+	"github.com/vitalfit/api/pkg/mailer"
+	"github.com/vitalfit/api/pkg/notifier"
+	"github.com/vitalfit/api/pkg/otp"
 )
 
+// mfaChallengeType marks challenge tokens, access tokens carry no type and a session id instead
+const mfaChallengeType = "mfa_challenge"
+
 type AuthService struct {
 	store store.Storage
 }
@@ -20,7 +34,8 @@ func NewAuthServices(store store.Storage) *AuthService {
 	}
 }
 
-func (s *AuthService) RegisterUserClient(ctx context.Context, user *authdomain.Users, token string) error {
+// RegisterUserClient creates the client and queues its invitation with the activation code through the channel
+func (s *AuthService) RegisterUserClient(ctx context.Context, user *authdomain.Users, code string, channel notifier.Channel) error {
 	role, error := s.store.Roles.GetByName(ctx, "client")
 	client_profile := &authdomain.ClientProfiles{
 		UserID:   user.UserID,
@@ -31,44 +46,44 @@ func (s *AuthService) RegisterUserClient(ctx context.Context, user *authdomain.U
 	}
 	user.RoleID = role.RoleID
 	user.ClientProfile = *client_profile
-	if err := s.store.Users.CreateAndInvitate(ctx, user, token, s.store.Config.Mail.Exp); err != nil {
-		return err
-	}
-	return nil
+	return s.createAndInvitate(ctx, user, code, nil, channel)
 }
 
-func (s *AuthService) RegisterUserStaff(ctx context.Context, user *authdomain.Users, token string, roleName string) error {
+// RegisterUserStaff creates the staff member assigned to the branches and queues its invitation with the
+// activation code through the channel
+func (s *AuthService) RegisterUserStaff(ctx context.Context, actor *authdomain.Users, user *authdomain.Users, code string, roleName string, branchIDs []uuid.UUID, channel notifier.Channel) error {
 	role, error := s.store.Roles.GetByName(ctx, roleName)
 	if error != nil {
 		return error
 	}
+	if !actor.CanAssign(role) {
+		return authdomain.ErrRoleEscalation
+	}
 	user.RoleID = role.RoleID
-	if err := s.store.Users.CreateAndInvitate(ctx, user, token, s.store.Config.Mail.Exp); err != nil {
-		return err
+	// instructors get their public profile in the same transaction as the account
+	if role.Name == "instructor" {
+		if user.InstructorProfile == nil {
+			user.InstructorProfile = &authdomain.Instructors{}
+		}
+	} else {
+		user.InstructorProfile = nil
 	}
-	return nil
+	return s.createAndInvitate(ctx, user, code, branchIDs, channel)
 }
 
-func (h *AuthService) MailSender(ctx context.Context, user *authdomain.Users, key string, template string) (int, error) {
-
-	//mail -> fail -> roll back -> create invite
-
-	isProdEnv := h.store.Env == "production"
-	vars := struct {
-		Username string
-		CODE     string
-	}{
-		Username: user.FirstName,
-		CODE:     key,
+func (s *AuthService) createAndInvitate(ctx context.Context, user *authdomain.Users, code string, branchIDs []uuid.UUID, channel notifier.Channel) error {
+	if user.PreferredLanguage == "" {
+		user.PreferredLanguage = s.store.Config.Mail.DefaultLocale
 	}
-
-	// send mail
-	status, err := h.store.Mailer.Send(template, user.FirstName, user.Email, vars, !isProdEnv)
+	message, phone, err := s.codeMessage(channel, mailer.UserWelcomeTemplate, user, code)
 	if err != nil {
-		return status, err
+		return err
 	}
-
-	return status, err
+	// the account keeps the number the invitation went to
+	if phone != "" {
+		user.Phone = phone
+	}
+	return s.store.Users.CreateAndInvitate(ctx, user, branchIDs, hashCode(code), s.store.Config.Mail.Exp, message)
 }
 
 // rollbacks user creations if transaction fails
@@ -79,12 +94,44 @@ func (h *AuthService) Delete(ctx context.Context, userID uuid.UUID) error {
 	return nil
 }
 
-func (h *AuthService) Activate(ctx context.Context, code string) error {
-	if err := h.store.Users.Activate(ctx, code); err != nil {
-		return err
-	}
-	return nil
+// Activate validates the account with the code it was sent, the account is the one of the email
+// or the one the code was sent to when the email is missing. Wrong codes count towards the lockout
+// of the email from the address and discard the code after too many tries.
+func (h *AuthService) Activate(ctx context.Context, payload authdomain.ActivatePayload, ipAddress string) error {
+	return NewLockoutService(h.store).Guard(ctx, authdomain.AttemptScopeActivate, payload.Email, ipAddress, func() error {
+		userID, err := h.store.Users.InvitationOwner(ctx, payload.Email, payload.Code)
+		if err != nil {
+			if errors.Is(err, shared_errors.ErrNotFound) {
+				return authdomain.ErrInvalidOTP
+			}
+			return err
+		}
+		return h.store.Users.Activate(ctx, userID, payload.Code, h.store.Config.Auth.Lockout.MaxOTPAttempts)
+	}, authdomain.ErrInvalidOTP)
+}
 
+// Authenticate checks the credentials of a login, failures count towards the lockout of the email from the address
+func (h *AuthService) Authenticate(ctx context.Context, payload authdomain.CreateUserTokenPayload, ipAddress string) (*authdomain.Users, error) {
+	var user *authdomain.Users
+	err := NewLockoutService(h.store).Guard(ctx, authdomain.AttemptScopeLogin, payload.Email, ipAddress, func() error {
+		found, err := h.store.Users.GetByEmail(ctx, payload.Email)
+		if err != nil {
+			return err
+		}
+		match, err := found.PasswordHash.Matches(payload.Password)
+		if err != nil {
+			return err
+		}
+		if !match {
+			return authdomain.ErrInvalidCredentials
+		}
+		user = found
+		return nil
+	}, shared_errors.ErrNotFound, authdomain.ErrInvalidCredentials)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func (h *AuthService) GetByEmail(ctx context.Context, email string) (*authdomain.Users, error) {
@@ -95,30 +142,24 @@ func (h *AuthService) GetByEmail(ctx context.Context, email string) (*authdomain
 	return users, nil
 }
 
-func (h *AuthService) CreatePasswordResetToken(ctx context.Context, email string, key string) error {
-	user, err := h.store.Users.GetByEmail(ctx, email)
+// CreatePasswordResetToken stores the reset code of the user and queues the message carrying it through the channel
+func (h *AuthService) CreatePasswordResetToken(ctx context.Context, user *authdomain.Users, code string, channel notifier.Channel) error {
+	message, _, err := h.codeMessage(channel, mailer.UserResetPwsTemplate, user, code)
 	if err != nil {
 		return err
 	}
-	if err := h.store.Users.CreatePasswordResetToken(ctx, user.UserID, key, h.store.Config.Mail.Exp); err != nil {
-		return err
-	}
-	return nil
+	return h.store.Users.CreatePasswordResetToken(ctx, user.UserID, hashCode(code), h.store.Config.Mail.Exp, message)
 }
 
 func (h *AuthService) DeleteResetToken(ctx context.Context, userID uuid.UUID) error {
-	err := h.store.Users.Delete(ctx, userID)
-	if err != nil {
-		return err
-	}
-	return nil
-
+	return h.store.Users.DeleteResetToken(ctx, userID)
 }
 
-func (h *AuthService) GenerateToken(user *authdomain.Users) (string, error) {
+func (h *AuthService) GenerateToken(user *authdomain.Users, sessionID uuid.UUID) (string, error) {
 	// generate the token -> add claims
 	claims := jwt.MapClaims{
 		"sub": user.UserID,
+		"sid": sessionID,
 		"exp": time.Now().Add(h.store.Config.Auth.Token.Exp).Unix(),
 		"iat": time.Now().Unix(),
 		"nbf": time.Now().Unix(),
@@ -137,9 +178,326 @@ func (h *AuthService) ValidateToken(token string) (*jwt.Token, error) {
 	return h.store.Auth.ValidateToken(token)
 }
 
-func (h *AuthService) ResetPassword(ctx context.Context, key string, user *authdomain.Users) error {
-	if err := h.store.Users.ResetUserPassword(ctx, key, user); err != nil {
+// ResetPassword sets a new password with the code sent by forgot password, for the user of the
+// email or the one the code was sent to when the email is missing. Wrong codes count towards the
+// lockout of the email from the address and discard the code after too many tries.
+func (h *AuthService) ResetPassword(ctx context.Context, payload authdomain.ResetPasswordPayload, ipAddress string) (*authdomain.Users, error) {
+	var user *authdomain.Users
+	err := NewLockoutService(h.store).Guard(ctx, authdomain.AttemptScopePasswordReset, payload.Email, ipAddress, func() error {
+		userID, err := h.store.Users.ResetTokenOwner(ctx, payload.Email, payload.Token)
+		if err == nil {
+			user, err = h.store.Users.GetByID(ctx, userID)
+		}
+		if err != nil {
+			if errors.Is(err, shared_errors.ErrNotFound) {
+				return authdomain.ErrInvalidOTP
+			}
+			return err
+		}
+		if err := user.PasswordHash.Set(payload.Password); err != nil {
+			return err
+		}
+		return h.store.Users.ResetUserPassword(ctx, payload.Token, user, h.store.Config.Auth.Lockout.MaxOTPAttempts)
+	}, authdomain.ErrInvalidOTP)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangePassword replaces the password of the user and signs out every other session
+func (h *AuthService) ChangePassword(ctx context.Context, user *authdomain.Users, sessionID uuid.UUID, payload authdomain.ChangePasswordPayload) error {
+	match, err := user.PasswordHash.Matches(payload.CurrentPassword)
+	if err != nil {
 		return err
 	}
-	return nil
+	if !match {
+		return authdomain.ErrWrongPassword
+	}
+	if err := user.PasswordHash.Set(payload.Password); err != nil {
+		return err
+	}
+	if err := h.store.Users.UpdatePassword(ctx, user); err != nil {
+		return err
+	}
+	return h.store.RefreshTokens.RevokeOthers(ctx, user.UserID, sessionID)
+}
+
+// RequestEmailChange sends a confirmation code to the new address, the email is not changed until it is confirmed
+func (h *AuthService) RequestEmailChange(ctx context.Context, user *authdomain.Users, payload authdomain.ChangeEmailPayload) error {
+	match, err := user.PasswordHash.Matches(payload.CurrentPassword)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return authdomain.ErrWrongPassword
+	}
+	if strings.EqualFold(payload.NewEmail, user.Email) {
+		return authdomain.ErrSameEmail
+	}
+	if _, err := h.store.Users.GetByEmail(ctx, payload.NewEmail); err == nil {
+		return shared_errors.ErrConflict
+	} else if !errors.Is(err, shared_errors.ErrNotFound) {
+		return err
+	}
+
+	key, err := otp.GenerateCode(6)
+	if err != nil {
+		return err
+	}
+	change := &authdomain.EmailChangeRequests{
+		UserID:    user.UserID,
+		Token:     hashCode(key),
+		NewEmail:  payload.NewEmail,
+		Expiry:    time.Now().Add(h.store.Config.Mail.Exp),
+		CreatedAt: time.Now(),
+	}
+	message, err := h.emailMessage(mailer.UserEmailChangeTemplate, user, payload.NewEmail, codeEmailVars{
+		Username: user.FirstName,
+		CODE:     key,
+	})
+	if err != nil {
+		return err
+	}
+	return h.store.Users.CreateEmailChange(ctx, change, message)
+}
+
+// ConfirmEmailChange switches the email of the user once the code is confirmed and returns the consumed request
+func (h *AuthService) ConfirmEmailChange(ctx context.Context, user *authdomain.Users, code string) (*authdomain.EmailChangeRequests, error) {
+	change, err := h.store.Users.ConfirmEmailChange(ctx, user.UserID, code, h.store.Config.Auth.Lockout.MaxOTPAttempts)
+	if err != nil {
+		return nil, err
+	}
+	user.Email = change.NewEmail
+	return change, nil
+}
+
+// NotifyEmailChanged queues the warning to the previous address that the account email changed
+func (h *AuthService) NotifyEmailChanged(ctx context.Context, user *authdomain.Users, change *authdomain.EmailChangeRequests, previousEmail string) error {
+	vars := struct {
+		Username string
+		NewEmail string
+	}{
+		Username: user.FirstName,
+		NewEmail: user.Email,
+	}
+	message, err := h.emailMessage(mailer.UserEmailChangedTemplate, user, previousEmail, vars)
+	if err != nil {
+		return err
+	}
+	message.UserID = &user.UserID
+	// keyed on the consumed request, a retried call queues the warning once and a later change back
+	// to the same addresses still gets its own
+	message.IdempotencyKey = outboxdomain.IdempotencyKey(message.Template, user.UserID.String(), change.Token, change.CreatedAt.UTC().Format(time.RFC3339Nano))
+	return h.store.Outbox.Enqueue(ctx, nil, message)
+}
+
+// codeEmailVars are the template values of the emails that carry a one time code
+type codeEmailVars struct {
+	Username string
+	CODE     string
+}
+
+// emailMessage seals the template values into an outbox message for the dispatcher, rendered later
+// in the preferred language of the user
+func (h *AuthService) emailMessage(template string, user *authdomain.Users, email string, vars any) (*outboxdomain.Outbox, error) {
+	return h.message(notifier.ChannelEmail, template, user, email, vars)
+}
+
+// codeMessage queues a one time code through the channel the user chose, email when none was.
+// SMS and WhatsApp go to the phone of the user normalized so the provider takes it, which is
+// returned along with the message; the user is left as it is.
+func (h *AuthService) codeMessage(channel notifier.Channel, template string, user *authdomain.Users, code string) (*outboxdomain.Outbox, string, error) {
+	vars := codeEmailVars{
+		Username: user.FirstName,
+		CODE:     code,
+	}
+	if channel == "" || channel == notifier.ChannelEmail {
+		message, err := h.emailMessage(template, user, user.Email, vars)
+		return message, "", err
+	}
+
+	if !h.store.Notifier.Supports(channel) {
+		return nil, "", notifier.ErrChannelUnavailable
+	}
+	phone, err := notifier.NormalizePhone(user.Phone)
+	if err != nil {
+		return nil, "", err
+	}
+	message, err := h.message(channel, template, user, phone, vars)
+	if err != nil {
+		return nil, "", err
+	}
+	return message, phone, nil
+}
+
+func (h *AuthService) message(channel notifier.Channel, template string, user *authdomain.Users, recipient string, vars any) (*outboxdomain.Outbox, error) {
+	locale := user.PreferredLanguage
+	if locale == "" {
+		locale = h.store.Config.Mail.DefaultLocale
+	}
+	return outboxdomain.NewMessage(h.store.Secrets, string(channel), template, locale, user.FirstName, recipient, vars, h.store.Config.Outbox.Retry.MaxAttempts)
+}
+
+// hashCode returns what is stored in place of a one time code
+func hashCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+// NewMFAChallenge returns the short lived token login hands out instead of a session
+// when the account has two-factor enabled
+func (h *AuthService) NewMFAChallenge(user *authdomain.Users) (*authdomain.MFAChallenge, error) {
+	if user.IsBlocked() {
+		return nil, shared_errors.ErrClientBlocked
+	}
+	exp := h.store.Config.Auth.MFA.ChallengeExp
+	claims := jwt.MapClaims{
+		"sub": user.UserID,
+		"typ": mfaChallengeType,
+		"exp": time.Now().Add(exp).Unix(),
+		"iat": time.Now().Unix(),
+		"nbf": time.Now().Unix(),
+		"iss": h.store.Config.Auth.Token.Iss,
+		"aud": h.store.Config.Auth.Token.Iss,
+	}
+	token, err := h.store.Auth.GenerateToken(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &authdomain.MFAChallenge{
+		MFARequired:    true,
+		ChallengeToken: token,
+		ExpiresIn:      int64(exp.Seconds()),
+	}, nil
+}
+
+// CompleteMFALogin exchanges a challenge token and a TOTP or recovery code for a new session
+func (h *AuthService) CompleteMFALogin(ctx context.Context, payload authdomain.MFALoginPayload, meta authdomain.SessionMeta) (*authdomain.TokenPair, error) {
+	token, err := h.store.Auth.ValidateToken(payload.ChallengeToken)
+	if err != nil {
+		return nil, shared_errors.ErrInvalidToken
+	}
+	claims, _ := token.Claims.(jwt.MapClaims)
+	if typ, _ := claims["typ"].(string); typ != mfaChallengeType {
+		return nil, shared_errors.ErrInvalidToken
+	}
+	sub, _ := claims["sub"].(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return nil, shared_errors.ErrInvalidToken
+	}
+
+	user, err := h.store.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = NewLockoutService(h.store).Guard(ctx, authdomain.AttemptScopeMFA, user.Email, meta.IPAddress, func() error {
+		return NewMFAService(h.store).Verify(ctx, user, payload.Code)
+	}, authdomain.ErrInvalidMFACode)
+	if err != nil {
+		return nil, err
+	}
+	return h.IssueTokens(ctx, user, meta)
+}
+
+// IssueTokens opens a new session for the user and returns its first token pair
+func (h *AuthService) IssueTokens(ctx context.Context, user *authdomain.Users, meta authdomain.SessionMeta) (*authdomain.TokenPair, error) {
+	if user.IsBlocked() {
+		return nil, shared_errors.ErrClientBlocked
+	}
+	refreshToken, tokenHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &authdomain.RefreshTokens{
+		TokenID:   uuid.New(),
+		TokenHash: tokenHash,
+		UserID:    user.UserID,
+		FamilyID:  uuid.New(),
+		UserAgent: meta.UserAgent,
+		IPAddress: meta.IPAddress,
+		ExpiresAt: time.Now().Add(h.store.Config.Auth.Refresh.Exp),
+	}
+	if err := h.store.RefreshTokens.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return h.tokenPair(user, session.FamilyID, refreshToken)
+}
+
+// RefreshTokens rotates the refresh token and issues a new access token for the same session
+func (h *AuthService) RefreshTokens(ctx context.Context, refreshToken string, meta authdomain.SessionMeta) (*authdomain.TokenPair, error) {
+	nextToken, nextHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	next := &authdomain.RefreshTokens{
+		TokenID:   uuid.New(),
+		TokenHash: nextHash,
+		UserAgent: meta.UserAgent,
+		IPAddress: meta.IPAddress,
+		ExpiresAt: time.Now().Add(h.store.Config.Auth.Refresh.Exp),
+	}
+	if _, err := h.store.RefreshTokens.Rotate(ctx, hashRefreshToken(refreshToken), next); err != nil {
+		return nil, err
+	}
+
+	user, err := h.store.Users.GetByID(ctx, next.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBlocked() {
+		return nil, shared_errors.ErrClientBlocked
+	}
+
+	return h.tokenPair(user, next.FamilyID, nextToken)
+}
+
+// Logout revokes the session the refresh token belongs to
+func (h *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	token, err := h.store.RefreshTokens.GetByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	return h.store.RefreshTokens.RevokeFamily(ctx, token.FamilyID)
+}
+
+// LogoutAll revokes every session of the user
+func (h *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	return h.store.RefreshTokens.RevokeAllForUser(ctx, userID)
+}
+
+func (h *AuthService) IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	return h.store.RefreshTokens.IsFamilyActive(ctx, sessionID)
+}
+
+func (h *AuthService) tokenPair(user *authdomain.Users, sessionID uuid.UUID, refreshToken string) (*authdomain.TokenPair, error) {
+	accessToken, err := h.GenerateToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &authdomain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(h.store.Config.Auth.Token.Exp.Seconds()),
+	}, nil
+}
+
+// newRefreshToken returns an opaque token and the hash that is persisted
+func newRefreshToken() (string, []byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
 }
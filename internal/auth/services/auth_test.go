@@ -0,0 +1,73 @@
+package authservices
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vitalfit/api/config"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/internal/store"
+	"github.com/vitalfit/api/pkg/mailer"
+	"github.com/vitalfit/api/pkg/notifier"
+	"github.com/vitalfit/api/pkg/secretbox"
+)
+
+func TestCodeMessage(t *testing.T) {
+	box, err := secretbox.New("test-passphrase")
+	if err != nil {
+		t.Fatalf("secretbox.New: %v", err)
+	}
+	n, err := notifier.New(notifier.Config{BrandName: "VitalFit", DefaultLocale: "es"}, nil, nil)
+	if err != nil {
+		t.Fatalf("notifier.New: %v", err)
+	}
+	n.WithProvider(notifier.ChannelSMS, notifier.NewFakeProvider())
+
+	var cfg config.Config
+	cfg.Mail.DefaultLocale = "es"
+	cfg.Outbox.Retry.MaxAttempts = 3
+	h := NewAuthServices(store.Storage{Config: cfg, Notifier: n, Secrets: box})
+
+	tests := []struct {
+		name          string
+		channel       notifier.Channel
+		phone         string
+		wantKind      notifier.Channel
+		wantRecipient string
+		wantPhone     string
+		err           error
+	}{
+		{name: "email by default", phone: "+58 412 1234567", wantKind: notifier.ChannelEmail, wantRecipient: "ana@example.com"},
+		{name: "email", channel: notifier.ChannelEmail, wantKind: notifier.ChannelEmail, wantRecipient: "ana@example.com"},
+		{name: "sms to the normalized phone", channel: notifier.ChannelSMS, phone: "+58 412-123.45.67", wantKind: notifier.ChannelSMS, wantRecipient: "+584121234567", wantPhone: "+584121234567"},
+		{name: "sms without a phone", channel: notifier.ChannelSMS, err: notifier.ErrInvalidPhone},
+		{name: "sms to a local number", channel: notifier.ChannelSMS, phone: "04121234567", err: notifier.ErrInvalidPhone},
+		{name: "channel without a provider", channel: notifier.ChannelWhatsApp, phone: "+584121234567", err: notifier.ErrChannelUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &authdomain.Users{FirstName: "Ana", Email: "ana@example.com", Phone: tt.phone}
+
+			message, phone, err := h.codeMessage(tt.channel, mailer.UserResetPwsTemplate, user, "123456")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("codeMessage error = %v, want %v", err, tt.err)
+			}
+			if user.Phone != tt.phone {
+				t.Errorf("user phone changed to %q", user.Phone)
+			}
+			if tt.err != nil {
+				return
+			}
+			if phone != tt.wantPhone {
+				t.Errorf("phone = %q, want %q", phone, tt.wantPhone)
+			}
+			if message.Kind != string(tt.wantKind) || message.Recipient != tt.wantRecipient || message.Locale != "es" {
+				t.Errorf("message = %s to %q in %q, want %s to %q in es", message.Kind, message.Recipient, message.Locale, tt.wantKind, tt.wantRecipient)
+			}
+			data, err := message.Data(box)
+			if err != nil || data["CODE"] != "123456" {
+				t.Errorf("payload = %v, %v, want the code sealed in it", data, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package authservices
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type InstructorService struct {
+	store store.Storage
+}
+
+func NewInstructorService(store store.Storage) *InstructorService {
+	return &InstructorService{
+		store: store,
+	}
+}
+
+// Create adds the profile of a user that already holds the instructor role
+func (s *InstructorService) Create(ctx context.Context, instructor *authdomain.Instructors) error {
+	user, err := s.store.Users.GetByID(ctx, instructor.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Role.Name != "instructor" {
+		return authdomain.ErrNotInstructor
+	}
+	if err := s.store.Instructors.Create(ctx, instructor); err != nil {
+		return err
+	}
+	instructor.User = user
+	return nil
+}
+
+func (s *InstructorService) GetByID(ctx context.Context, instructorID uuid.UUID) (*authdomain.Instructors, error) {
+	return s.store.Instructors.GetByID(ctx, instructorID)
+}
+
+func (s *InstructorService) GetByUserID(ctx context.Context, userID uuid.UUID) (*authdomain.Instructors, error) {
+	return s.store.Instructors.GetByUserID(ctx, userID)
+}
+
+func (s *InstructorService) List(ctx context.Context, filter authdomain.InstructorFilter) ([]authdomain.Instructors, error) {
+	return s.store.Instructors.List(ctx, filter)
+}
+
+func (s *InstructorService) Update(ctx context.Context, instructor *authdomain.Instructors) error {
+	return s.store.Instructors.Update(ctx, instructor)
+}
+
+func (s *InstructorService) Delete(ctx context.Context, instructorID uuid.UUID) error {
+	return s.store.Instructors.Delete(ctx, instructorID)
+}
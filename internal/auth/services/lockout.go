@@ -0,0 +1,102 @@
+package authservices
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type LockoutService struct {
+	store store.Storage
+}
+
+func NewLockoutService(store store.Storage) *LockoutService {
+	return &LockoutService{store: store}
+}
+
+// Guard runs attempt unless the identifier has to wait at the address or everywhere. Errors matching
+// failures count against the identifier from that address, so failures sent from elsewhere do not
+// delay its owner, and against the identifier from any address under the laxer account policy, so
+// rotating addresses does not buy unlimited guesses. A successful attempt clears the counters.
+// An empty identifier counts the address alone and, across addresses, every guess made without one.
+func (s *LockoutService) Guard(ctx context.Context, scope authdomain.AttemptScope, identifier, ipAddress string, attempt func() error, failures ...error) error {
+	identifier = normalizeIdentifier(identifier)
+	if err := s.Check(ctx, scope, identifier, ipAddress); err != nil {
+		return err
+	}
+
+	err := attempt()
+	if err == nil {
+		if err := s.store.Lockouts.Clear(ctx, scope, identifier, ipAddress); err != nil {
+			return err
+		}
+		// one right code says nothing about the guesses others make without an email
+		if identifier == "" {
+			return nil
+		}
+		return s.store.Lockouts.Clear(ctx, scope, identifier, authdomain.AnyAddress)
+	}
+	for _, failure := range failures {
+		if errors.Is(err, failure) {
+			if _, ferr := s.store.Lockouts.RegisterFailure(ctx, scope, identifier, ipAddress, s.store.Config.Auth.Lockout); ferr != nil {
+				return ferr
+			}
+			if _, ferr := s.store.Lockouts.RegisterFailure(ctx, scope, identifier, authdomain.AnyAddress, s.store.Config.Auth.AccountLockout); ferr != nil {
+				return ferr
+			}
+			break
+		}
+	}
+	return err
+}
+
+// Check returns a *authdomain.LockedError while the identifier is delayed or locked at the address
+// or from every address
+func (s *LockoutService) Check(ctx context.Context, scope authdomain.AttemptScope, identifier, ipAddress string) error {
+	identifier = normalizeIdentifier(identifier)
+	if err := s.locked(ctx, scope, identifier, ipAddress, s.store.Config.Auth.Lockout); err != nil {
+		return err
+	}
+	return s.locked(ctx, scope, identifier, authdomain.AnyAddress, s.store.Config.Auth.AccountLockout)
+}
+
+func (s *LockoutService) locked(ctx context.Context, scope authdomain.AttemptScope, identifier, ipAddress string, policy authdomain.LockoutPolicy) error {
+	attempts, err := s.store.Lockouts.Get(ctx, scope, identifier, ipAddress)
+	if err != nil {
+		if errors.Is(err, shared_errors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if attempts.LockedUntil == nil || !attempts.LockedUntil.After(time.Now()) {
+		return nil
+	}
+	_, lockout := policy.Delay(attempts.Failures)
+	return &authdomain.LockedError{Until: *attempts.LockedUntil, Lockout: lockout}
+}
+
+// Unlock lifts every delay and lockout on the account
+func (s *LockoutService) Unlock(ctx context.Context, userID, unlockedBy uuid.UUID) error {
+	user, err := s.store.Users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.store.Lockouts.Unlock(ctx, normalizeIdentifier(user.Email), unlockedBy)
+}
+
+func (s *LockoutService) ListLockouts(ctx context.Context, userID uuid.UUID) ([]authdomain.AccountLockouts, error) {
+	if _, err := s.store.Users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.store.Lockouts.ListLockouts(ctx, userID)
+}
+
+func normalizeIdentifier(identifier string) string {
+	return strings.ToLower(strings.TrimSpace(identifier))
+}
@@ -0,0 +1,78 @@
+package authservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vitalfit/api/config"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	authrepository "github.com/vitalfit/api/internal/auth/repository"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/internal/store"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+func newLockoutService(t *testing.T, perAddress, account authdomain.LockoutPolicy) *LockoutService {
+	db := testdb.New(t)
+	var cfg config.Config
+	cfg.Auth.Lockout = perAddress
+	cfg.Auth.AccountLockout = account
+	return NewLockoutService(store.Storage{
+		Users:    authrepository.NewUserRepositoryDAO(db.Gorm),
+		Lockouts: authrepository.NewLockoutStore(db.Gorm),
+		Config:   cfg,
+	})
+}
+
+func TestGuardCountsEveryAddress(t *testing.T) {
+	lax := authdomain.LockoutPolicy{FreeFailures: 100, FailureWindow: time.Hour}
+	strict := authdomain.LockoutPolicy{FreeFailures: 2, BaseDelay: time.Hour, MaxDelay: time.Hour, FailureWindow: time.Hour}
+	wrong := func() error { return authdomain.ErrInvalidOTP }
+	right := func() error { return nil }
+
+	tests := []struct {
+		name       string
+		perAddress authdomain.LockoutPolicy
+		account    authdomain.LockoutPolicy
+		identifier string
+		// addresses of the failures, then of the attempt that is checked
+		failures []string
+		checked  string
+		locked   bool
+	}{
+		{name: "same address", perAddress: strict, account: lax, identifier: "ana@example.com", failures: []string{"10.0.0.1", "10.0.0.1", "10.0.0.1"}, checked: "10.0.0.1", locked: true},
+		{name: "owner at another address", perAddress: strict, account: lax, identifier: "ana@example.com", failures: []string{"10.0.0.1", "10.0.0.1", "10.0.0.1"}, checked: "10.0.0.2"},
+		{name: "rotating addresses", perAddress: lax, account: strict, identifier: "ana@example.com", failures: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, checked: "10.0.0.4", locked: true},
+		{name: "code guesses without an email", perAddress: lax, account: strict, failures: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, checked: "10.0.0.4", locked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLockoutService(t, tt.perAddress, tt.account)
+			ctx := context.Background()
+
+			for _, address := range tt.failures {
+				err := s.Guard(ctx, authdomain.AttemptScopeActivate, tt.identifier, address, wrong, authdomain.ErrInvalidOTP)
+				if !errors.Is(err, authdomain.ErrInvalidOTP) {
+					t.Fatalf("Guard from %s = %v, want ErrInvalidOTP", address, err)
+				}
+			}
+			err := s.Guard(ctx, authdomain.AttemptScopeActivate, tt.identifier, tt.checked, right, authdomain.ErrInvalidOTP)
+			if locked := errors.Is(err, authdomain.ErrAccountLocked); locked != tt.locked {
+				t.Errorf("Guard from %s = %v, want locked %v", tt.checked, err, tt.locked)
+			}
+
+			// other identifiers are not affected
+			other := fmt.Sprintf("other+%s", tt.identifier)
+			if err := s.Guard(ctx, authdomain.AttemptScopeActivate, other, tt.checked, right); err != nil {
+				t.Errorf("Guard for another identifier = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package authservices
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"time"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/store"
+	"github.com/vitalfit/api/pkg/totp"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+type MFAService struct {
+	store store.Storage
+}
+
+func NewMFAService(store store.Storage) *MFAService {
+	return &MFAService{store: store}
+}
+
+// Required reports whether the role of the user must use two-factor authentication
+func (s *MFAService) Required(user *authdomain.Users) bool {
+	level := s.store.Config.Auth.MFA.RequiredLevel
+	return level > 0 && user.Role.Level >= level
+}
+
+// Status returns whether two-factor is enabled, mandatory, and how many recovery codes are left
+func (s *MFAService) Status(ctx context.Context, user *authdomain.Users) (*authdomain.MFAStatus, error) {
+	status := &authdomain.MFAStatus{
+		Enabled:  user.MFAEnabled,
+		Required: s.Required(user),
+	}
+	if user.MFAEnabled {
+		left, err := s.store.MFA.CountRecoveryCodes(ctx, user.UserID)
+		if err != nil {
+			return nil, err
+		}
+		status.RecoveryCodesLeft = left
+	}
+	return status, nil
+}
+
+// Enroll creates a new secret, two-factor stays disabled until ConfirmEnrollment
+func (s *MFAService) Enroll(ctx context.Context, user *authdomain.Users) (*authdomain.MFAEnrollment, error) {
+	if user.MFAEnabled {
+		return nil, authdomain.ErrMFAAlreadyEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.store.Secrets.Seal([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.MFA.SavePending(ctx, &authdomain.UserMFA{UserID: user.UserID, Secret: sealed}); err != nil {
+		return nil, err
+	}
+	return &authdomain.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.store.Config.Auth.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables two-factor once the user proves the authenticator works and returns the recovery codes
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, user *authdomain.Users, code string) ([]string, error) {
+	mfa, err := s.store.MFA.Get(ctx, user.UserID)
+	if err != nil {
+		if errors.Is(err, shared_errors.ErrNotFound) {
+			return nil, authdomain.ErrMFAEnrollmentPending
+		}
+		return nil, err
+	}
+	if mfa.EnabledAt != nil {
+		return nil, authdomain.ErrMFAAlreadyEnabled
+	}
+	step, err := s.validateTOTP(mfa, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.MFA.Enable(ctx, user.UserID, step, hashes); err != nil {
+		return nil, err
+	}
+	user.MFAEnabled = true
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, user *authdomain.Users, code string) ([]string, error) {
+	if err := s.Verify(ctx, user, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.MFA.ReplaceRecoveryCodes(ctx, user.UserID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *MFAService) Disable(ctx context.Context, user *authdomain.Users, payload authdomain.DisableMFAPayload) error {
+	if s.Required(user) {
+		return authdomain.ErrMFARequired
+	}
+	match, err := user.PasswordHash.Matches(payload.Password)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return authdomain.ErrWrongPassword
+	}
+	if err := s.Verify(ctx, user, payload.Code); err != nil {
+		return err
+	}
+	if err := s.store.MFA.Disable(ctx, user.UserID); err != nil {
+		return err
+	}
+	user.MFAEnabled = false
+	return nil
+}
+
+// Verify accepts a TOTP code or an unused recovery code, each of them only once
+func (s *MFAService) Verify(ctx context.Context, user *authdomain.Users, code string) error {
+	mfa, err := s.store.MFA.Get(ctx, user.UserID)
+	if err != nil {
+		if errors.Is(err, shared_errors.ErrNotFound) {
+			return authdomain.ErrMFANotEnabled
+		}
+		return err
+	}
+	if mfa.EnabledAt == nil {
+		return authdomain.ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, err := s.validateTOTP(mfa, code)
+		if err != nil {
+			return err
+		}
+		return s.store.MFA.UseStep(ctx, user.UserID, step)
+	}
+	return s.store.MFA.UseRecoveryCode(ctx, user.UserID, hashRecoveryCode(code))
+}
+
+func (s *MFAService) validateTOTP(mfa *authdomain.UserMFA, code string) (int64, error) {
+	secret, err := s.store.Secrets.Open(mfa.Secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := totp.Validate(string(secret), code, time.Now(), mfa.LastUsedStep)
+	if !ok {
+		return 0, authdomain.ErrInvalidMFACode
+	}
+	return step, nil
+}
+
+// newRecoveryCodes returns the codes shown to the user and the hashes that are persisted
+func newRecoveryCodes() ([]string, [][]byte, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([][]byte, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for i := range b {
+			b[i] = recoveryCodeAlphabet[int(b[i])%len(recoveryCodeAlphabet)]
+		}
+		code := string(b[:5]) + "-" + string(b[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case and separators so codes can be typed loosely
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hash[:]
+}
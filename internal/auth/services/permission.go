@@ -0,0 +1,83 @@
+package authservices
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type PermissionService struct {
+	store store.Storage
+}
+
+func NewPermissionService(store store.Storage) *PermissionService {
+	return &PermissionService{store: store}
+}
+
+func (s *PermissionService) List(ctx context.Context) ([]authdomain.Permissions, error) {
+	return s.store.Permissions.List(ctx)
+}
+
+// Resolve returns the permissions granted to the role of the user, super_admin gets all of them
+func (s *PermissionService) Resolve(ctx context.Context, user *authdomain.Users) (authdomain.PermissionSet, error) {
+	var permissions []authdomain.Permissions
+	var err error
+	if user.Role.Name == authdomain.SuperAdminRole {
+		permissions, err = s.store.Permissions.List(ctx)
+	} else {
+		permissions, err = s.store.Permissions.ListForRole(ctx, user.RoleID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return authdomain.NewPermissionSet(permissions), nil
+}
+
+func (s *PermissionService) RolePermissions(ctx context.Context, roleID uuid.UUID) ([]authdomain.Permissions, error) {
+	role, err := s.store.Roles.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == authdomain.SuperAdminRole {
+		return s.store.Permissions.List(ctx)
+	}
+	return s.store.Permissions.ListForRole(ctx, roleID)
+}
+
+// Grant maps the permission to the role. The actor must hold the permission and be at or above the role.
+func (s *PermissionService) Grant(ctx context.Context, actor *authdomain.Users, roleID uuid.UUID, name string) error {
+	if err := s.editable(ctx, actor, roleID); err != nil {
+		return err
+	}
+	held, err := s.Resolve(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if !held.Has(name) {
+		return authdomain.ErrRoleEscalation
+	}
+	return s.store.Permissions.Grant(ctx, roleID, name, actor.UserID)
+}
+
+func (s *PermissionService) Revoke(ctx context.Context, actor *authdomain.Users, roleID uuid.UUID, name string) error {
+	if err := s.editable(ctx, actor, roleID); err != nil {
+		return err
+	}
+	return s.store.Permissions.Revoke(ctx, roleID, name)
+}
+
+func (s *PermissionService) editable(ctx context.Context, actor *authdomain.Users, roleID uuid.UUID) error {
+	role, err := s.store.Roles.GetByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if role.Name == authdomain.SuperAdminRole {
+		return authdomain.ErrSuperAdminPermissions
+	}
+	if !actor.CanAssign(role) {
+		return authdomain.ErrRoleEscalation
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package authservices
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type RoleService struct {
+	store store.Storage
+}
+
+func NewRoleService(store store.Storage) *RoleService {
+	return &RoleService{store: store}
+}
+
+func (s *RoleService) List(ctx context.Context) ([]authdomain.Roles, error) {
+	return s.store.Roles.List(ctx)
+}
+
+// Create adds a role, nobody can create a role above their own level
+func (s *RoleService) Create(ctx context.Context, actor *authdomain.Users, payload authdomain.CreateRolePayload) (*authdomain.Roles, error) {
+	role := &authdomain.Roles{
+		Name:        strings.TrimSpace(payload.Name),
+		Level:       payload.Level,
+		Description: strings.TrimSpace(payload.Description),
+	}
+	if !authdomain.ValidRoleName(role.Name) {
+		return nil, authdomain.ErrInvalidRoleName
+	}
+	if !actor.CanAssign(role) {
+		return nil, authdomain.ErrRoleEscalation
+	}
+	if err := s.store.Roles.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Update changes a role and returns it as it was before and after the change.
+// Both its current and its new level must be within the level of the actor.
+func (s *RoleService) Update(ctx context.Context, actor *authdomain.Users, roleID uuid.UUID, payload authdomain.UpdateRolePayload) (*authdomain.Roles, *authdomain.Roles, error) {
+	role, err := s.store.Roles.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !actor.CanAssign(role) {
+		return nil, nil, authdomain.ErrRoleEscalation
+	}
+	previous := *role
+	if err := payload.Apply(role); err != nil {
+		return nil, nil, err
+	}
+	if !actor.CanAssign(role) {
+		return nil, nil, authdomain.ErrRoleEscalation
+	}
+	if err := s.store.Roles.Update(ctx, role); err != nil {
+		return nil, nil, err
+	}
+	return &previous, role, nil
+}
+
+// Delete removes a role nobody has, system roles cannot be deleted
+func (s *RoleService) Delete(ctx context.Context, actor *authdomain.Users, roleID uuid.UUID) (*authdomain.Roles, error) {
+	role, err := s.store.Roles.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if authdomain.SystemRoles[role.Name] {
+		return nil, authdomain.ErrSystemRole
+	}
+	if !actor.CanAssign(role) {
+		return nil, authdomain.ErrRoleEscalation
+	}
+	if err := s.store.Roles.Delete(ctx, roleID); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// ChangeUserRole moves the user to another role. The actor must be at or above both the current
+// and the new role of the user, and the last super_admin is never demoted.
+func (s *RoleService) ChangeUserRole(ctx context.Context, actor *authdomain.Users, userID uuid.UUID, roleName string) (*authdomain.UserRoleChange, error) {
+	role, err := s.store.Roles.GetByName(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanAssign(role) {
+		return nil, authdomain.ErrRoleEscalation
+	}
+	user, err := s.store.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanAssign(&user.Role) {
+		return nil, authdomain.ErrRoleEscalation
+	}
+	return s.store.Users.ChangeRole(ctx, userID, role)
+}
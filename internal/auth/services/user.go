@@ -1,12 +1,17 @@
 package authservices
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	authdomain "github.com/vitalfit/api/internal/auth/domain"
 	"github.com/vitalfit/api/internal/store"
+	"github.com/vitalfit/api/pkg/images"
 )
 
 type UserService struct {
@@ -42,6 +47,37 @@ func (h *UserService) Update(ctx context.Context, user *authdomain.Users) error
 	return nil
 }
 
+func (h *UserService) UpdateProfile(ctx context.Context, user *authdomain.Users, payload authdomain.UpdateProfilePayload) error {
+	if err := payload.Apply(user, time.Now()); err != nil {
+		return err
+	}
+	return h.store.Users.UpdateProfile(ctx, user)
+}
+
+// AvatarUploadLimit is the largest request body an avatar upload may send, the image plus room for
+// the multipart framing
+func (h *UserService) AvatarUploadLimit() int64 {
+	return h.store.Config.Avatar.MaxSize + 64<<10
+}
+
+// SetAvatar resizes the image, stores it and points the profile picture of the user to it
+func (h *UserService) SetAvatar(ctx context.Context, user *authdomain.Users, image io.Reader) error {
+	cfg := h.store.Config.Avatar
+	thumbnail, err := images.Thumbnail(image, cfg.MaxSize, cfg.Size)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("avatars/%s.jpg", user.UserID)
+	url, err := h.store.Files.Put(ctx, key, bytes.NewReader(thumbnail), "image/jpeg")
+	if err != nil {
+		return err
+	}
+	// the key is reused on every upload, the version busts client caches
+	user.ProfilePictureURL = fmt.Sprintf("%s?v=%d", url, time.Now().Unix())
+	return h.store.Users.UpdateProfile(ctx, user)
+}
+
 func (h *UserService) GetUserFromContext(c *gin.Context) *authdomain.Users {
 	user, ok := c.Value("user").(*authdomain.Users)
 	if !ok {
@@ -57,3 +93,41 @@ func (h *UserService) GetRoleByName(ctx context.Context, name string) (*authdoma
 	}
 	return role, nil
 }
+
+func (h *UserService) List(ctx context.Context, filter authdomain.UserFilter) (*authdomain.UserPage, error) {
+	users, total, err := h.store.Users.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &authdomain.UserPage{
+		Users:    users,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+// SetClientStatus blocks or unblocks a client. Blocking also revokes every session of the client.
+func (h *UserService) SetClientStatus(ctx context.Context, change *authdomain.ClientStatusHistory) error {
+	user, err := h.store.Users.GetByID(ctx, change.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Role.Name != "client" {
+		return authdomain.ErrNotClient
+	}
+	if err := h.store.Users.SetClientStatus(ctx, change); err != nil {
+		return err
+	}
+	if change.NewStatus == authdomain.ClientStatusBlocked {
+		return h.store.RefreshTokens.RevokeAllForUser(ctx, change.UserID)
+	}
+	return nil
+}
+
+func (h *UserService) StatusHistory(ctx context.Context, userID uuid.UUID) ([]authdomain.ClientStatusHistory, error) {
+	if _, err := h.store.Users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return h.store.Users.ListStatusHistory(ctx, userID)
+}
@@ -0,0 +1,124 @@
+package branchdomain
+
+import (
+	"errors"
+	"time"
+	_ "time/tzdata" // timezone database for branches hosted without one
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotStaff            = errors.New("user is not a staff member")
+	ErrInvalidTimezone     = errors.New("invalid timezone")
+	ErrInvalidOpeningHours = errors.New("invalid opening hours")
+)
+
+type Branches struct {
+	BranchID     uuid.UUID            `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"branch_id"`
+	Name         string               `gorm:"type:varchar(100);unique;not null" json:"name"`
+	AddressLine  string               `gorm:"type:varchar(255);not null" json:"address_line"`
+	City         string               `gorm:"type:varchar(100);not null" json:"city"`
+	State        string               `gorm:"type:varchar(100)" json:"state"`
+	Country      string               `gorm:"type:varchar(100);not null" json:"country"`
+	PostalCode   string               `gorm:"type:varchar(20)" json:"postal_code"`
+	Phone        string               `gorm:"type:varchar(50)" json:"phone"`
+	Timezone     string               `gorm:"type:varchar(64);not null;default:'UTC'" json:"timezone"`
+	IsActive     bool                 `gorm:"not null;default:true" json:"is_active"`
+	OpeningHours []BranchOpeningHours `gorm:"foreignKey:BranchID;references:BranchID" json:"opening_hours"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt       `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// BranchOpeningHours uses time.Weekday numbering (0 = Sunday)
+type BranchOpeningHours struct {
+	BranchID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Weekday  int16     `gorm:"type:smallint;primaryKey" json:"weekday"`
+	OpensAt  string    `gorm:"type:time;not null" json:"opens_at"`
+	ClosesAt string    `gorm:"type:time;not null" json:"closes_at"`
+}
+
+type StaffBranches struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	BranchID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"branch_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OpeningHoursPayload struct {
+	Weekday  int16  `json:"weekday" binding:"min=0,max=6"`
+	OpensAt  string `json:"opens_at" binding:"required"`
+	ClosesAt string `json:"closes_at" binding:"required"`
+}
+
+type BranchPayload struct {
+	Name         string                `json:"name" binding:"required,max=100"`
+	AddressLine  string                `json:"address_line" binding:"required,max=255"`
+	City         string                `json:"city" binding:"required,max=100"`
+	State        string                `json:"state" binding:"max=100"`
+	Country      string                `json:"country" binding:"required,max=100"`
+	PostalCode   string                `json:"postal_code" binding:"max=20"`
+	Phone        string                `json:"phone" binding:"max=50"`
+	Timezone     string                `json:"timezone" binding:"required"`
+	IsActive     *bool                 `json:"is_active"`
+	OpeningHours []OpeningHoursPayload `json:"opening_hours" binding:"dive"`
+}
+
+type AssignStaffPayload struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+}
+
+// Validate checks the timezone and the opening hours of the branch
+func (b *Branches) Validate() error {
+	if _, err := time.LoadLocation(b.Timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+
+	seen := make(map[int16]bool)
+	for _, h := range b.OpeningHours {
+		if h.Weekday < 0 || h.Weekday > 6 || seen[h.Weekday] {
+			return ErrInvalidOpeningHours
+		}
+		seen[h.Weekday] = true
+
+		opens, err := time.Parse("15:04", h.OpensAt)
+		if err != nil {
+			return ErrInvalidOpeningHours
+		}
+		closes, err := time.Parse("15:04", h.ClosesAt)
+		if err != nil {
+			return ErrInvalidOpeningHours
+		}
+		if !closes.After(opens) {
+			return ErrInvalidOpeningHours
+		}
+	}
+	return nil
+}
+
+// NewBranchFromPayload maps the request payload into a branch model
+func NewBranchFromPayload(payload BranchPayload) *Branches {
+	branch := &Branches{
+		Name:        payload.Name,
+		AddressLine: payload.AddressLine,
+		City:        payload.City,
+		State:       payload.State,
+		Country:     payload.Country,
+		PostalCode:  payload.PostalCode,
+		Phone:       payload.Phone,
+		Timezone:    payload.Timezone,
+		IsActive:    true,
+	}
+	if payload.IsActive != nil {
+		branch.IsActive = *payload.IsActive
+	}
+	for _, h := range payload.OpeningHours {
+		branch.OpeningHours = append(branch.OpeningHours, BranchOpeningHours{
+			Weekday:  h.Weekday,
+			OpensAt:  h.OpensAt,
+			ClosesAt: h.ClosesAt,
+		})
+	}
+	return branch
+}
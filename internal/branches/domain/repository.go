@@ -0,0 +1,21 @@
+package branchdomain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+)
+
+type BranchRepository interface {
+	Create(ctx context.Context, branch *Branches) error
+	GetByID(ctx context.Context, branchID uuid.UUID) (*Branches, error)
+	List(ctx context.Context, onlyActive bool) ([]Branches, error)
+	Update(ctx context.Context, branch *Branches) error
+	Delete(ctx context.Context, branchID uuid.UUID) error
+	AssignStaff(ctx context.Context, branchID uuid.UUID, userID uuid.UUID) error
+	UnassignStaff(ctx context.Context, branchID uuid.UUID, userID uuid.UUID) error
+	ListStaff(ctx context.Context, branchID uuid.UUID) ([]authdomain.Users, error)
+	IsStaffAssigned(ctx context.Context, userID uuid.UUID, branchID uuid.UUID) (bool, error)
+	GetStaffBranchIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
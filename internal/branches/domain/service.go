@@ -0,0 +1,24 @@
+package branchdomain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+)
+
+type BranchServicesInterface interface {
+	Create(ctx context.Context, branch *Branches) error
+	GetByID(ctx context.Context, branchID uuid.UUID) (*Branches, error)
+	List(ctx context.Context, onlyActive bool) ([]Branches, error)
+	Update(ctx context.Context, branch *Branches) error
+	Delete(ctx context.Context, branchID uuid.UUID) error
+	AssignStaff(ctx context.Context, actor *authdomain.Users, branchID uuid.UUID, userID uuid.UUID) error
+	UnassignStaff(ctx context.Context, actor *authdomain.Users, branchID uuid.UUID, userID uuid.UUID) error
+	ListStaff(ctx context.Context, branchID uuid.UUID) ([]authdomain.Users, error)
+	IsStaffAssigned(ctx context.Context, userID uuid.UUID, branchID uuid.UUID) (bool, error)
+	GetStaffBranchIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	CanAccessBranch(ctx context.Context, user *authdomain.Users, branchID uuid.UUID) (bool, error)
+	BranchScope(ctx context.Context, user *authdomain.Users) ([]uuid.UUID, bool, error)
+	CanAccessUser(ctx context.Context, user *authdomain.Users, userID uuid.UUID) (bool, error)
+}
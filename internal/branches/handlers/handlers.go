@@ -0,0 +1,293 @@
+package branchhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	appservices "github.com/vitalfit/api/internal/app/services"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+type BranchHandlersInterface interface {
+	BranchRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+}
+
+type BranchHandlers struct {
+	services appservices.Services
+}
+
+func NewBranchHandlers(services appservices.Services) *BranchHandlers {
+	return &BranchHandlers{services: services}
+}
+
+// @Summary		List branches
+// @Description	Returns the active gym locations with their address, timezone and opening hours.
+// @Tags			Branches
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"branches"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/branches [get]
+func (h *BranchHandlers) listBranchesHandler(c *gin.Context) {
+	branches, err := h.services.BranchServices.List(c.Request.Context(), true)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"branches": branches,
+	})
+}
+
+// @Summary		Get branch
+// @Description	Returns a single gym location.
+// @Tags			Branches
+// @Produce		json
+// @Param			branch_id	path		string					true	"Branch ID"
+// @Success		200			{object}	map[string]interface{}	"branch"
+// @Failure		400			{object}	map[string]interface{}	"invalid branch id"
+// @Failure		404			{object}	map[string]interface{}	"branch not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id} [get]
+func (h *BranchHandlers) getBranchHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	branch, err := h.services.BranchServices.GetByID(c.Request.Context(), branchID)
+	if err != nil {
+		switch err {
+		case shared_errors.ErrNotFound:
+			h.services.LogErrors.NotFoundResponse(c)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, branch)
+}
+
+// @Summary		Create branch
+// @Description	Creates a new gym location. Opening hours use weekday 0 (Sunday) to 6 and HH:MM times.
+// @Tags			Branches
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			branch	body		branchdomain.BranchPayload	true	"Branch data"
+// @Success		201		{object}	map[string]interface{}		"branch created"
+// @Failure		400		{object}	map[string]interface{}		"bad request"
+// @Failure		403		{object}	map[string]interface{}		"forbidden"
+// @Failure		409		{object}	map[string]interface{}		"branch name already exists"
+// @Failure		500		{object}	map[string]interface{}		"internal server error"
+// @Router			/branches [post]
+func (h *BranchHandlers) createBranchHandler(c *gin.Context) {
+	var payload branchdomain.BranchPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	branch := branchdomain.NewBranchFromPayload(payload)
+	if err := h.services.BranchServices.Create(c.Request.Context(), branch); err != nil {
+		h.branchErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, branch)
+}
+
+// @Summary		Update branch
+// @Description	Replaces the data and opening hours of a gym location. Branch admins can only update their own branches.
+// @Tags			Branches
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			branch_id	path		string						true	"Branch ID"
+// @Param			branch		body		branchdomain.BranchPayload	true	"Branch data"
+// @Success		200			{object}	map[string]interface{}		"branch updated"
+// @Failure		400			{object}	map[string]interface{}		"bad request"
+// @Failure		403			{object}	map[string]interface{}		"forbidden"
+// @Failure		404			{object}	map[string]interface{}		"branch not found"
+// @Failure		409			{object}	map[string]interface{}		"branch name already exists"
+// @Failure		500			{object}	map[string]interface{}		"internal server error"
+// @Router			/branches/{branch_id} [put]
+func (h *BranchHandlers) updateBranchHandler(c *gin.Context) {
+	var payload branchdomain.BranchPayload
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	branch := branchdomain.NewBranchFromPayload(payload)
+	branch.BranchID = branchID
+	if err := h.services.BranchServices.Update(c.Request.Context(), branch); err != nil {
+		h.branchErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, branch)
+}
+
+// @Summary		Delete branch
+// @Description	Soft deletes a gym location.
+// @Tags			Branches
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path	string	true	"Branch ID"
+// @Success		204			"Branch deleted. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"invalid branch id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"branch not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id} [delete]
+func (h *BranchHandlers) deleteBranchHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.BranchServices.Delete(c.Request.Context(), branchID); err != nil {
+		switch err {
+		case shared_errors.ErrNotFound:
+			h.services.LogErrors.NotFoundResponse(c)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		List branch staff
+// @Description	Returns the staff members assigned to the branch.
+// @Tags			Branches
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path		string					true	"Branch ID"
+// @Success		200			{object}	map[string]interface{}	"staff"
+// @Failure		400			{object}	map[string]interface{}	"invalid branch id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/staff [get]
+func (h *BranchHandlers) listStaffHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	staff, err := h.services.BranchServices.ListStaff(c.Request.Context(), branchID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"staff": staff,
+	})
+}
+
+// @Summary		Assign staff to branch
+// @Description	Assigns a staff member to the branch. Clients cannot be assigned, and only staff whose role the requester could assign can be moved.
+// @Tags			Branches
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			branch_id	path	string							true	"Branch ID"
+// @Param			payload		body	branchdomain.AssignStaffPayload	true	"Staff member"
+// @Success		204			"Staff assigned. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"bad request or user is not staff"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"branch or user not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/staff [post]
+func (h *BranchHandlers) assignStaffHandler(c *gin.Context) {
+	var payload branchdomain.AssignStaffPayload
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.BranchServices.AssignStaff(c.Request.Context(), actor, branchID, userID); err != nil {
+		h.branchErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Remove staff from branch
+// @Description	Removes the staff member assignment from the branch. Only staff whose role the requester could assign can be removed.
+// @Tags			Branches
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path	string	true	"Branch ID"
+// @Param			user_id		path	string	true	"User ID"
+// @Success		204			"Staff removed. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"invalid id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"assignment not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/staff/{user_id} [delete]
+func (h *BranchHandlers) unassignStaffHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	actor := h.services.UserServices.GetUserFromContext(c)
+	if err := h.services.BranchServices.UnassignStaff(c.Request.Context(), actor, branchID, userID); err != nil {
+		switch err {
+		case shared_errors.ErrNotFound:
+			h.services.LogErrors.NotFoundResponse(c)
+		case authdomain.ErrRoleEscalation:
+			h.services.LogErrors.ForbiddenResponse(c)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *BranchHandlers) branchErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, shared_errors.ErrConflict):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, authdomain.ErrRoleEscalation):
+		h.services.LogErrors.ForbiddenResponse(c)
+	case errors.Is(err, branchdomain.ErrInvalidTimezone),
+		errors.Is(err, branchdomain.ErrInvalidOpeningHours),
+		errors.Is(err, branchdomain.ErrNotStaff):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
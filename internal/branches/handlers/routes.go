@@ -0,0 +1,29 @@
+package branchhandlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+func (r *BranchHandlers) BranchRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+
+	branchGroup := rg.Group("/branches")
+	{ //public routes
+		branchGroup.GET("", r.listBranchesHandler)
+		branchGroup.GET("/:branch_id", r.getBranchHandler)
+
+		superAdminGroup := branchGroup.Group("").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("super_admin"))
+		{
+			superAdminGroup.POST("", r.createBranchHandler)
+			superAdminGroup.DELETE("/:branch_id", r.deleteBranchHandler)
+		}
+
+		branchAdminGroup := branchGroup.Group("/:branch_id").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("branch_admin"), m.CheckBranchAccess("branch_id"))
+		{
+			branchAdminGroup.PUT("", r.updateBranchHandler)
+			branchAdminGroup.GET("/staff", r.listStaffHandler)
+			branchAdminGroup.POST("/staff", r.assignStaffHandler)
+			branchAdminGroup.DELETE("/staff/:user_id", r.unassignStaffHandler)
+		}
+	}
+}
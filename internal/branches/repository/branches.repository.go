@@ -0,0 +1,193 @@
+package branchrepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BranchStore struct {
+	db *gorm.DB
+}
+
+func NewBranchStore(db *gorm.DB) *BranchStore {
+	return &BranchStore{db: db}
+}
+
+func (s *BranchStore) Create(ctx context.Context, branch *branchdomain.Branches) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(branch).Error; err != nil {
+		return mapBranchError(err)
+	}
+	return nil
+}
+
+func (s *BranchStore) GetByID(ctx context.Context, branchID uuid.UUID) (*branchdomain.Branches, error) {
+	var branch branchdomain.Branches
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Preload("OpeningHours", func(tx *gorm.DB) *gorm.DB { return tx.Order("weekday") }).
+		Where("branch_id = ?", branchID).
+		First(&branch).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &branch, nil
+}
+
+func (s *BranchStore) List(ctx context.Context, onlyActive bool) ([]branchdomain.Branches, error) {
+	var branches []branchdomain.Branches
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).
+		Preload("OpeningHours", func(tx *gorm.DB) *gorm.DB { return tx.Order("weekday") }).
+		Order("name")
+	if onlyActive {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Find(&branches).Error; err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+// Update saves the branch and replaces its opening hours
+func (s *BranchStore) Update(ctx context.Context, branch *branchdomain.Branches) error {
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+		defer cancel()
+
+		result := tx.WithContext(ctx).Model(branch).
+			Select("name", "address_line", "city", "state", "country", "postal_code", "phone", "timezone", "is_active").
+			Updates(branch)
+		if result.Error != nil {
+			return mapBranchError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return shared_errors.ErrNotFound
+		}
+
+		if err := tx.WithContext(ctx).Where("branch_id = ?", branch.BranchID).Delete(&branchdomain.BranchOpeningHours{}).Error; err != nil {
+			return err //rollback
+		}
+		for i := range branch.OpeningHours {
+			branch.OpeningHours[i].BranchID = branch.BranchID
+		}
+		if len(branch.OpeningHours) > 0 {
+			if err := tx.WithContext(ctx).Create(&branch.OpeningHours).Error; err != nil {
+				return err //rollback
+			}
+		}
+		return nil //commit
+	})
+}
+
+func (s *BranchStore) Delete(ctx context.Context, branchID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&branchdomain.Branches{}, branchID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *BranchStore) AssignStaff(ctx context.Context, branchID uuid.UUID, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&branchdomain.StaffBranches{UserID: userID, BranchID: branchID}).Error
+}
+
+func (s *BranchStore) UnassignStaff(ctx context.Context, branchID uuid.UUID, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Where("branch_id = ? AND user_id = ?", branchID, userID).
+		Delete(&branchdomain.StaffBranches{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *BranchStore) ListStaff(ctx context.Context, branchID uuid.UUID) ([]authdomain.Users, error) {
+	var users []authdomain.Users
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Preload("Role").
+		Joins("JOIN staff_branches sb ON sb.user_id = users.user_id").
+		Where("sb.branch_id = ?", branchID).
+		Order("users.last_name, users.first_name").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (s *BranchStore) IsStaffAssigned(ctx context.Context, userID uuid.UUID, branchID uuid.UUID) (bool, error) {
+	var count int64
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Model(&branchdomain.StaffBranches{}).
+		Where("user_id = ? AND branch_id = ?", userID, branchID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *BranchStore) GetStaffBranchIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Model(&branchdomain.StaffBranches{}).
+		Where("user_id = ?", userID).
+		Pluck("branch_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func mapBranchError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "branches_name_key" {
+		return shared_errors.ErrConflict
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package branchservices
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type BranchService struct {
+	store store.Storage
+}
+
+func NewBranchService(store store.Storage) *BranchService {
+	return &BranchService{
+		store: store,
+	}
+}
+
+func (s *BranchService) Create(ctx context.Context, branch *branchdomain.Branches) error {
+	if err := branch.Validate(); err != nil {
+		return err
+	}
+	return s.store.Branches.Create(ctx, branch)
+}
+
+func (s *BranchService) GetByID(ctx context.Context, branchID uuid.UUID) (*branchdomain.Branches, error) {
+	return s.store.Branches.GetByID(ctx, branchID)
+}
+
+func (s *BranchService) List(ctx context.Context, onlyActive bool) ([]branchdomain.Branches, error) {
+	return s.store.Branches.List(ctx, onlyActive)
+}
+
+func (s *BranchService) Update(ctx context.Context, branch *branchdomain.Branches) error {
+	if err := branch.Validate(); err != nil {
+		return err
+	}
+	return s.store.Branches.Update(ctx, branch)
+}
+
+func (s *BranchService) Delete(ctx context.Context, branchID uuid.UUID) error {
+	return s.store.Branches.Delete(ctx, branchID)
+}
+
+// AssignStaff links a staff member to the branch, clients cannot be assigned and the actor can
+// only move staff whose role it could assign
+func (s *BranchService) AssignStaff(ctx context.Context, actor *authdomain.Users, branchID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.store.Branches.GetByID(ctx, branchID); err != nil {
+		return err
+	}
+	user, err := s.staffManagedBy(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role.Name == "client" {
+		return branchdomain.ErrNotStaff
+	}
+	return s.store.Branches.AssignStaff(ctx, branchID, userID)
+}
+
+func (s *BranchService) UnassignStaff(ctx context.Context, actor *authdomain.Users, branchID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.staffManagedBy(ctx, actor, userID); err != nil {
+		return err
+	}
+	return s.store.Branches.UnassignStaff(ctx, branchID, userID)
+}
+
+// staffManagedBy returns the user when its role is one the actor can assign, the rule role changes follow
+func (s *BranchService) staffManagedBy(ctx context.Context, actor *authdomain.Users, userID uuid.UUID) (*authdomain.Users, error) {
+	user, err := s.store.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanAssign(&user.Role) {
+		return nil, authdomain.ErrRoleEscalation
+	}
+	return user, nil
+}
+
+func (s *BranchService) ListStaff(ctx context.Context, branchID uuid.UUID) ([]authdomain.Users, error) {
+	return s.store.Branches.ListStaff(ctx, branchID)
+}
+
+func (s *BranchService) IsStaffAssigned(ctx context.Context, userID uuid.UUID, branchID uuid.UUID) (bool, error) {
+	return s.store.Branches.IsStaffAssigned(ctx, userID, branchID)
+}
+
+func (s *BranchService) GetStaffBranchIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return s.store.Branches.GetStaffBranchIDs(ctx, userID)
+}
+
+// CanAccessBranch reports whether the user may act on the branch resources.
+// Roles at or above super_admin are not restricted to their assigned branches.
+func (s *BranchService) CanAccessBranch(ctx context.Context, user *authdomain.Users, branchID uuid.UUID) (bool, error) {
+	unrestricted, err := s.isUnrestricted(ctx, user)
+	if err != nil || unrestricted {
+		return unrestricted, err
+	}
+	return s.store.Branches.IsStaffAssigned(ctx, user.UserID, branchID)
+}
+
+// BranchScope returns the branches the user is restricted to, scoped is false for roles at or
+// above super_admin which see every branch
+func (s *BranchService) BranchScope(ctx context.Context, user *authdomain.Users) ([]uuid.UUID, bool, error) {
+	unrestricted, err := s.isUnrestricted(ctx, user)
+	if err != nil || unrestricted {
+		return nil, false, err
+	}
+	branchIDs, err := s.store.Branches.GetStaffBranchIDs(ctx, user.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+	return branchIDs, true, nil
+}
+
+// CanAccessUser reports whether the user may see and act on the other user, which has to be
+// assigned to or have checked in at one of the branches of the user
+func (s *BranchService) CanAccessUser(ctx context.Context, user *authdomain.Users, userID uuid.UUID) (bool, error) {
+	branchIDs, scoped, err := s.BranchScope(ctx, user)
+	if err != nil || !scoped {
+		return err == nil, err
+	}
+	return s.store.Users.InBranches(ctx, userID, branchIDs)
+}
+
+func (s *BranchService) isUnrestricted(ctx context.Context, user *authdomain.Users) (bool, error) {
+	superAdmin, err := s.store.Roles.GetByName(ctx, "super_admin")
+	if err != nil {
+		return false, err
+	}
+	return user.Role.Level >= superAdmin.Level, nil
+}
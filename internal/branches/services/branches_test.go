@@ -0,0 +1,61 @@
+package branchservices_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	authrepository "github.com/vitalfit/api/internal/auth/repository"
+	branchrepository "github.com/vitalfit/api/internal/branches/repository"
+	branchservices "github.com/vitalfit/api/internal/branches/services"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/internal/migrate/testdb/factory"
+	"github.com/vitalfit/api/internal/store"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+func TestStaffAssignmentFollowsRoleLevels(t *testing.T) {
+	tests := []struct {
+		actor  string
+		target string
+		want   error
+	}{
+		{actor: "branch_admin", target: "recepcionist"},
+		{actor: "branch_admin", target: "instructor"},
+		{actor: "branch_admin", target: "super_admin", want: authdomain.ErrRoleEscalation},
+		{actor: "super_admin", target: "branch_admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.actor+" moves "+tt.target, func(t *testing.T) {
+			f := factory.New(t, testdb.New(t))
+			users := authrepository.NewUserRepositoryDAO(f.DB)
+			s := branchservices.NewBranchService(store.Storage{
+				Users:    users,
+				Branches: branchrepository.NewBranchStore(f.DB),
+			})
+			ctx := context.Background()
+
+			actor, err := users.GetByID(ctx, f.Staff(tt.actor).UserID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			target := f.Staff(tt.target)
+			branch := f.Branch()
+
+			if err := s.AssignStaff(ctx, actor, branch.BranchID, target.UserID); !errors.Is(err, tt.want) {
+				t.Fatalf("AssignStaff = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				f.AssignStaff(target, branch)
+			}
+			if err := s.UnassignStaff(ctx, actor, branch.BranchID, target.UserID); !errors.Is(err, tt.want) {
+				t.Errorf("UnassignStaff = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package checkindomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+)
+
+var (
+	ErrInvalidQRCode = errors.New("invalid or expired qr code")
+	ErrNotClient     = errors.New("user is not a client")
+)
+
+type Checkins struct {
+	CheckinID      uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"checkin_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	BranchID       uuid.UUID  `gorm:"type:uuid;not null" json:"branch_id"`
+	SubscriptionID *uuid.UUID `gorm:"type:uuid" json:"subscription_id"`
+	CheckedInBy    *uuid.UUID `gorm:"type:uuid" json:"checked_in_by"`
+	CheckedInAt    time.Time  `gorm:"not null;default:now()" json:"checked_in_at"`
+}
+
+type CheckinPayload struct {
+	Payload  string `json:"payload" binding:"required"`
+	BranchID string `json:"branch_id" binding:"required,uuid"`
+}
+
+type QRCode struct {
+	Payload   string    `json:"payload"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MemberCard is the client summary shown on the front desk screen after a check-in
+type MemberCard struct {
+	CheckinID         uuid.UUID                             `json:"checkin_id"`
+	CheckedInAt       time.Time                             `json:"checked_in_at"`
+	UserID            uuid.UUID                             `json:"user_id"`
+	FirstName         string                                `json:"first_name"`
+	LastName          string                                `json:"last_name"`
+	IdentityDocument  string                                `json:"identity_document"`
+	ProfilePictureURL string                                `json:"profile_picture_url"`
+	Status            authdomain.ClientStatusEnum           `json:"status"`
+	Category          authdomain.ClientCategoryEnum         `json:"category"`
+	Subscription      *membershipdomain.ClientSubscriptions `json:"subscription"`
+}
+
+func NewMemberCard(user *authdomain.Users, checkin *Checkins, subscription *membershipdomain.ClientSubscriptions) *MemberCard {
+	return &MemberCard{
+		CheckinID:         checkin.CheckinID,
+		CheckedInAt:       checkin.CheckedInAt,
+		UserID:            user.UserID,
+		FirstName:         user.FirstName,
+		LastName:          user.LastName,
+		IdentityDocument:  user.IdentityDocument,
+		ProfilePictureURL: user.ProfilePictureURL,
+		Status:            user.ClientProfile.Status,
+		Category:          user.ClientProfile.Category,
+		Subscription:      subscription,
+	}
+}
@@ -0,0 +1,7 @@
+package checkindomain
+
+import "context"
+
+type CheckinRepository interface {
+	Create(ctx context.Context, checkin *Checkins, payload string) error
+}
@@ -0,0 +1,13 @@
+package checkindomain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+)
+
+type CheckinServicesInterface interface {
+	IssueQRCode(ctx context.Context, user *authdomain.Users) (*QRCode, error)
+	CheckIn(ctx context.Context, payload string, branchID uuid.UUID, staff *authdomain.Users) (*MemberCard, error)
+}
@@ -0,0 +1,103 @@
+package checkinhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	appservices "github.com/vitalfit/api/internal/app/services"
+	checkindomain "github.com/vitalfit/api/internal/checkins/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+type CheckinHandlersInterface interface {
+	CheckinRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+}
+
+type CheckinHandlers struct {
+	services appservices.Services
+}
+
+func NewCheckinHandlers(services appservices.Services) *CheckinHandlers {
+	return &CheckinHandlers{services: services}
+}
+
+// @Summary		Get my QR code
+// @Description	Issues a short lived signed QR payload for the authenticated client. Issuing a new payload invalidates the previous one, and a payload is good for a single check-in.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	checkindomain.QRCode	"qr code"
+// @Failure		400	{object}	map[string]interface{}	"user is not a client"
+// @Failure		401	{object}	map[string]interface{}	"unauthorized"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/user/qr [get]
+func (h *CheckinHandlers) myQRCodeHandler(c *gin.Context) {
+	user := h.services.UserServices.GetUserFromContext(c)
+
+	code, err := h.services.CheckinServices.IssueQRCode(c.Request.Context(), user)
+	if err != nil {
+		h.checkinErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, code)
+}
+
+// @Summary		Check in client
+// @Description	Validates the client QR payload and records the visit at the branch. A payload is accepted once. Returns the member card for the front desk screen, without the subscription when its visit limit was reached.
+// @Tags			Checkins
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		checkindomain.CheckinPayload	true	"QR payload and branch"
+// @Success		201		{object}	map[string]interface{}			"member card"
+// @Failure		400		{object}	map[string]interface{}			"bad request or invalid qr code"
+// @Failure		403		{object}	map[string]interface{}			"forbidden, or client is blocked (code CLIENT_BLOCKED)"
+// @Failure		404		{object}	map[string]interface{}			"branch or client not found"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/checkins [post]
+func (h *CheckinHandlers) checkInHandler(c *gin.Context) {
+	var payload checkindomain.CheckinPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	branchID, err := uuid.Parse(payload.BranchID)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	staff := h.services.UserServices.GetUserFromContext(c)
+	allowed, err := h.services.BranchServices.CanAccessBranch(c.Request.Context(), staff, branchID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	if !allowed {
+		h.services.LogErrors.ForbiddenResponse(c)
+		return
+	}
+
+	card, err := h.services.CheckinServices.CheckIn(c.Request.Context(), payload.Payload, branchID, staff)
+	if err != nil {
+		h.checkinErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, card)
+}
+
+func (h *CheckinHandlers) checkinErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, shared_errors.ErrClientBlocked):
+		h.services.LogErrors.ClientBlockedResponse(c)
+	case errors.Is(err, checkindomain.ErrInvalidQRCode), errors.Is(err, checkindomain.ErrNotClient):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
@@ -0,0 +1,19 @@
+package checkinhandlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+func (r *CheckinHandlers) CheckinRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+
+	checkinGroup := rg.Group("/checkins").Use(m.AuthJwtTokenMiddleware(), m.RequirePermission("checkins:write"))
+	{ //front desk routes
+		checkinGroup.POST("", r.checkInHandler)
+	}
+
+	userGroup := rg.Group("/user").Use(m.AuthJwtTokenMiddleware())
+	{ //private routes
+		userGroup.GET("/qr", r.myQRCodeHandler)
+	}
+}
@@ -0,0 +1,58 @@
+package checkinrepository
+
+import (
+	"context"
+
+	checkindomain "github.com/vitalfit/api/internal/checkins/domain"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+)
+
+type CheckinStore struct {
+	db *gorm.DB
+}
+
+func NewCheckinStore(db *gorm.DB) *CheckinStore {
+	return &CheckinStore{db: db}
+}
+
+// Create consumes the qr payload, records the visit and consumes one visit of the subscription
+// used for it. A payload that was already used is rejected, and the visit is recorded without the
+// subscription when a concurrent check-in took its last visit.
+func (s *CheckinStore) Create(ctx context.Context, checkin *checkindomain.Checkins, payload string) error {
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+		defer cancel()
+
+		result := tx.WithContext(ctx).
+			Table("client_profiles").
+			Where("user_id = ? AND qr_code = ?", checkin.UserID, payload).
+			Update("qr_code", nil)
+		if result.Error != nil {
+			return result.Error //rollback
+		}
+		if result.RowsAffected == 0 {
+			return checkindomain.ErrInvalidQRCode //rollback
+		}
+
+		if checkin.SubscriptionID != nil {
+			// the limit is checked by the same statement that counts the visit
+			result := tx.WithContext(ctx).
+				Table("client_subscriptions").
+				Where("subscription_id = ?", *checkin.SubscriptionID).
+				Where("visits_used < COALESCE((SELECT visit_limit FROM membership_plans p WHERE p.plan_id = client_subscriptions.plan_id), visits_used + 1)").
+				Update("visits_used", gorm.Expr("visits_used + 1"))
+			if result.Error != nil {
+				return result.Error //rollback
+			}
+			if result.RowsAffected == 0 {
+				checkin.SubscriptionID = nil
+			}
+		}
+
+		if err := tx.WithContext(ctx).Create(checkin).Error; err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
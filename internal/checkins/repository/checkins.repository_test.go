@@ -0,0 +1,64 @@
+package checkinrepository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	checkindomain "github.com/vitalfit/api/internal/checkins/domain"
+	checkinrepository "github.com/vitalfit/api/internal/checkins/repository"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/internal/migrate/testdb/factory"
+)
+
+func TestCreateConsumesThePayload(t *testing.T) {
+	f := factory.New(t, testdb.New(t))
+	store := checkinrepository.NewCheckinStore(f.DB)
+	ctx := context.Background()
+
+	const payload = "VF2.payload.signature"
+	client := f.User(func(u *authdomain.Users) { u.ClientProfile.QRCode = payload })
+	branch := f.Branch()
+
+	if err := store.Create(ctx, &checkindomain.Checkins{UserID: client.UserID, BranchID: branch.BranchID}, payload); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	err := store.Create(ctx, &checkindomain.Checkins{UserID: client.UserID, BranchID: branch.BranchID}, payload)
+	if !errors.Is(err, checkindomain.ErrInvalidQRCode) {
+		t.Errorf("Create with a used payload = %v, want ErrInvalidQRCode", err)
+	}
+	if got := f.Count(&checkindomain.Checkins{}, "user_id = ?", client.UserID); got != 1 {
+		t.Errorf("checkins = %d, want 1", got)
+	}
+}
+
+func TestCreateStopsAtTheVisitLimit(t *testing.T) {
+	f := factory.New(t, testdb.New(t))
+	store := checkinrepository.NewCheckinStore(f.DB)
+	ctx := context.Background()
+
+	limit := 2
+	client := f.User()
+	branch := f.Branch()
+	subscription := f.Subscription(client, &limit)
+
+	for i, wantSubscription := range []bool{true, true, false} {
+		payload := "VF2.visit." + string(rune('a'+i))
+		if err := f.DB.Model(&authdomain.ClientProfiles{}).Where("user_id = ?", client.UserID).Update("qr_code", payload).Error; err != nil {
+			t.Fatalf("set qr code: %v", err)
+		}
+		// every check-in read the subscription before the limit was reached
+		checkin := &checkindomain.Checkins{UserID: client.UserID, BranchID: branch.BranchID, SubscriptionID: &subscription.SubscriptionID}
+		if err := store.Create(ctx, checkin, payload); err != nil {
+			t.Fatalf("Create visit %d: %v", i+1, err)
+		}
+		if got := checkin.SubscriptionID != nil; got != wantSubscription {
+			t.Errorf("visit %d counted on the subscription = %v, want %v", i+1, got, wantSubscription)
+		}
+	}
+	if got := f.Count(&membershipdomain.ClientSubscriptions{}, "subscription_id = ? AND visits_used = ?", subscription.SubscriptionID, limit); got != 1 {
+		t.Errorf("subscription with %d visits used = %d, want 1", limit, got)
+	}
+}
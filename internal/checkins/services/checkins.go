@@ -0,0 +1,120 @@
+package checkinservices
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	checkindomain "github.com/vitalfit/api/internal/checkins/domain"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/store"
+	"github.com/vitalfit/api/pkg/qrcode"
+)
+
+type CheckinService struct {
+	store store.Storage
+}
+
+func NewCheckinService(store store.Storage) *CheckinService {
+	return &CheckinService{
+		store: store,
+	}
+}
+
+// IssueQRCode signs a new payload for the client. Issuing a payload invalidates the previous one,
+// and each payload is accepted for a single check-in.
+func (s *CheckinService) IssueQRCode(ctx context.Context, user *authdomain.Users) (*checkindomain.QRCode, error) {
+	if user.Role.Name != "client" {
+		return nil, checkindomain.ErrNotClient
+	}
+
+	payload, expiresAt := s.store.QRCodes.Sign(user.UserID, time.Now())
+	if err := s.store.Users.SetQRCode(ctx, user.UserID, payload); err != nil {
+		return nil, err
+	}
+	return &checkindomain.QRCode{
+		Payload:   payload,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// CheckIn validates the client payload and records the visit at the branch
+func (s *CheckinService) CheckIn(ctx context.Context, payload string, branchID uuid.UUID, staff *authdomain.Users) (*checkindomain.MemberCard, error) {
+	now := time.Now()
+
+	userID, err := s.store.QRCodes.Verify(payload, now)
+	if err != nil {
+		if errors.Is(err, qrcode.ErrInvalidPayload) || errors.Is(err, qrcode.ErrExpiredPayload) {
+			return nil, checkindomain.ErrInvalidQRCode
+		}
+		return nil, err
+	}
+
+	if _, err := s.store.Branches.GetByID(ctx, branchID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.store.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role.Name != "client" {
+		return nil, checkindomain.ErrNotClient
+	}
+	// only the last payload issued to the client is accepted, the check-in consumes it
+	if user.ClientProfile.QRCode != payload {
+		return nil, checkindomain.ErrInvalidQRCode
+	}
+	if user.IsBlocked() {
+		return nil, shared_errors.ErrClientBlocked
+	}
+
+	subscription, err := s.usableSubscription(ctx, userID, branchID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	checkin := &checkindomain.Checkins{
+		UserID:      userID,
+		BranchID:    branchID,
+		CheckedInBy: &staff.UserID,
+		CheckedInAt: now,
+	}
+	if subscription != nil {
+		checkin.SubscriptionID = &subscription.SubscriptionID
+	}
+	if err := s.store.Checkins.Create(ctx, checkin, payload); err != nil {
+		return nil, err
+	}
+	if checkin.SubscriptionID == nil {
+		// a concurrent check-in took the last visit
+		subscription = nil
+	} else if subscription != nil {
+		subscription.VisitsUsed++
+	}
+
+	return checkindomain.NewMemberCard(user, checkin, subscription), nil
+}
+
+// usableSubscription returns the client subscription that grants access to the branch, if any
+func (s *CheckinService) usableSubscription(ctx context.Context, userID uuid.UUID, branchID uuid.UUID, now time.Time) (*membershipdomain.ClientSubscriptions, error) {
+	subscription, err := s.store.Subscriptions.GetCurrentByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, shared_errors.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if subscription.RefreshStatus(now) {
+		if err := s.store.Subscriptions.Update(ctx, subscription); err != nil {
+			return nil, err
+		}
+	}
+	if !subscription.IsUsable(now) || !subscription.Plan.AvailableAt(branchID) {
+		return nil, nil
+	}
+	return subscription, nil
+}
@@ -0,0 +1,293 @@
+package classdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SessionStatusEnum string
+
+const (
+	SessionScheduled SessionStatusEnum = "scheduled"
+	SessionCancelled SessionStatusEnum = "cancelled"
+)
+
+type BookingStatusEnum string
+
+const (
+	BookingBooked     BookingStatusEnum = "booked"
+	BookingWaitlisted BookingStatusEnum = "waitlisted"
+	BookingCancelled  BookingStatusEnum = "cancelled"
+)
+
+var (
+	ErrInvalidSchedule      = errors.New("invalid class schedule")
+	ErrCapacityExceeded     = errors.New("class capacity exceeds the room capacity")
+	ErrSessionUnavailable   = errors.New("class session is not open for booking")
+	ErrCancellationClosed   = errors.New("the cancellation period for this class is over")
+	ErrBookingNotOpen       = errors.New("booking is already cancelled")
+	ErrNoActiveSubscription = errors.New("client has no subscription valid for this class")
+	ErrNotClient            = errors.New("user is not a client")
+)
+
+type ClassTypes struct {
+	ClassTypeID     uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"class_type_id"`
+	Name            string         `gorm:"type:varchar(100);unique;not null" json:"name"`
+	Description     string         `gorm:"type:text" json:"description"`
+	DurationMinutes int            `gorm:"type:integer;not null" json:"duration_minutes"`
+	IsActive        bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+type Rooms struct {
+	RoomID    uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"room_id"`
+	BranchID  uuid.UUID      `gorm:"type:uuid;not null" json:"branch_id"`
+	Name      string         `gorm:"type:varchar(100);not null" json:"name"`
+	Capacity  int            `gorm:"type:integer;not null" json:"capacity"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// ClassSchedules is a weekly recurring slot. StartTime is in the branch timezone
+// and Weekday uses time.Weekday numbering (0 = Sunday).
+type ClassSchedules struct {
+	ScheduleID      uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"schedule_id"`
+	ClassTypeID     uuid.UUID  `gorm:"type:uuid;not null" json:"class_type_id"`
+	ClassType       ClassTypes `gorm:"foreignKey:ClassTypeID;references:ClassTypeID" json:"class_type"`
+	BranchID        uuid.UUID  `gorm:"type:uuid;not null" json:"branch_id"`
+	RoomID          *uuid.UUID `gorm:"type:uuid" json:"room_id"`
+	InstructorID    *uuid.UUID `gorm:"type:uuid" json:"instructor_id"`
+	Weekday         int16      `gorm:"type:smallint;not null" json:"weekday"`
+	StartTime       string     `gorm:"type:time;not null" json:"start_time"`
+	DurationMinutes int        `gorm:"type:integer;not null" json:"duration_minutes"`
+	Capacity        int        `gorm:"type:integer;not null" json:"capacity"`
+	ValidFrom       time.Time  `gorm:"type:date;not null" json:"valid_from"`
+	ValidUntil      *time.Time `gorm:"type:date" json:"valid_until"`
+	IsActive        bool       `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type ClassSessions struct {
+	SessionID    uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"session_id"`
+	ScheduleID   *uuid.UUID        `gorm:"type:uuid" json:"schedule_id"`
+	ClassTypeID  uuid.UUID         `gorm:"type:uuid;not null" json:"class_type_id"`
+	ClassType    ClassTypes        `gorm:"foreignKey:ClassTypeID;references:ClassTypeID" json:"class_type"`
+	BranchID     uuid.UUID         `gorm:"type:uuid;not null" json:"branch_id"`
+	RoomID       *uuid.UUID        `gorm:"type:uuid" json:"room_id"`
+	InstructorID *uuid.UUID        `gorm:"type:uuid" json:"instructor_id"`
+	StartsAt     time.Time         `gorm:"not null" json:"starts_at"`
+	EndsAt       time.Time         `gorm:"not null" json:"ends_at"`
+	Capacity     int               `gorm:"type:integer;not null" json:"capacity"`
+	Status       SessionStatusEnum `gorm:"type:class_session_status;not null;default:'scheduled'" json:"status"`
+	BookedCount  int               `gorm:"->;-:migration" json:"booked_count"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type ClassBookings struct {
+	BookingID   uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"booking_id"`
+	SessionID   uuid.UUID         `gorm:"type:uuid;not null" json:"session_id"`
+	Session     *ClassSessions    `gorm:"foreignKey:SessionID;references:SessionID" json:"session,omitempty"`
+	UserID      uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
+	Status      BookingStatusEnum `gorm:"type:booking_status;not null" json:"status"`
+	PromotedAt  *time.Time        `json:"promoted_at,omitempty"`
+	CancelledAt *time.Time        `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Validate checks the weekday, the start time and the validity period of the schedule
+func (s *ClassSchedules) Validate() error {
+	if s.Weekday < 0 || s.Weekday > 6 || s.DurationMinutes <= 0 || s.Capacity <= 0 {
+		return ErrInvalidSchedule
+	}
+	if _, err := parseClock(s.StartTime); err != nil {
+		return ErrInvalidSchedule
+	}
+	if s.ValidUntil != nil && s.ValidUntil.Before(s.ValidFrom) {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
+// Occurrences returns the sessions of the schedule starting within [from, to).
+// The start time is resolved in the branch location so daylight saving changes keep the local hour.
+func (s *ClassSchedules) Occurrences(from, to time.Time, loc *time.Location) []ClassSessions {
+	clock, err := parseClock(s.StartTime)
+	if err != nil {
+		return nil
+	}
+
+	var sessions []ClassSessions
+	localFrom := from.In(loc)
+	day := time.Date(localFrom.Year(), localFrom.Month(), localFrom.Day(), 0, 0, 0, 0, loc)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if int16(day.Weekday()) != s.Weekday || !s.activeOn(day) {
+			continue
+		}
+		startsAt := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if startsAt.Before(from) || !startsAt.Before(to) {
+			continue
+		}
+		scheduleID := s.ScheduleID
+		sessions = append(sessions, ClassSessions{
+			ScheduleID:   &scheduleID,
+			ClassTypeID:  s.ClassTypeID,
+			BranchID:     s.BranchID,
+			RoomID:       s.RoomID,
+			InstructorID: s.InstructorID,
+			StartsAt:     startsAt.UTC(),
+			EndsAt:       startsAt.Add(time.Duration(s.DurationMinutes) * time.Minute).UTC(),
+			Capacity:     s.Capacity,
+			Status:       SessionScheduled,
+		})
+	}
+	return sessions
+}
+
+// activeOn reports whether the local day is within the validity period
+func (s *ClassSchedules) activeOn(day time.Time) bool {
+	date := truncateDay(day)
+	if date.Before(truncateDay(s.ValidFrom)) {
+		return false
+	}
+	return s.ValidUntil == nil || !date.After(truncateDay(*s.ValidUntil))
+}
+
+// IsBookable reports whether clients can still book or join the waitlist
+func (s *ClassSessions) IsBookable(now time.Time) bool {
+	return s.Status == SessionScheduled && now.Before(s.StartsAt)
+}
+
+// CanCancel checks whether a booked spot can still be released.
+// Waitlist spots can be released at any time before the class starts.
+func (b *ClassBookings) CanCancel(now time.Time, cutoff time.Duration) error {
+	if b.Status == BookingCancelled {
+		return ErrBookingNotOpen
+	}
+	if b.Session == nil {
+		return nil
+	}
+	if b.Status == BookingBooked && now.After(b.Session.StartsAt.Add(-cutoff)) {
+		return ErrCancellationClosed
+	}
+	if !now.Before(b.Session.StartsAt) {
+		return ErrCancellationClosed
+	}
+	return nil
+}
+
+func parseClock(value string) (time.Time, error) {
+	if t, err := time.Parse("15:04", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("15:04:05", value)
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+type SessionFilter struct {
+	BranchID     *uuid.UUID
+	ClassTypeID  *uuid.UUID
+	InstructorID *uuid.UUID
+	From         time.Time
+	To           time.Time
+}
+
+type ClassTypePayload struct {
+	Name            string `json:"name" binding:"required,max=100"`
+	Description     string `json:"description"`
+	DurationMinutes int    `json:"duration_minutes" binding:"required,min=1"`
+	IsActive        *bool  `json:"is_active"`
+}
+
+type RoomPayload struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Capacity int    `json:"capacity" binding:"required,min=1"`
+}
+
+type SchedulePayload struct {
+	ClassTypeID     string `json:"class_type_id" binding:"required,uuid"`
+	RoomID          string `json:"room_id" binding:"omitempty,uuid"`
+	InstructorID    string `json:"instructor_id" binding:"omitempty,uuid"`
+	Weekday         int16  `json:"weekday" binding:"min=0,max=6"`
+	StartTime       string `json:"start_time" binding:"required"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=1"`
+	Capacity        int    `json:"capacity" binding:"required,min=1"`
+	ValidFrom       string `json:"valid_from"`
+	ValidUntil      string `json:"valid_until"`
+}
+
+type AssignInstructorPayload struct {
+	InstructorID string `json:"instructor_id" binding:"omitempty,uuid"`
+}
+
+// NewClassTypeFromPayload maps the request payload into a class type model
+func NewClassTypeFromPayload(payload ClassTypePayload) *ClassTypes {
+	classType := &ClassTypes{
+		Name:            payload.Name,
+		Description:     payload.Description,
+		DurationMinutes: payload.DurationMinutes,
+		IsActive:        true,
+	}
+	if payload.IsActive != nil {
+		classType.IsActive = *payload.IsActive
+	}
+	return classType
+}
+
+// NewScheduleFromPayload maps the request payload into a schedule of the branch.
+// The schedule starts today when no valid_from date is given.
+func NewScheduleFromPayload(branchID uuid.UUID, payload SchedulePayload, now time.Time) (*ClassSchedules, error) {
+	classTypeID, err := uuid.Parse(payload.ClassTypeID)
+	if err != nil {
+		return nil, err
+	}
+	schedule := &ClassSchedules{
+		ClassTypeID:     classTypeID,
+		BranchID:        branchID,
+		Weekday:         payload.Weekday,
+		StartTime:       payload.StartTime,
+		DurationMinutes: payload.DurationMinutes,
+		Capacity:        payload.Capacity,
+		ValidFrom:       truncateDay(now),
+		IsActive:        true,
+	}
+	if payload.RoomID != "" {
+		roomID, err := uuid.Parse(payload.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		schedule.RoomID = &roomID
+	}
+	if payload.InstructorID != "" {
+		instructorID, err := uuid.Parse(payload.InstructorID)
+		if err != nil {
+			return nil, err
+		}
+		schedule.InstructorID = &instructorID
+	}
+	if payload.ValidFrom != "" {
+		if schedule.ValidFrom, err = time.Parse(time.DateOnly, payload.ValidFrom); err != nil {
+			return nil, ErrInvalidSchedule
+		}
+	}
+	if payload.ValidUntil != "" {
+		until, err := time.Parse(time.DateOnly, payload.ValidUntil)
+		if err != nil {
+			return nil, ErrInvalidSchedule
+		}
+		schedule.ValidUntil = &until
+	}
+	return schedule, nil
+}
@@ -0,0 +1,46 @@
+package classdomain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ClassTypeRepository interface {
+	Create(ctx context.Context, classType *ClassTypes) error
+	GetByID(ctx context.Context, classTypeID uuid.UUID) (*ClassTypes, error)
+	List(ctx context.Context, onlyActive bool) ([]ClassTypes, error)
+	Update(ctx context.Context, classType *ClassTypes) error
+	Delete(ctx context.Context, classTypeID uuid.UUID) error
+}
+
+type RoomRepository interface {
+	Create(ctx context.Context, room *Rooms) error
+	GetByID(ctx context.Context, roomID uuid.UUID) (*Rooms, error)
+	ListByBranch(ctx context.Context, branchID uuid.UUID) ([]Rooms, error)
+	Update(ctx context.Context, room *Rooms) error
+	Delete(ctx context.Context, roomID uuid.UUID) error
+}
+
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *ClassSchedules) error
+	GetByID(ctx context.Context, scheduleID uuid.UUID) (*ClassSchedules, error)
+	List(ctx context.Context, branchID *uuid.UUID, onlyActive bool) ([]ClassSchedules, error)
+	Deactivate(ctx context.Context, scheduleID uuid.UUID) error
+}
+
+type SessionRepository interface {
+	CreateMany(ctx context.Context, sessions []ClassSessions) (int, error)
+	GetByID(ctx context.Context, sessionID uuid.UUID) (*ClassSessions, error)
+	List(ctx context.Context, filter SessionFilter) ([]ClassSessions, error)
+	SetInstructor(ctx context.Context, sessionID uuid.UUID, instructorID *uuid.UUID) error
+	Cancel(ctx context.Context, sessionID uuid.UUID) error
+}
+
+type BookingRepository interface {
+	Book(ctx context.Context, booking *ClassBookings) error
+	GetByID(ctx context.Context, bookingID uuid.UUID) (*ClassBookings, error)
+	ListBySession(ctx context.Context, sessionID uuid.UUID) ([]ClassBookings, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]ClassBookings, error)
+	Cancel(ctx context.Context, bookingID uuid.UUID) (*ClassBookings, error)
+}
@@ -0,0 +1,32 @@
+package classdomain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+)
+
+type ClassServicesInterface interface {
+	CreateClassType(ctx context.Context, classType *ClassTypes) error
+	GetClassType(ctx context.Context, classTypeID uuid.UUID) (*ClassTypes, error)
+	ListClassTypes(ctx context.Context, onlyActive bool) ([]ClassTypes, error)
+	UpdateClassType(ctx context.Context, classType *ClassTypes) error
+	DeleteClassType(ctx context.Context, classTypeID uuid.UUID) error
+	CreateRoom(ctx context.Context, room *Rooms) error
+	ListRooms(ctx context.Context, branchID uuid.UUID) ([]Rooms, error)
+	UpdateRoom(ctx context.Context, room *Rooms) error
+	DeleteRoom(ctx context.Context, branchID uuid.UUID, roomID uuid.UUID) error
+	CreateSchedule(ctx context.Context, schedule *ClassSchedules) error
+	ListSchedules(ctx context.Context, branchID uuid.UUID) ([]ClassSchedules, error)
+	DeactivateSchedule(ctx context.Context, branchID uuid.UUID, scheduleID uuid.UUID) error
+	GenerateSessions(ctx context.Context) (int, error)
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*ClassSessions, error)
+	ListSessions(ctx context.Context, filter SessionFilter) ([]ClassSessions, error)
+	AssignInstructor(ctx context.Context, branchID uuid.UUID, sessionID uuid.UUID, instructorID *uuid.UUID) error
+	CancelSession(ctx context.Context, branchID uuid.UUID, sessionID uuid.UUID) error
+	Book(ctx context.Context, user *authdomain.Users, sessionID uuid.UUID) (*ClassBookings, error)
+	CancelBooking(ctx context.Context, user *authdomain.Users, bookingID uuid.UUID) (*ClassBookings, error)
+	ListSessionBookings(ctx context.Context, branchID uuid.UUID, sessionID uuid.UUID) ([]ClassBookings, error)
+	ListUserBookings(ctx context.Context, userID uuid.UUID) ([]ClassBookings, error)
+}
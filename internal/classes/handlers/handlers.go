@@ -0,0 +1,737 @@
+package classhandlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	appservices "github.com/vitalfit/api/internal/app/services"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+type ClassHandlersInterface interface {
+	ClassRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+}
+
+type ClassHandlers struct {
+	services appservices.Services
+}
+
+func NewClassHandlers(services appservices.Services) *ClassHandlers {
+	return &ClassHandlers{services: services}
+}
+
+// @Summary		List class types
+// @Description	Returns the active class catalog.
+// @Tags			Classes
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"class types"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/classes/types [get]
+func (h *ClassHandlers) listClassTypesHandler(c *gin.Context) {
+	classTypes, err := h.services.ClassServices.ListClassTypes(c.Request.Context(), true)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"class_types": classTypes,
+	})
+}
+
+// @Summary		Get class type
+// @Description	Returns a single class type of the catalog.
+// @Tags			Classes
+// @Produce		json
+// @Param			class_type_id	path		string					true	"Class type ID"
+// @Success		200				{object}	map[string]interface{}	"class type"
+// @Failure		400				{object}	map[string]interface{}	"invalid class type id"
+// @Failure		404				{object}	map[string]interface{}	"class type not found"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/classes/types/{class_type_id} [get]
+func (h *ClassHandlers) getClassTypeHandler(c *gin.Context) {
+	classTypeID, err := uuid.Parse(c.Param("class_type_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	classType, err := h.services.ClassServices.GetClassType(c.Request.Context(), classTypeID)
+	if err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, classType)
+}
+
+// @Summary		Create class type
+// @Description	Adds a class type to the catalog. The duration is used as default for new schedules.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			class_type	body		classdomain.ClassTypePayload	true	"Class type data"
+// @Success		201			{object}	map[string]interface{}			"class type created"
+// @Failure		400			{object}	map[string]interface{}			"bad request"
+// @Failure		403			{object}	map[string]interface{}			"forbidden"
+// @Failure		409			{object}	map[string]interface{}			"class type name already exists"
+// @Failure		500			{object}	map[string]interface{}			"internal server error"
+// @Router			/classes/types [post]
+func (h *ClassHandlers) createClassTypeHandler(c *gin.Context) {
+	var payload classdomain.ClassTypePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	classType := classdomain.NewClassTypeFromPayload(payload)
+	if err := h.services.ClassServices.CreateClassType(c.Request.Context(), classType); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, classType)
+}
+
+// @Summary		Update class type
+// @Description	Replaces the data of a class type. Existing schedules keep their duration.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			class_type_id	path		string							true	"Class type ID"
+// @Param			class_type		body		classdomain.ClassTypePayload	true	"Class type data"
+// @Success		200				{object}	map[string]interface{}			"class type updated"
+// @Failure		400				{object}	map[string]interface{}			"bad request"
+// @Failure		403				{object}	map[string]interface{}			"forbidden"
+// @Failure		404				{object}	map[string]interface{}			"class type not found"
+// @Failure		409				{object}	map[string]interface{}			"class type name already exists"
+// @Failure		500				{object}	map[string]interface{}			"internal server error"
+// @Router			/classes/types/{class_type_id} [put]
+func (h *ClassHandlers) updateClassTypeHandler(c *gin.Context) {
+	var payload classdomain.ClassTypePayload
+	classTypeID, err := uuid.Parse(c.Param("class_type_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	classType := classdomain.NewClassTypeFromPayload(payload)
+	classType.ClassTypeID = classTypeID
+	if err := h.services.ClassServices.UpdateClassType(c.Request.Context(), classType); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, classType)
+}
+
+// @Summary		Delete class type
+// @Description	Soft deletes a class type. Sessions already scheduled keep a reference to it.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			class_type_id	path	string	true	"Class type ID"
+// @Success		204				"Class type deleted. No content returned."
+// @Failure		400				{object}	map[string]interface{}	"invalid class type id"
+// @Failure		403				{object}	map[string]interface{}	"forbidden"
+// @Failure		404				{object}	map[string]interface{}	"class type not found"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/classes/types/{class_type_id} [delete]
+func (h *ClassHandlers) deleteClassTypeHandler(c *gin.Context) {
+	classTypeID, err := uuid.Parse(c.Param("class_type_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.ClassServices.DeleteClassType(c.Request.Context(), classTypeID); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		List branch rooms
+// @Description	Returns the rooms of the branch where classes take place.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path		string					true	"Branch ID"
+// @Success		200			{object}	map[string]interface{}	"rooms"
+// @Failure		400			{object}	map[string]interface{}	"invalid branch id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/rooms [get]
+func (h *ClassHandlers) listRoomsHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	rooms, err := h.services.ClassServices.ListRooms(c.Request.Context(), branchID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"rooms": rooms,
+	})
+}
+
+// @Summary		Create room
+// @Description	Adds a room to the branch. The room capacity caps the capacity of its schedules.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			branch_id	path		string					true	"Branch ID"
+// @Param			room		body		classdomain.RoomPayload	true	"Room data"
+// @Success		201			{object}	map[string]interface{}	"room created"
+// @Failure		400			{object}	map[string]interface{}	"bad request"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"branch not found"
+// @Failure		409			{object}	map[string]interface{}	"room name already exists in the branch"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/rooms [post]
+func (h *ClassHandlers) createRoomHandler(c *gin.Context) {
+	var payload classdomain.RoomPayload
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	room := &classdomain.Rooms{
+		BranchID: branchID,
+		Name:     payload.Name,
+		Capacity: payload.Capacity,
+	}
+	if err := h.services.ClassServices.CreateRoom(c.Request.Context(), room); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, room)
+}
+
+// @Summary		Update room
+// @Description	Replaces the name and capacity of a room of the branch.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			branch_id	path		string					true	"Branch ID"
+// @Param			room_id		path		string					true	"Room ID"
+// @Param			room		body		classdomain.RoomPayload	true	"Room data"
+// @Success		200			{object}	map[string]interface{}	"room updated"
+// @Failure		400			{object}	map[string]interface{}	"bad request"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"room not found"
+// @Failure		409			{object}	map[string]interface{}	"room name already exists in the branch"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/rooms/{room_id} [put]
+func (h *ClassHandlers) updateRoomHandler(c *gin.Context) {
+	var payload classdomain.RoomPayload
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	roomID, err := uuid.Parse(c.Param("room_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	room := &classdomain.Rooms{
+		RoomID:   roomID,
+		BranchID: branchID,
+		Name:     payload.Name,
+		Capacity: payload.Capacity,
+	}
+	if err := h.services.ClassServices.UpdateRoom(c.Request.Context(), room); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, room)
+}
+
+// @Summary		Delete room
+// @Description	Soft deletes a room of the branch.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path	string	true	"Branch ID"
+// @Param			room_id		path	string	true	"Room ID"
+// @Success		204			"Room deleted. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"invalid id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"room not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/rooms/{room_id} [delete]
+func (h *ClassHandlers) deleteRoomHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	roomID, err := uuid.Parse(c.Param("room_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.ClassServices.DeleteRoom(c.Request.Context(), branchID, roomID); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		List branch schedules
+// @Description	Returns the weekly class schedules of the branch, including the inactive ones.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path		string					true	"Branch ID"
+// @Success		200			{object}	map[string]interface{}	"schedules"
+// @Failure		400			{object}	map[string]interface{}	"invalid branch id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/schedules [get]
+func (h *ClassHandlers) listSchedulesHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	schedules, err := h.services.ClassServices.ListSchedules(c.Request.Context(), branchID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"schedules": schedules,
+	})
+}
+
+// @Summary		Create schedule
+// @Description	Adds a weekly recurring class to the branch and creates its sessions for the coming weeks. Weekday uses 0 (Sunday) to 6 and start_time is HH:MM in the branch timezone. The duration defaults to the class type duration.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			branch_id	path		string						true	"Branch ID"
+// @Param			schedule	body		classdomain.SchedulePayload	true	"Schedule data"
+// @Success		201			{object}	map[string]interface{}		"schedule created"
+// @Failure		400			{object}	map[string]interface{}		"bad request, invalid schedule or capacity exceeds the room"
+// @Failure		403			{object}	map[string]interface{}		"forbidden"
+// @Failure		404			{object}	map[string]interface{}		"class type, room or instructor not found"
+// @Failure		500			{object}	map[string]interface{}		"internal server error"
+// @Router			/branches/{branch_id}/schedules [post]
+func (h *ClassHandlers) createScheduleHandler(c *gin.Context) {
+	var payload classdomain.SchedulePayload
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	schedule, err := classdomain.NewScheduleFromPayload(branchID, payload, time.Now())
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.ClassServices.CreateSchedule(c.Request.Context(), schedule); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// @Summary		Deactivate schedule
+// @Description	Stops a weekly schedule from creating new sessions. Sessions already created are kept and can be cancelled one by one.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path	string	true	"Branch ID"
+// @Param			schedule_id	path	string	true	"Schedule ID"
+// @Success		204			"Schedule deactivated. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"invalid id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"schedule not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/schedules/{schedule_id} [delete]
+func (h *ClassHandlers) deactivateScheduleHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	scheduleID, err := uuid.Parse(c.Param("schedule_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.ClassServices.DeactivateSchedule(c.Request.Context(), branchID, scheduleID); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Generate sessions
+// @Description	Creates the missing sessions of every active schedule for the coming weeks. Safe to run repeatedly.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"number of sessions created"
+// @Failure		403	{object}	map[string]interface{}	"forbidden"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/classes/sessions/generate [post]
+func (h *ClassHandlers) generateSessionsHandler(c *gin.Context) {
+	created, err := h.services.ClassServices.GenerateSessions(c.Request.Context())
+	if err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"created": created,
+	})
+}
+
+// @Summary		List class sessions
+// @Description	Returns the class timetable with the number of booked spots. Defaults to the next 7 days; from and to accept RFC3339 or YYYY-MM-DD.
+// @Tags			Classes
+// @Produce		json
+// @Param			branch_id		query		string					false	"Branch ID"
+// @Param			class_type_id	query		string					false	"Class type ID"
+// @Param			instructor_id	query		string					false	"Instructor ID"
+// @Param			from			query		string					false	"Start of the range"
+// @Param			to				query		string					false	"End of the range"
+// @Success		200				{object}	map[string]interface{}	"sessions"
+// @Failure		400				{object}	map[string]interface{}	"invalid filter"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/classes/sessions [get]
+func (h *ClassHandlers) listSessionsHandler(c *gin.Context) {
+	var (
+		filter classdomain.SessionFilter
+		err    error
+	)
+	if filter.BranchID, err = queryUUID(c, "branch_id"); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if filter.ClassTypeID, err = queryUUID(c, "class_type_id"); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if filter.InstructorID, err = queryUUID(c, "instructor_id"); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if filter.From, err = queryTime(c, "from", time.Now()); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if filter.To, err = queryTime(c, "to", filter.From.AddDate(0, 0, 7)); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	sessions, err := h.services.ClassServices.ListSessions(c.Request.Context(), filter)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+	})
+}
+
+// @Summary		Get class session
+// @Description	Returns a single class session with the number of booked spots.
+// @Tags			Classes
+// @Produce		json
+// @Param			session_id	path		string					true	"Session ID"
+// @Success		200			{object}	map[string]interface{}	"session"
+// @Failure		400			{object}	map[string]interface{}	"invalid session id"
+// @Failure		404			{object}	map[string]interface{}	"session not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/classes/sessions/{session_id} [get]
+func (h *ClassHandlers) getSessionHandler(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	session, err := h.services.ClassServices.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// @Summary		Assign session instructor
+// @Description	Replaces the instructor of a single session. An empty instructor_id leaves the session unassigned.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			branch_id	path	string								true	"Branch ID"
+// @Param			session_id	path	string								true	"Session ID"
+// @Param			payload		body	classdomain.AssignInstructorPayload	true	"Instructor"
+// @Success		204			"Instructor assigned. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"bad request"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"session or instructor not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/sessions/{session_id}/instructor [put]
+func (h *ClassHandlers) assignInstructorHandler(c *gin.Context) {
+	var payload classdomain.AssignInstructorPayload
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	var instructorID *uuid.UUID
+	if payload.InstructorID != "" {
+		id, err := uuid.Parse(payload.InstructorID)
+		if err != nil {
+			h.services.LogErrors.BadRequestResponse(c, err)
+			return
+		}
+		instructorID = &id
+	}
+
+	if err := h.services.ClassServices.AssignInstructor(c.Request.Context(), branchID, sessionID, instructorID); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Cancel session
+// @Description	Cancels a scheduled session together with all its bookings and waitlist spots.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path	string	true	"Branch ID"
+// @Param			session_id	path	string	true	"Session ID"
+// @Success		204			"Session cancelled. No content returned."
+// @Failure		400			{object}	map[string]interface{}	"invalid id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"session not found or already cancelled"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/sessions/{session_id}/cancel [post]
+func (h *ClassHandlers) cancelSessionHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.ClassServices.CancelSession(c.Request.Context(), branchID, sessionID); err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		List session bookings
+// @Description	Returns the booked clients of a session followed by the waitlist in arrival order.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			branch_id	path		string					true	"Branch ID"
+// @Param			session_id	path		string					true	"Session ID"
+// @Success		200			{object}	map[string]interface{}	"bookings"
+// @Failure		400			{object}	map[string]interface{}	"invalid id"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"session not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/branches/{branch_id}/sessions/{session_id}/bookings [get]
+func (h *ClassHandlers) listSessionBookingsHandler(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Param("branch_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	bookings, err := h.services.ClassServices.ListSessionBookings(c.Request.Context(), branchID, sessionID)
+	if err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"bookings": bookings,
+	})
+}
+
+// @Summary		Book class
+// @Description	Books a spot in the session for the authenticated client. When the session is full the client joins the waitlist and is promoted automatically when a spot is released.
+// @Tags			Classes
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			session_id	path		string					true	"Session ID"
+// @Success		201			{object}	map[string]interface{}	"booking with status booked or waitlisted"
+// @Failure		400			{object}	map[string]interface{}	"session not open for booking or no valid subscription"
+// @Failure		401			{object}	map[string]interface{}	"unauthorized"
+// @Failure		403			{object}	map[string]interface{}	"client is blocked (code CLIENT_BLOCKED)"
+// @Failure		404			{object}	map[string]interface{}	"session not found"
+// @Failure		409			{object}	map[string]interface{}	"client already booked this session"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/classes/sessions/{session_id}/bookings [post]
+func (h *ClassHandlers) bookHandler(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	booking, err := h.services.ClassServices.Book(c.Request.Context(), user, sessionID)
+	if err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, booking)
+}
+
+// @Summary		List my bookings
+// @Description	Returns the class bookings of the authenticated client, newest first.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"bookings"
+// @Failure		401	{object}	map[string]interface{}	"unauthorized"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/user/bookings [get]
+func (h *ClassHandlers) myBookingsHandler(c *gin.Context) {
+	user := h.services.UserServices.GetUserFromContext(c)
+
+	bookings, err := h.services.ClassServices.ListUserBookings(c.Request.Context(), user.UserID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"bookings": bookings,
+	})
+}
+
+// @Summary		Cancel my booking
+// @Description	Releases a booking of the authenticated client. Booked spots cannot be released within the cancellation cutoff before the class; waitlist spots can be released until the class starts.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			booking_id	path		string					true	"Booking ID"
+// @Success		200			{object}	map[string]interface{}	"booking"
+// @Failure		400			{object}	map[string]interface{}	"cancellation period is over or booking already cancelled"
+// @Failure		401			{object}	map[string]interface{}	"unauthorized"
+// @Failure		404			{object}	map[string]interface{}	"booking not found"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/user/bookings/{booking_id}/cancel [post]
+func (h *ClassHandlers) cancelBookingHandler(c *gin.Context) {
+	bookingID, err := uuid.Parse(c.Param("booking_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	user := h.services.UserServices.GetUserFromContext(c)
+	booking, err := h.services.ClassServices.CancelBooking(c.Request.Context(), user, bookingID)
+	if err != nil {
+		h.classErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, booking)
+}
+
+func (h *ClassHandlers) classErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, shared_errors.ErrConflict):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, shared_errors.ErrClientBlocked):
+		h.services.LogErrors.ClientBlockedResponse(c)
+	case errors.Is(err, classdomain.ErrInvalidSchedule),
+		errors.Is(err, classdomain.ErrCapacityExceeded),
+		errors.Is(err, classdomain.ErrSessionUnavailable),
+		errors.Is(err, classdomain.ErrCancellationClosed),
+		errors.Is(err, classdomain.ErrBookingNotOpen),
+		errors.Is(err, classdomain.ErrNoActiveSubscription),
+		errors.Is(err, classdomain.ErrNotClient):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
+
+// queryUUID parses an optional uuid query parameter
+func queryUUID(c *gin.Context, key string) (*uuid.UUID, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// queryTime parses an optional RFC3339 or YYYY-MM-DD query parameter
+func queryTime(c *gin.Context, key string, fallback time.Time) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
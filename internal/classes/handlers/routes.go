@@ -0,0 +1,54 @@
+package classhandlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+func (r *ClassHandlers) ClassRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+
+	classGroup := rg.Group("/classes")
+	{ //public routes
+		classGroup.GET("/types", r.listClassTypesHandler)
+		classGroup.GET("/types/:class_type_id", r.getClassTypeHandler)
+		classGroup.GET("/sessions", r.listSessionsHandler)
+		classGroup.GET("/sessions/:session_id", r.getSessionHandler)
+
+		clientGroup := classGroup.Group("").Use(m.AuthJwtTokenMiddleware())
+		{
+			clientGroup.POST("/sessions/:session_id/bookings", r.bookHandler)
+		}
+
+		superAdminGroup := classGroup.Group("").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("super_admin"))
+		{
+			superAdminGroup.POST("/types", r.createClassTypeHandler)
+			superAdminGroup.PUT("/types/:class_type_id", r.updateClassTypeHandler)
+			superAdminGroup.DELETE("/types/:class_type_id", r.deleteClassTypeHandler)
+			superAdminGroup.POST("/sessions/generate", r.generateSessionsHandler)
+		}
+	}
+
+	branchAdminGroup := rg.Group("/branches/:branch_id").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("branch_admin"), m.CheckBranchAccess("branch_id"))
+	{
+		branchAdminGroup.GET("/rooms", r.listRoomsHandler)
+		branchAdminGroup.POST("/rooms", r.createRoomHandler)
+		branchAdminGroup.PUT("/rooms/:room_id", r.updateRoomHandler)
+		branchAdminGroup.DELETE("/rooms/:room_id", r.deleteRoomHandler)
+		branchAdminGroup.GET("/schedules", r.listSchedulesHandler)
+		branchAdminGroup.POST("/schedules", r.createScheduleHandler)
+		branchAdminGroup.DELETE("/schedules/:schedule_id", r.deactivateScheduleHandler)
+		branchAdminGroup.PUT("/sessions/:session_id/instructor", r.assignInstructorHandler)
+		branchAdminGroup.POST("/sessions/:session_id/cancel", r.cancelSessionHandler)
+	}
+
+	frontDeskGroup := rg.Group("/branches/:branch_id").Use(m.AuthJwtTokenMiddleware(), m.CheckRoleAccess("recepcionist"), m.CheckBranchAccess("branch_id"))
+	{
+		frontDeskGroup.GET("/sessions/:session_id/bookings", r.listSessionBookingsHandler)
+	}
+
+	userGroup := rg.Group("/user").Use(m.AuthJwtTokenMiddleware())
+	{ //private routes
+		userGroup.GET("/bookings", r.myBookingsHandler)
+		userGroup.POST("/bookings/:booking_id/cancel", r.cancelBookingHandler)
+	}
+}
@@ -0,0 +1,200 @@
+package classrepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookingStore struct {
+	db *gorm.DB
+}
+
+func NewBookingStore(db *gorm.DB) *BookingStore {
+	return &BookingStore{db: db}
+}
+
+// Book takes a spot in the session, or a place at the end of the waitlist when the session is full.
+// The session row is locked so concurrent bookings cannot exceed the capacity.
+func (s *BookingStore) Book(ctx context.Context, booking *classdomain.ClassBookings) error {
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+		defer cancel()
+
+		var session classdomain.ClassSessions
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("session_id = ?", booking.SessionID).
+			First(&session).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shared_errors.ErrNotFound //rollback
+			}
+			return err //rollback
+		}
+		if !session.IsBookable(time.Now()) {
+			return classdomain.ErrSessionUnavailable //rollback
+		}
+
+		var booked int64
+		err = tx.WithContext(ctx).
+			Model(&classdomain.ClassBookings{}).
+			Where("session_id = ? AND status = ?", booking.SessionID, classdomain.BookingBooked).
+			Count(&booked).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		booking.Status = classdomain.BookingBooked
+		if booked >= int64(session.Capacity) {
+			booking.Status = classdomain.BookingWaitlisted
+		}
+		if err := tx.WithContext(ctx).Omit(clause.Associations).Create(booking).Error; err != nil {
+			return mapClassError(err) //rollback
+		}
+		return nil //commit
+	})
+}
+
+func (s *BookingStore) GetByID(ctx context.Context, bookingID uuid.UUID) (*classdomain.ClassBookings, error) {
+	var booking classdomain.ClassBookings
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Preload("Session").
+		Preload("Session.ClassType", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Where("booking_id = ?", bookingID).
+		First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// ListBySession returns the open bookings followed by the waitlist in FIFO order
+func (s *BookingStore) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]classdomain.ClassBookings, error) {
+	var bookings []classdomain.ClassBookings
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Where("session_id = ? AND status IN ?", sessionID, openBookingStatuses).
+		Order("status, created_at").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (s *BookingStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]classdomain.ClassBookings, error) {
+	var bookings []classdomain.ClassBookings
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Preload("Session").
+		Preload("Session.ClassType", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// Cancel releases the booking. When a booked spot is released the oldest waitlisted
+// booking of the session is promoted and returned.
+func (s *BookingStore) Cancel(ctx context.Context, bookingID uuid.UUID) (*classdomain.ClassBookings, error) {
+	var promoted *classdomain.ClassBookings
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+		defer cancel()
+
+		var booking classdomain.ClassBookings
+		err := tx.WithContext(ctx).
+			Where("booking_id = ?", bookingID).
+			First(&booking).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shared_errors.ErrNotFound //rollback
+			}
+			return err //rollback
+		}
+
+		// lock the session first, in the same order as Book, to serialize capacity changes
+		err = tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("session_id = ?", booking.SessionID).
+			First(&classdomain.ClassSessions{}).Error
+		if err != nil {
+			return err //rollback
+		}
+		// read the status again under the lock, a concurrent cancel may have promoted the booking
+		err = tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("booking_id = ?", bookingID).
+			First(&booking).Error
+		if err != nil {
+			return err //rollback
+		}
+
+		now := time.Now()
+		result := tx.WithContext(ctx).
+			Model(&classdomain.ClassBookings{}).
+			Where("booking_id = ? AND status IN ?", bookingID, openBookingStatuses).
+			Updates(map[string]interface{}{
+				"status":       classdomain.BookingCancelled,
+				"cancelled_at": now,
+			})
+		if result.Error != nil {
+			return result.Error //rollback
+		}
+		if result.RowsAffected == 0 {
+			return classdomain.ErrBookingNotOpen //rollback
+		}
+		if booking.Status != classdomain.BookingBooked {
+			return nil //commit
+		}
+
+		var next classdomain.ClassBookings
+		err = tx.WithContext(ctx).
+			Where("session_id = ? AND status = ?", booking.SessionID, classdomain.BookingWaitlisted).
+			Order("created_at").
+			First(&next).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil //commit
+			}
+			return err //rollback
+		}
+
+		next.Status = classdomain.BookingBooked
+		next.PromotedAt = &now
+		err = tx.WithContext(ctx).
+			Model(&next).
+			Select("status", "promoted_at").
+			Updates(&next).Error
+		if err != nil {
+			return err //rollback
+		}
+		promoted = &next
+		return nil //commit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
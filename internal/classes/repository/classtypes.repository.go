@@ -0,0 +1,104 @@
+package classrepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+)
+
+type ClassTypeStore struct {
+	db *gorm.DB
+}
+
+func NewClassTypeStore(db *gorm.DB) *ClassTypeStore {
+	return &ClassTypeStore{db: db}
+}
+
+func (s *ClassTypeStore) Create(ctx context.Context, classType *classdomain.ClassTypes) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(classType).Error; err != nil {
+		return mapClassError(err)
+	}
+	return nil
+}
+
+func (s *ClassTypeStore) GetByID(ctx context.Context, classTypeID uuid.UUID) (*classdomain.ClassTypes, error) {
+	var classType classdomain.ClassTypes
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Where("class_type_id = ?", classTypeID).First(&classType).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &classType, nil
+}
+
+func (s *ClassTypeStore) List(ctx context.Context, onlyActive bool) ([]classdomain.ClassTypes, error) {
+	var classTypes []classdomain.ClassTypes
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Order("name")
+	if onlyActive {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Find(&classTypes).Error; err != nil {
+		return nil, err
+	}
+	return classTypes, nil
+}
+
+func (s *ClassTypeStore) Update(ctx context.Context, classType *classdomain.ClassTypes) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Model(classType).
+		Select("name", "description", "duration_minutes", "is_active").
+		Updates(classType)
+	if result.Error != nil {
+		return mapClassError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *ClassTypeStore) Delete(ctx context.Context, classTypeID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&classdomain.ClassTypes{}, classTypeID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func mapClassError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return shared_errors.ErrConflict
+		case "23503":
+			return shared_errors.ErrNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package classrepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+)
+
+type RoomStore struct {
+	db *gorm.DB
+}
+
+func NewRoomStore(db *gorm.DB) *RoomStore {
+	return &RoomStore{db: db}
+}
+
+func (s *RoomStore) Create(ctx context.Context, room *classdomain.Rooms) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(room).Error; err != nil {
+		return mapClassError(err)
+	}
+	return nil
+}
+
+func (s *RoomStore) GetByID(ctx context.Context, roomID uuid.UUID) (*classdomain.Rooms, error) {
+	var room classdomain.Rooms
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Where("room_id = ?", roomID).First(&room).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (s *RoomStore) ListByBranch(ctx context.Context, branchID uuid.UUID) ([]classdomain.Rooms, error) {
+	var rooms []classdomain.Rooms
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Where("branch_id = ?", branchID).
+		Order("name").
+		Find(&rooms).Error
+	if err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+func (s *RoomStore) Update(ctx context.Context, room *classdomain.Rooms) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Model(room).
+		Where("branch_id = ?", room.BranchID).
+		Select("name", "capacity").
+		Updates(room)
+	if result.Error != nil {
+		return mapClassError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *RoomStore) Delete(ctx context.Context, roomID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&classdomain.Rooms{}, roomID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
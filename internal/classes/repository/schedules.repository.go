@@ -0,0 +1,89 @@
+package classrepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduleStore struct {
+	db *gorm.DB
+}
+
+func NewScheduleStore(db *gorm.DB) *ScheduleStore {
+	return &ScheduleStore{db: db}
+}
+
+func (s *ScheduleStore) Create(ctx context.Context, schedule *classdomain.ClassSchedules) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Omit(clause.Associations).Create(schedule).Error; err != nil {
+		return mapClassError(err)
+	}
+	return nil
+}
+
+func (s *ScheduleStore) GetByID(ctx context.Context, scheduleID uuid.UUID) (*classdomain.ClassSchedules, error) {
+	var schedule classdomain.ClassSchedules
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.withClassType(s.db.WithContext(ctx)).
+		Where("schedule_id = ?", scheduleID).
+		First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (s *ScheduleStore) List(ctx context.Context, branchID *uuid.UUID, onlyActive bool) ([]classdomain.ClassSchedules, error) {
+	var schedules []classdomain.ClassSchedules
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.withClassType(s.db.WithContext(ctx)).Order("weekday, start_time")
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+	if onlyActive {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Deactivate stops the schedule from producing new sessions
+func (s *ScheduleStore) Deactivate(ctx context.Context, scheduleID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Model(&classdomain.ClassSchedules{}).
+		Where("schedule_id = ?", scheduleID).
+		Update("is_active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+// withClassType preloads the class type even if it was removed from the catalog afterwards
+func (s *ScheduleStore) withClassType(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("ClassType", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() })
+}
@@ -0,0 +1,142 @@
+package classrepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionStore struct {
+	db *gorm.DB
+}
+
+func NewSessionStore(db *gorm.DB) *SessionStore {
+	return &SessionStore{db: db}
+}
+
+// CreateMany inserts the sessions skipping the ones already materialized for the same schedule and start
+// and returns how many were created
+func (s *SessionStore) CreateMany(ctx context.Context, sessions []classdomain.ClassSessions) (int, error) {
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&sessions)
+	if result.Error != nil {
+		return 0, mapClassError(result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (s *SessionStore) GetByID(ctx context.Context, sessionID uuid.UUID) (*classdomain.ClassSessions, error) {
+	var session classdomain.ClassSessions
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.withDetails(s.db.WithContext(ctx)).
+		Where("session_id = ?", sessionID).
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *SessionStore) List(ctx context.Context, filter classdomain.SessionFilter) ([]classdomain.ClassSessions, error) {
+	var sessions []classdomain.ClassSessions
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.withDetails(s.db.WithContext(ctx)).
+		Where("starts_at >= ? AND starts_at < ?", filter.From, filter.To).
+		Order("starts_at")
+	if filter.BranchID != nil {
+		query = query.Where("branch_id = ?", *filter.BranchID)
+	}
+	if filter.ClassTypeID != nil {
+		query = query.Where("class_type_id = ?", *filter.ClassTypeID)
+	}
+	if filter.InstructorID != nil {
+		query = query.Where("instructor_id = ?", *filter.InstructorID)
+	}
+	if err := query.Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *SessionStore) SetInstructor(ctx context.Context, sessionID uuid.UUID, instructorID *uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Model(&classdomain.ClassSessions{}).
+		Where("session_id = ?", sessionID).
+		Update("instructor_id", instructorID)
+	if result.Error != nil {
+		return mapClassError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+// Cancel cancels the session together with every open booking and waitlist spot
+func (s *SessionStore) Cancel(ctx context.Context, sessionID uuid.UUID) error {
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+		defer cancel()
+
+		result := tx.WithContext(ctx).
+			Model(&classdomain.ClassSessions{}).
+			Where("session_id = ? AND status = ?", sessionID, classdomain.SessionScheduled).
+			Update("status", classdomain.SessionCancelled)
+		if result.Error != nil {
+			return result.Error //rollback
+		}
+		if result.RowsAffected == 0 {
+			return shared_errors.ErrNotFound //rollback
+		}
+
+		err := tx.WithContext(ctx).
+			Model(&classdomain.ClassBookings{}).
+			Where("session_id = ? AND status IN ?", sessionID, openBookingStatuses).
+			Updates(map[string]interface{}{
+				"status":       classdomain.BookingCancelled,
+				"cancelled_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
+
+// withDetails preloads the class type and counts the booked spots of each session
+func (s *SessionStore) withDetails(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Model(&classdomain.ClassSessions{}).
+		Select("class_sessions.*, (SELECT COUNT(*) FROM class_bookings cb WHERE cb.session_id = class_sessions.session_id AND cb.status = ?) AS booked_count", classdomain.BookingBooked).
+		Preload("ClassType", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() })
+}
+
+var openBookingStatuses = []classdomain.BookingStatusEnum{
+	classdomain.BookingBooked,
+	classdomain.BookingWaitlisted,
+}
@@ -0,0 +1,275 @@
+package classservices
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type ClassService struct {
+	store store.Storage
+}
+
+func NewClassService(store store.Storage) *ClassService {
+	return &ClassService{
+		store: store,
+	}
+}
+
+func (s *ClassService) CreateClassType(ctx context.Context, classType *classdomain.ClassTypes) error {
+	return s.store.ClassTypes.Create(ctx, classType)
+}
+
+func (s *ClassService) GetClassType(ctx context.Context, classTypeID uuid.UUID) (*classdomain.ClassTypes, error) {
+	return s.store.ClassTypes.GetByID(ctx, classTypeID)
+}
+
+func (s *ClassService) ListClassTypes(ctx context.Context, onlyActive bool) ([]classdomain.ClassTypes, error) {
+	return s.store.ClassTypes.List(ctx, onlyActive)
+}
+
+func (s *ClassService) UpdateClassType(ctx context.Context, classType *classdomain.ClassTypes) error {
+	return s.store.ClassTypes.Update(ctx, classType)
+}
+
+func (s *ClassService) DeleteClassType(ctx context.Context, classTypeID uuid.UUID) error {
+	return s.store.ClassTypes.Delete(ctx, classTypeID)
+}
+
+func (s *ClassService) CreateRoom(ctx context.Context, room *classdomain.Rooms) error {
+	return s.store.Rooms.Create(ctx, room)
+}
+
+func (s *ClassService) ListRooms(ctx context.Context, branchID uuid.UUID) ([]classdomain.Rooms, error) {
+	return s.store.Rooms.ListByBranch(ctx, branchID)
+}
+
+func (s *ClassService) UpdateRoom(ctx context.Context, room *classdomain.Rooms) error {
+	return s.store.Rooms.Update(ctx, room)
+}
+
+func (s *ClassService) DeleteRoom(ctx context.Context, branchID uuid.UUID, roomID uuid.UUID) error {
+	if _, err := s.branchRoom(ctx, branchID, roomID); err != nil {
+		return err
+	}
+	return s.store.Rooms.Delete(ctx, roomID)
+}
+
+// CreateSchedule saves the weekly slot and materializes its sessions within the schedule horizon
+func (s *ClassService) CreateSchedule(ctx context.Context, schedule *classdomain.ClassSchedules) error {
+	classType, err := s.store.ClassTypes.GetByID(ctx, schedule.ClassTypeID)
+	if err != nil {
+		return err
+	}
+	if schedule.DurationMinutes == 0 {
+		schedule.DurationMinutes = classType.DurationMinutes
+	}
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	if schedule.RoomID != nil {
+		room, err := s.branchRoom(ctx, schedule.BranchID, *schedule.RoomID)
+		if err != nil {
+			return err
+		}
+		if schedule.Capacity > room.Capacity {
+			return classdomain.ErrCapacityExceeded
+		}
+	}
+
+	loc, err := s.branchLocation(ctx, schedule.BranchID)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Schedules.Create(ctx, schedule); err != nil {
+		return err
+	}
+	schedule.ClassType = *classType
+
+	now := time.Now()
+	_, err = s.store.Sessions.CreateMany(ctx, schedule.Occurrences(now, now.Add(s.store.Config.Classes.ScheduleHorizon), loc))
+	return err
+}
+
+func (s *ClassService) ListSchedules(ctx context.Context, branchID uuid.UUID) ([]classdomain.ClassSchedules, error) {
+	return s.store.Schedules.List(ctx, &branchID, false)
+}
+
+// DeactivateSchedule stops materializing new sessions. Sessions already created are kept.
+func (s *ClassService) DeactivateSchedule(ctx context.Context, branchID uuid.UUID, scheduleID uuid.UUID) error {
+	schedule, err := s.store.Schedules.GetByID(ctx, scheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule.BranchID != branchID {
+		return shared_errors.ErrNotFound
+	}
+	return s.store.Schedules.Deactivate(ctx, scheduleID)
+}
+
+// GenerateSessions materializes the sessions of every active schedule up to the schedule horizon.
+// It is idempotent and returns how many sessions were created.
+func (s *ClassService) GenerateSessions(ctx context.Context) (int, error) {
+	schedules, err := s.store.Schedules.List(ctx, nil, true)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	until := now.Add(s.store.Config.Classes.ScheduleHorizon)
+	locations := make(map[uuid.UUID]*time.Location)
+	created := 0
+	for i := range schedules {
+		loc, ok := locations[schedules[i].BranchID]
+		if !ok {
+			if loc, err = s.branchLocation(ctx, schedules[i].BranchID); err != nil {
+				return created, err
+			}
+			locations[schedules[i].BranchID] = loc
+		}
+
+		n, err := s.store.Sessions.CreateMany(ctx, schedules[i].Occurrences(now, until, loc))
+		if err != nil {
+			return created, err
+		}
+		created += n
+	}
+	return created, nil
+}
+
+func (s *ClassService) GetSession(ctx context.Context, sessionID uuid.UUID) (*classdomain.ClassSessions, error) {
+	return s.store.Sessions.GetByID(ctx, sessionID)
+}
+
+func (s *ClassService) ListSessions(ctx context.Context, filter classdomain.SessionFilter) ([]classdomain.ClassSessions, error) {
+	return s.store.Sessions.List(ctx, filter)
+}
+
+// AssignInstructor replaces the instructor of a single session, a nil instructor leaves it unassigned
+func (s *ClassService) AssignInstructor(ctx context.Context, branchID uuid.UUID, sessionID uuid.UUID, instructorID *uuid.UUID) error {
+	if _, err := s.branchSession(ctx, branchID, sessionID); err != nil {
+		return err
+	}
+	return s.store.Sessions.SetInstructor(ctx, sessionID, instructorID)
+}
+
+func (s *ClassService) CancelSession(ctx context.Context, branchID uuid.UUID, sessionID uuid.UUID) error {
+	if _, err := s.branchSession(ctx, branchID, sessionID); err != nil {
+		return err
+	}
+	return s.store.Sessions.Cancel(ctx, sessionID)
+}
+
+// Book reserves a spot for the client, or joins the waitlist when the session is full.
+// The client needs a subscription that is usable at the branch on the day of the class.
+func (s *ClassService) Book(ctx context.Context, user *authdomain.Users, sessionID uuid.UUID) (*classdomain.ClassBookings, error) {
+	if user.Role.Name != "client" {
+		return nil, classdomain.ErrNotClient
+	}
+	if user.IsBlocked() {
+		return nil, shared_errors.ErrClientBlocked
+	}
+
+	session, err := s.store.Sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsBookable(time.Now()) {
+		return nil, classdomain.ErrSessionUnavailable
+	}
+
+	subscription, err := s.store.Subscriptions.GetCurrentByUser(ctx, user.UserID)
+	if err != nil {
+		if errors.Is(err, shared_errors.ErrNotFound) {
+			return nil, classdomain.ErrNoActiveSubscription
+		}
+		return nil, err
+	}
+	if subscription.RefreshStatus(time.Now()) {
+		if err := s.store.Subscriptions.Update(ctx, subscription); err != nil {
+			return nil, err
+		}
+	}
+	if !subscription.IsUsable(session.StartsAt) || !subscription.Plan.AvailableAt(session.BranchID) {
+		return nil, classdomain.ErrNoActiveSubscription
+	}
+
+	booking := &classdomain.ClassBookings{
+		SessionID: sessionID,
+		UserID:    user.UserID,
+	}
+	if err := s.store.Bookings.Book(ctx, booking); err != nil {
+		return nil, err
+	}
+	booking.Session = session
+	return booking, nil
+}
+
+// CancelBooking releases the client booking, promoting the first client of the waitlist
+func (s *ClassService) CancelBooking(ctx context.Context, user *authdomain.Users, bookingID uuid.UUID) (*classdomain.ClassBookings, error) {
+	booking, err := s.store.Bookings.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != user.UserID {
+		return nil, shared_errors.ErrNotFound
+	}
+	if err := booking.CanCancel(time.Now(), s.store.Config.Classes.CancellationCutoff); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.store.Bookings.Cancel(ctx, bookingID); err != nil {
+		return nil, err
+	}
+	return s.store.Bookings.GetByID(ctx, bookingID)
+}
+
+func (s *ClassService) ListSessionBookings(ctx context.Context, branchID uuid.UUID, sessionID uuid.UUID) ([]classdomain.ClassBookings, error) {
+	if _, err := s.branchSession(ctx, branchID, sessionID); err != nil {
+		return nil, err
+	}
+	return s.store.Bookings.ListBySession(ctx, sessionID)
+}
+
+func (s *ClassService) ListUserBookings(ctx context.Context, userID uuid.UUID) ([]classdomain.ClassBookings, error) {
+	return s.store.Bookings.ListByUser(ctx, userID)
+}
+
+// branchRoom returns the room only if it belongs to the branch
+func (s *ClassService) branchRoom(ctx context.Context, branchID uuid.UUID, roomID uuid.UUID) (*classdomain.Rooms, error) {
+	room, err := s.store.Rooms.GetByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.BranchID != branchID {
+		return nil, shared_errors.ErrNotFound
+	}
+	return room, nil
+}
+
+// branchSession returns the session only if it belongs to the branch
+func (s *ClassService) branchSession(ctx context.Context, branchID uuid.UUID, sessionID uuid.UUID) (*classdomain.ClassSessions, error) {
+	session, err := s.store.Sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.BranchID != branchID {
+		return nil, shared_errors.ErrNotFound
+	}
+	return session, nil
+}
+
+func (s *ClassService) branchLocation(ctx context.Context, branchID uuid.UUID) (*time.Location, error) {
+	branch, err := s.store.Branches.GetByID(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocation(branch.Timezone)
+}
@@ -0,0 +1,270 @@
+package membershipdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SubscriptionStatusEnum string
+
+const (
+	SubscriptionActive    SubscriptionStatusEnum = "active"
+	SubscriptionFrozen    SubscriptionStatusEnum = "frozen"
+	SubscriptionExpired   SubscriptionStatusEnum = "expired"
+	SubscriptionCancelled SubscriptionStatusEnum = "cancelled"
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid subscription status transition")
+	ErrPlanUnavailable   = errors.New("membership plan is not available")
+	ErrNotClient         = errors.New("user is not a client")
+)
+
+// allowed subscription state machine transitions
+var subscriptionTransitions = map[SubscriptionStatusEnum][]SubscriptionStatusEnum{
+	SubscriptionActive:    {SubscriptionFrozen, SubscriptionExpired, SubscriptionCancelled},
+	SubscriptionFrozen:    {SubscriptionActive, SubscriptionCancelled},
+	SubscriptionExpired:   {SubscriptionActive},
+	SubscriptionCancelled: {},
+}
+
+// CanTransitionTo reports whether the state machine allows moving to next
+func (s SubscriptionStatusEnum) CanTransitionTo(next SubscriptionStatusEnum) bool {
+	for _, allowed := range subscriptionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type MembershipPlans struct {
+	PlanID       uuid.UUID                `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"plan_id"`
+	Name         string                   `gorm:"type:varchar(100);unique;not null" json:"name"`
+	Description  string                   `gorm:"type:text" json:"description"`
+	Price        float64                  `gorm:"type:numeric(10,2);not null" json:"price"`
+	DurationDays int                      `gorm:"type:integer;not null" json:"duration_days"`
+	VisitLimit   *int                     `gorm:"type:integer" json:"visit_limit"`
+	AllBranches  bool                     `gorm:"not null;default:true" json:"all_branches"`
+	IsActive     bool                     `gorm:"not null;default:true" json:"is_active"`
+	Branches     []MembershipPlanBranches `gorm:"foreignKey:PlanID;references:PlanID" json:"branches"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt           `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+type MembershipPlanBranches struct {
+	PlanID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	BranchID uuid.UUID `gorm:"type:uuid;primaryKey" json:"branch_id"`
+}
+
+// AvailableAt reports whether the plan can be used at the branch
+func (p *MembershipPlans) AvailableAt(branchID uuid.UUID) bool {
+	if p.AllBranches {
+		return true
+	}
+	for _, b := range p.Branches {
+		if b.BranchID == branchID {
+			return true
+		}
+	}
+	return false
+}
+
+type ClientSubscriptions struct {
+	SubscriptionID uuid.UUID              `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"subscription_id"`
+	UserID         uuid.UUID              `gorm:"type:uuid;not null" json:"user_id"`
+	PlanID         uuid.UUID              `gorm:"type:uuid;not null" json:"plan_id"`
+	Plan           MembershipPlans        `gorm:"foreignKey:PlanID;references:PlanID" json:"plan"`
+	Status         SubscriptionStatusEnum `gorm:"type:subscription_status;not null;default:'active'" json:"status"`
+	StartDate      time.Time              `gorm:"type:date;not null" json:"start_date"`
+	EndDate        time.Time              `gorm:"type:date;not null" json:"end_date"`
+	PeriodEndDate  time.Time              `gorm:"type:date;not null" json:"period_end_date"` // last day of the plan period VisitsUsed counts
+	VisitsUsed     int                    `gorm:"type:integer;not null;default:0" json:"visits_used"`
+	FrozenAt       *time.Time             `json:"frozen_at,omitempty"`
+	CancelledAt    *time.Time             `json:"cancelled_at,omitempty"`
+	CancelReason   string                 `gorm:"type:text" json:"cancel_reason,omitempty"`
+	CreatedBy      *uuid.UUID             `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+
+	visitsReset bool // a new period started, VisitsUsed has to be written back
+}
+
+// NewSubscription starts a subscription to the plan on the given day
+func NewSubscription(userID uuid.UUID, plan *MembershipPlans, start time.Time) *ClientSubscriptions {
+	start = truncateDay(start)
+	end := start.AddDate(0, 0, plan.DurationDays-1)
+	return &ClientSubscriptions{
+		UserID:        userID,
+		PlanID:        plan.PlanID,
+		Plan:          *plan,
+		Status:        SubscriptionActive,
+		StartDate:     start,
+		EndDate:       end,
+		PeriodEndDate: end,
+	}
+}
+
+func (s *ClientSubscriptions) transition(next SubscriptionStatusEnum) error {
+	if !s.Status.CanTransitionTo(next) {
+		return ErrInvalidTransition
+	}
+	s.Status = next
+	return nil
+}
+
+// RefreshStatus expires an active subscription whose last day has passed, and starts counting
+// visits again when a renewed period has begun. It returns true when the subscription changed.
+func (s *ClientSubscriptions) RefreshStatus(now time.Time) bool {
+	if s.Status != SubscriptionActive {
+		return false
+	}
+	day := truncateDay(now)
+	if day.After(truncateDay(s.EndDate)) {
+		s.Status = SubscriptionExpired
+		return true
+	}
+	if !day.After(truncateDay(s.PeriodEndDate)) {
+		return false
+	}
+	for day.After(truncateDay(s.PeriodEndDate)) {
+		s.PeriodEndDate = truncateDay(s.PeriodEndDate).AddDate(0, 0, s.Plan.DurationDays)
+	}
+	if s.PeriodEndDate.After(s.EndDate) {
+		s.PeriodEndDate = truncateDay(s.EndDate)
+	}
+	s.VisitsUsed = 0
+	s.visitsReset = true
+	return true
+}
+
+// VisitsReset reports whether a transition started a new period and cleared the visit count
+func (s *ClientSubscriptions) VisitsReset() bool {
+	return s.visitsReset
+}
+
+// HasVisitsLeft reports whether the plan visit limit has not been reached
+func (s *ClientSubscriptions) HasVisitsLeft() bool {
+	return s.Plan.VisitLimit == nil || s.VisitsUsed < *s.Plan.VisitLimit
+}
+
+// IsUsable reports whether the subscription grants access on the given day
+func (s *ClientSubscriptions) IsUsable(now time.Time) bool {
+	day := truncateDay(now)
+	return s.Status == SubscriptionActive &&
+		!day.Before(truncateDay(s.StartDate)) &&
+		!day.After(truncateDay(s.EndDate)) &&
+		s.HasVisitsLeft()
+}
+
+func (s *ClientSubscriptions) Freeze(now time.Time) error {
+	if err := s.transition(SubscriptionFrozen); err != nil {
+		return err
+	}
+	s.FrozenAt = &now
+	return nil
+}
+
+// Unfreeze reactivates the subscription and extends it by the days it was frozen
+func (s *ClientSubscriptions) Unfreeze(now time.Time) error {
+	if err := s.transition(SubscriptionActive); err != nil {
+		return err
+	}
+	if s.FrozenAt != nil {
+		frozenDays := int(truncateDay(now).Sub(truncateDay(*s.FrozenAt)).Hours() / 24)
+		s.EndDate = s.EndDate.AddDate(0, 0, frozenDays)
+		s.PeriodEndDate = s.PeriodEndDate.AddDate(0, 0, frozenDays)
+	}
+	s.FrozenAt = nil
+	return nil
+}
+
+// Renew extends an active subscription by one plan period, or restarts an expired one today.
+// The visits of an active subscription keep counting until the added period starts.
+func (s *ClientSubscriptions) Renew(now time.Time) error {
+	switch s.Status {
+	case SubscriptionActive:
+		s.EndDate = s.EndDate.AddDate(0, 0, s.Plan.DurationDays)
+	case SubscriptionExpired:
+		if err := s.transition(SubscriptionActive); err != nil {
+			return err
+		}
+		s.StartDate = truncateDay(now)
+		s.EndDate = s.StartDate.AddDate(0, 0, s.Plan.DurationDays-1)
+		s.PeriodEndDate = s.EndDate
+		s.VisitsUsed = 0
+		s.visitsReset = true
+	default:
+		return ErrInvalidTransition
+	}
+	return nil
+}
+
+func (s *ClientSubscriptions) Cancel(now time.Time, reason string) error {
+	if err := s.transition(SubscriptionCancelled); err != nil {
+		return err
+	}
+	s.CancelledAt = &now
+	s.CancelReason = reason
+	s.FrozenAt = nil
+	return nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+type PlanFilter struct {
+	BranchID   *uuid.UUID
+	OnlyActive bool
+}
+
+type PlanPayload struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	Description  string   `json:"description"`
+	Price        float64  `json:"price" binding:"min=0"`
+	DurationDays int      `json:"duration_days" binding:"required,min=1"`
+	VisitLimit   *int     `json:"visit_limit" binding:"omitempty,min=1"`
+	IsActive     *bool    `json:"is_active"`
+	BranchIDs    []string `json:"branch_ids" binding:"omitempty,dive,uuid"`
+}
+
+type SubscribePayload struct {
+	UserID    string `json:"user_id" binding:"required,uuid"`
+	PlanID    string `json:"plan_id" binding:"required,uuid"`
+	StartDate string `json:"start_date"`
+}
+
+type CancelSubscriptionPayload struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// NewPlanFromPayload maps the request payload into a plan model.
+// A plan without branches is available at every branch.
+func NewPlanFromPayload(payload PlanPayload) (*MembershipPlans, error) {
+	plan := &MembershipPlans{
+		Name:         payload.Name,
+		Description:  payload.Description,
+		Price:        payload.Price,
+		DurationDays: payload.DurationDays,
+		VisitLimit:   payload.VisitLimit,
+		AllBranches:  len(payload.BranchIDs) == 0,
+		IsActive:     true,
+	}
+	if payload.IsActive != nil {
+		plan.IsActive = *payload.IsActive
+	}
+	for _, id := range payload.BranchIDs {
+		branchID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		plan.Branches = append(plan.Branches, MembershipPlanBranches{BranchID: branchID})
+	}
+	return plan, nil
+}
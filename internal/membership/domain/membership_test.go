@@ -0,0 +1,84 @@
+package membershipdomain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRenewKeepsVisitsUntilTheNextPeriod(t *testing.T) {
+	limit := 8
+	plan := &membershipdomain.MembershipPlans{DurationDays: 30, VisitLimit: &limit}
+	sub := membershipdomain.NewSubscription(uuid.New(), plan, date(time.March, 1))
+	sub.VisitsUsed = 8
+
+	if err := sub.Renew(date(time.March, 20)); err != nil {
+		t.Fatalf("Renew: %v", err)
+	}
+	if !sub.EndDate.Equal(date(time.April, 29)) {
+		t.Errorf("end date = %s, want 2026-04-29", sub.EndDate)
+	}
+	if sub.VisitsUsed != 8 || sub.VisitsReset() || sub.IsUsable(date(time.March, 20)) {
+		t.Errorf("visits used = %d, want the current period still exhausted", sub.VisitsUsed)
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		changed    bool
+		visits     int
+		periodEnd  time.Time
+		wantStatus membershipdomain.SubscriptionStatusEnum
+	}{
+		{name: "last day of the period", now: date(time.March, 30), visits: 8, periodEnd: date(time.March, 30), wantStatus: membershipdomain.SubscriptionActive},
+		{name: "renewed period starts", now: date(time.March, 31), changed: true, periodEnd: date(time.April, 29), wantStatus: membershipdomain.SubscriptionActive},
+		{name: "after the renewal", now: date(time.April, 30), changed: true, visits: 8, periodEnd: date(time.March, 30), wantStatus: membershipdomain.SubscriptionExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := *sub
+			changed := got.RefreshStatus(tt.now)
+			if changed != tt.changed || got.VisitsUsed != tt.visits || got.VisitsReset() != (tt.changed && tt.visits == 0) || !got.PeriodEndDate.Equal(tt.periodEnd) || got.Status != tt.wantStatus {
+				t.Errorf("RefreshStatus = %v, visits %d, period end %s, %s, want %v, %d, %s, %s",
+					changed, got.VisitsUsed, got.PeriodEndDate.Format(time.DateOnly), got.Status,
+					tt.changed, tt.visits, tt.periodEnd.Format(time.DateOnly), tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRenewRestartsAnExpiredSubscription(t *testing.T) {
+	plan := &membershipdomain.MembershipPlans{DurationDays: 30}
+	sub := membershipdomain.NewSubscription(uuid.New(), plan, date(time.January, 1))
+	sub.VisitsUsed = 12
+	if !sub.RefreshStatus(date(time.February, 15)) || sub.Status != membershipdomain.SubscriptionExpired {
+		t.Fatalf("status = %s, want expired", sub.Status)
+	}
+
+	if err := sub.Renew(date(time.February, 15)); err != nil {
+		t.Fatalf("Renew: %v", err)
+	}
+	if sub.VisitsUsed != 0 || !sub.VisitsReset() || !sub.StartDate.Equal(date(time.February, 15)) || !sub.PeriodEndDate.Equal(date(time.March, 16)) {
+		t.Errorf("visits used %d from %s to %s, want 0 from 2026-02-15 to 2026-03-16", sub.VisitsUsed, sub.StartDate, sub.PeriodEndDate)
+	}
+}
+
+func TestUnfreezeMovesThePeriod(t *testing.T) {
+	plan := &membershipdomain.MembershipPlans{DurationDays: 30}
+	sub := membershipdomain.NewSubscription(uuid.New(), plan, date(time.March, 1))
+	if err := sub.Freeze(date(time.March, 10)); err != nil {
+		t.Fatalf("Freeze: %v", err)
+	}
+	if err := sub.Unfreeze(date(time.March, 15)); err != nil {
+		t.Fatalf("Unfreeze: %v", err)
+	}
+	if !sub.EndDate.Equal(date(time.April, 4)) || !sub.PeriodEndDate.Equal(date(time.April, 4)) {
+		t.Errorf("end %s, period end %s, want both moved to 2026-04-04", sub.EndDate, sub.PeriodEndDate)
+	}
+}
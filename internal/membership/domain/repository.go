@@ -0,0 +1,23 @@
+package membershipdomain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type PlanRepository interface {
+	Create(ctx context.Context, plan *MembershipPlans) error
+	GetByID(ctx context.Context, planID uuid.UUID) (*MembershipPlans, error)
+	List(ctx context.Context, filter PlanFilter) ([]MembershipPlans, error)
+	Update(ctx context.Context, plan *MembershipPlans) error
+	Delete(ctx context.Context, planID uuid.UUID) error
+}
+
+type SubscriptionRepository interface {
+	Create(ctx context.Context, subscription *ClientSubscriptions) error
+	GetByID(ctx context.Context, subscriptionID uuid.UUID) (*ClientSubscriptions, error)
+	GetCurrentByUser(ctx context.Context, userID uuid.UUID) (*ClientSubscriptions, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]ClientSubscriptions, error)
+	Update(ctx context.Context, subscription *ClientSubscriptions) error
+}
@@ -0,0 +1,24 @@
+package membershipdomain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MembershipServicesInterface interface {
+	CreatePlan(ctx context.Context, plan *MembershipPlans) error
+	GetPlan(ctx context.Context, planID uuid.UUID) (*MembershipPlans, error)
+	ListPlans(ctx context.Context, filter PlanFilter) ([]MembershipPlans, error)
+	UpdatePlan(ctx context.Context, plan *MembershipPlans) error
+	DeletePlan(ctx context.Context, planID uuid.UUID) error
+	Subscribe(ctx context.Context, userID uuid.UUID, planID uuid.UUID, start time.Time, createdBy uuid.UUID) (*ClientSubscriptions, error)
+	GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*ClientSubscriptions, error)
+	GetCurrentSubscription(ctx context.Context, userID uuid.UUID) (*ClientSubscriptions, error)
+	ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]ClientSubscriptions, error)
+	Freeze(ctx context.Context, subscriptionID uuid.UUID) (*ClientSubscriptions, error)
+	Unfreeze(ctx context.Context, subscriptionID uuid.UUID) (*ClientSubscriptions, error)
+	Renew(ctx context.Context, subscriptionID uuid.UUID) (*ClientSubscriptions, error)
+	Cancel(ctx context.Context, subscriptionID uuid.UUID, reason string) (*ClientSubscriptions, error)
+}
@@ -0,0 +1,415 @@
+package membershiphandlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	appservices "github.com/vitalfit/api/internal/app/services"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+type MembershipHandlersInterface interface {
+	MembershipRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+}
+
+type MembershipHandlers struct {
+	services appservices.Services
+}
+
+func NewMembershipHandlers(services appservices.Services) *MembershipHandlers {
+	return &MembershipHandlers{services: services}
+}
+
+// @Summary		List membership plans
+// @Description	Returns the active plan catalog, optionally only the plans available at a branch.
+// @Tags			Membership
+// @Produce		json
+// @Param			branch_id	query		string					false	"Branch ID"
+// @Success		200			{object}	map[string]interface{}	"plans"
+// @Failure		400			{object}	map[string]interface{}	"invalid branch id"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/membership/plans [get]
+func (h *MembershipHandlers) listPlansHandler(c *gin.Context) {
+	filter := membershipdomain.PlanFilter{OnlyActive: true}
+	if branch := c.Query("branch_id"); branch != "" {
+		branchID, err := uuid.Parse(branch)
+		if err != nil {
+			h.services.LogErrors.BadRequestResponse(c, err)
+			return
+		}
+		filter.BranchID = &branchID
+	}
+
+	plans, err := h.services.MembershipServices.ListPlans(c.Request.Context(), filter)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"plans": plans,
+	})
+}
+
+// @Summary		Get membership plan
+// @Description	Returns a single plan of the catalog.
+// @Tags			Membership
+// @Produce		json
+// @Param			plan_id	path		string					true	"Plan ID"
+// @Success		200		{object}	map[string]interface{}	"plan"
+// @Failure		400		{object}	map[string]interface{}	"invalid plan id"
+// @Failure		404		{object}	map[string]interface{}	"plan not found"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/membership/plans/{plan_id} [get]
+func (h *MembershipHandlers) getPlanHandler(c *gin.Context) {
+	planID, err := uuid.Parse(c.Param("plan_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	plan, err := h.services.MembershipServices.GetPlan(c.Request.Context(), planID)
+	if err != nil {
+		h.membershipErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// @Summary		Create membership plan
+// @Description	Adds a plan to the catalog. A plan without branch_ids is available at every branch; visit_limit null means unlimited visits.
+// @Tags			Membership
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			plan	body		membershipdomain.PlanPayload	true	"Plan data"
+// @Success		201		{object}	map[string]interface{}			"plan created"
+// @Failure		400		{object}	map[string]interface{}			"bad request"
+// @Failure		403		{object}	map[string]interface{}			"forbidden"
+// @Failure		409		{object}	map[string]interface{}			"plan name already exists"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/membership/plans [post]
+func (h *MembershipHandlers) createPlanHandler(c *gin.Context) {
+	var payload membershipdomain.PlanPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	plan, err := membershipdomain.NewPlanFromPayload(payload)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.MembershipServices.CreatePlan(c.Request.Context(), plan); err != nil {
+		switch err {
+		case shared_errors.ErrNotFound:
+			h.services.LogErrors.BadRequestResponse(c, errors.New("branch not found"))
+		default:
+			h.membershipErrorResponse(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, plan)
+}
+
+// @Summary		Update membership plan
+// @Description	Replaces the data of a plan. Existing subscriptions keep their dates until renewed.
+// @Tags			Membership
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			plan_id	path		string							true	"Plan ID"
+// @Param			plan	body		membershipdomain.PlanPayload	true	"Plan data"
+// @Success		200		{object}	map[string]interface{}			"plan updated"
+// @Failure		400		{object}	map[string]interface{}			"bad request"
+// @Failure		403		{object}	map[string]interface{}			"forbidden"
+// @Failure		404		{object}	map[string]interface{}			"plan or branch not found"
+// @Failure		409		{object}	map[string]interface{}			"plan name already exists"
+// @Failure		500		{object}	map[string]interface{}			"internal server error"
+// @Router			/membership/plans/{plan_id} [put]
+func (h *MembershipHandlers) updatePlanHandler(c *gin.Context) {
+	var payload membershipdomain.PlanPayload
+	planID, err := uuid.Parse(c.Param("plan_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	plan, err := membershipdomain.NewPlanFromPayload(payload)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	plan.PlanID = planID
+
+	if err := h.services.MembershipServices.UpdatePlan(c.Request.Context(), plan); err != nil {
+		h.membershipErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// @Summary		Delete membership plan
+// @Description	Removes a plan from the catalog. Existing subscriptions are kept.
+// @Tags			Membership
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			plan_id	path	string	true	"Plan ID"
+// @Success		204		"Plan deleted. No content returned."
+// @Failure		400		{object}	map[string]interface{}	"invalid plan id"
+// @Failure		403		{object}	map[string]interface{}	"forbidden"
+// @Failure		404		{object}	map[string]interface{}	"plan not found"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/membership/plans/{plan_id} [delete]
+func (h *MembershipHandlers) deletePlanHandler(c *gin.Context) {
+	planID, err := uuid.Parse(c.Param("plan_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	if err := h.services.MembershipServices.DeletePlan(c.Request.Context(), planID); err != nil {
+		h.membershipErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Summary		Subscribe client to plan
+// @Description	Sells a membership plan to a client. start_date (YYYY-MM-DD) defaults to today. A client can only hold one active or frozen subscription.
+// @Tags			Subscriptions
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			payload	body		membershipdomain.SubscribePayload	true	"Subscription data"
+// @Success		201		{object}	map[string]interface{}				"subscription created"
+// @Failure		400		{object}	map[string]interface{}				"bad request, user is not a client or plan unavailable"
+// @Failure		403		{object}	map[string]interface{}				"forbidden"
+// @Failure		404		{object}	map[string]interface{}				"user or plan not found"
+// @Failure		409		{object}	map[string]interface{}				"client already has an open subscription"
+// @Failure		500		{object}	map[string]interface{}				"internal server error"
+// @Router			/subscriptions [post]
+func (h *MembershipHandlers) subscribeHandler(c *gin.Context) {
+	var payload membershipdomain.SubscribePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	planID, err := uuid.Parse(payload.PlanID)
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	start := time.Now()
+	if payload.StartDate != "" {
+		start, err = time.Parse("2006-01-02", payload.StartDate)
+		if err != nil {
+			h.services.LogErrors.BadRequestResponse(c, err)
+			return
+		}
+	}
+
+	staff := h.services.UserServices.GetUserFromContext(c)
+	subscription, err := h.services.MembershipServices.Subscribe(c.Request.Context(), userID, planID, start, staff.UserID)
+	if err != nil {
+		h.membershipErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// @Summary		List client subscriptions
+// @Description	Returns every subscription of a client, newest first.
+// @Tags			Subscriptions
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			user_id	query		string					true	"Client user ID"
+// @Success		200		{object}	map[string]interface{}	"subscriptions"
+// @Failure		400		{object}	map[string]interface{}	"invalid user id"
+// @Failure		403		{object}	map[string]interface{}	"forbidden"
+// @Failure		500		{object}	map[string]interface{}	"internal server error"
+// @Router			/subscriptions [get]
+func (h *MembershipHandlers) listSubscriptionsHandler(c *gin.Context) {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	h.respondSubscriptions(c, userID)
+}
+
+// @Summary		List my subscriptions
+// @Description	Returns the subscriptions of the authenticated client.
+// @Tags			User
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"subscriptions"
+// @Failure		401	{object}	map[string]interface{}	"unauthorized"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/user/subscriptions [get]
+func (h *MembershipHandlers) mySubscriptionsHandler(c *gin.Context) {
+	user := h.services.UserServices.GetUserFromContext(c)
+	h.respondSubscriptions(c, user.UserID)
+}
+
+// @Summary		Get subscription
+// @Description	Returns a subscription with its plan, expiring it first if its period is over.
+// @Tags			Subscriptions
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			subscription_id	path		string					true	"Subscription ID"
+// @Success		200				{object}	map[string]interface{}	"subscription"
+// @Failure		400				{object}	map[string]interface{}	"invalid subscription id"
+// @Failure		403				{object}	map[string]interface{}	"forbidden"
+// @Failure		404				{object}	map[string]interface{}	"subscription not found"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/subscriptions/{subscription_id} [get]
+func (h *MembershipHandlers) getSubscriptionHandler(c *gin.Context) {
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	subscription, err := h.services.MembershipServices.GetSubscription(c.Request.Context(), subscriptionID)
+	if err != nil {
+		h.membershipErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// @Summary		Freeze subscription
+// @Description	Pauses an active subscription. The frozen days are added to the end date when it is unfrozen.
+// @Tags			Subscriptions
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			subscription_id	path		string					true	"Subscription ID"
+// @Success		200				{object}	map[string]interface{}	"subscription"
+// @Failure		400				{object}	map[string]interface{}	"invalid id"
+// @Failure		404				{object}	map[string]interface{}	"subscription not found"
+// @Failure		409				{object}	map[string]interface{}	"invalid status transition"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/subscriptions/{subscription_id}/freeze [post]
+func (h *MembershipHandlers) freezeHandler(c *gin.Context) {
+	h.transition(c, h.services.MembershipServices.Freeze)
+}
+
+// @Summary		Unfreeze subscription
+// @Description	Reactivates a frozen subscription and extends its end date by the frozen days.
+// @Tags			Subscriptions
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			subscription_id	path		string					true	"Subscription ID"
+// @Success		200				{object}	map[string]interface{}	"subscription"
+// @Failure		400				{object}	map[string]interface{}	"invalid id"
+// @Failure		404				{object}	map[string]interface{}	"subscription not found"
+// @Failure		409				{object}	map[string]interface{}	"invalid status transition"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/subscriptions/{subscription_id}/unfreeze [post]
+func (h *MembershipHandlers) unfreezeHandler(c *gin.Context) {
+	h.transition(c, h.services.MembershipServices.Unfreeze)
+}
+
+// @Summary		Renew subscription
+// @Description	Extends an active subscription by one plan period, or restarts an expired one from today. The visits used keep counting until the added period starts.
+// @Tags			Subscriptions
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			subscription_id	path		string					true	"Subscription ID"
+// @Success		200				{object}	map[string]interface{}	"subscription"
+// @Failure		400				{object}	map[string]interface{}	"invalid id or plan unavailable"
+// @Failure		404				{object}	map[string]interface{}	"subscription not found"
+// @Failure		409				{object}	map[string]interface{}	"invalid status transition"
+// @Failure		500				{object}	map[string]interface{}	"internal server error"
+// @Router			/subscriptions/{subscription_id}/renew [post]
+func (h *MembershipHandlers) renewHandler(c *gin.Context) {
+	h.transition(c, h.services.MembershipServices.Renew)
+}
+
+// @Summary		Cancel subscription
+// @Description	Cancels an active or frozen subscription. Cancelled subscriptions cannot be reactivated.
+// @Tags			Subscriptions
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			subscription_id	path		string										true	"Subscription ID"
+// @Param			payload			body		membershipdomain.CancelSubscriptionPayload	true	"Cancellation reason"
+// @Success		200				{object}	map[string]interface{}						"subscription"
+// @Failure		400				{object}	map[string]interface{}						"bad request"
+// @Failure		404				{object}	map[string]interface{}						"subscription not found"
+// @Failure		409				{object}	map[string]interface{}						"invalid status transition"
+// @Failure		500				{object}	map[string]interface{}						"internal server error"
+// @Router			/subscriptions/{subscription_id}/cancel [post]
+func (h *MembershipHandlers) cancelHandler(c *gin.Context) {
+	var payload membershipdomain.CancelSubscriptionPayload
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	subscription, err := h.services.MembershipServices.Cancel(c.Request.Context(), subscriptionID, payload.Reason)
+	if err != nil {
+		h.membershipErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+func (h *MembershipHandlers) transition(c *gin.Context, fn func(ctx context.Context, subscriptionID uuid.UUID) (*membershipdomain.ClientSubscriptions, error)) {
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	subscription, err := fn(c.Request.Context(), subscriptionID)
+	if err != nil {
+		h.membershipErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+func (h *MembershipHandlers) respondSubscriptions(c *gin.Context, userID uuid.UUID) {
+	subscriptions, err := h.services.MembershipServices.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"subscriptions": subscriptions,
+	})
+}
+
+func (h *MembershipHandlers) membershipErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shared_errors.ErrNotFound):
+		h.services.LogErrors.NotFoundResponse(c)
+	case errors.Is(err, shared_errors.ErrConflict), errors.Is(err, membershipdomain.ErrInvalidTransition):
+		h.services.LogErrors.ConflictResponse(c, err)
+	case errors.Is(err, membershipdomain.ErrNotClient), errors.Is(err, membershipdomain.ErrPlanUnavailable):
+		h.services.LogErrors.BadRequestResponse(c, err)
+	default:
+		h.services.LogErrors.InternalServerError(c, err)
+	}
+}
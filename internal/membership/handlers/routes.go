@@ -0,0 +1,38 @@
+package membershiphandlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+func (r *MembershipHandlers) MembershipRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+
+	planGroup := rg.Group("/membership/plans")
+	{ //public routes
+		planGroup.GET("", r.listPlansHandler)
+		planGroup.GET("/:plan_id", r.getPlanHandler)
+
+		protectedGroup := planGroup.Group("").Use(m.AuthJwtTokenMiddleware(), m.RequirePermission("plans:manage"))
+		{
+			protectedGroup.POST("", r.createPlanHandler)
+			protectedGroup.PUT("/:plan_id", r.updatePlanHandler)
+			protectedGroup.DELETE("/:plan_id", r.deletePlanHandler)
+		}
+	}
+
+	subscriptionGroup := rg.Group("/subscriptions").Use(m.AuthJwtTokenMiddleware())
+	{ //front desk routes
+		subscriptionGroup.POST("", m.RequirePermission("billing:write"), r.subscribeHandler)
+		subscriptionGroup.GET("", m.RequirePermission("billing:read"), r.listSubscriptionsHandler)
+		subscriptionGroup.GET("/:subscription_id", m.RequirePermission("billing:read"), r.getSubscriptionHandler)
+		subscriptionGroup.POST("/:subscription_id/freeze", m.RequirePermission("billing:write"), r.freezeHandler)
+		subscriptionGroup.POST("/:subscription_id/unfreeze", m.RequirePermission("billing:write"), r.unfreezeHandler)
+		subscriptionGroup.POST("/:subscription_id/renew", m.RequirePermission("billing:write"), r.renewHandler)
+		subscriptionGroup.POST("/:subscription_id/cancel", m.RequirePermission("billing:write"), r.cancelHandler)
+	}
+
+	userGroup := rg.Group("/user").Use(m.AuthJwtTokenMiddleware())
+	{ //private routes
+		userGroup.GET("/subscriptions", r.mySubscriptionsHandler)
+	}
+}
@@ -0,0 +1,128 @@
+package membershiprepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+)
+
+type PlanStore struct {
+	db *gorm.DB
+}
+
+func NewPlanStore(db *gorm.DB) *PlanStore {
+	return &PlanStore{db: db}
+}
+
+func (s *PlanStore) Create(ctx context.Context, plan *membershipdomain.MembershipPlans) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(plan).Error; err != nil {
+		return mapMembershipError(err)
+	}
+	return nil
+}
+
+func (s *PlanStore) GetByID(ctx context.Context, planID uuid.UUID) (*membershipdomain.MembershipPlans, error) {
+	var plan membershipdomain.MembershipPlans
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).
+		Preload("Branches").
+		Where("plan_id = ?", planID).
+		First(&plan).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (s *PlanStore) List(ctx context.Context, filter membershipdomain.PlanFilter) ([]membershipdomain.MembershipPlans, error) {
+	var plans []membershipdomain.MembershipPlans
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Preload("Branches").Order("price, name")
+	if filter.OnlyActive {
+		query = query.Where("is_active = ?", true)
+	}
+	if filter.BranchID != nil {
+		query = query.Where(
+			"all_branches OR EXISTS (SELECT 1 FROM membership_plan_branches mpb WHERE mpb.plan_id = membership_plans.plan_id AND mpb.branch_id = ?)",
+			*filter.BranchID,
+		)
+	}
+	if err := query.Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// Update saves the plan and replaces the branches it is available at
+func (s *PlanStore) Update(ctx context.Context, plan *membershipdomain.MembershipPlans) error {
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+		defer cancel()
+
+		result := tx.WithContext(ctx).Model(plan).
+			Select("name", "description", "price", "duration_days", "visit_limit", "all_branches", "is_active").
+			Updates(plan)
+		if result.Error != nil {
+			return mapMembershipError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return shared_errors.ErrNotFound
+		}
+
+		if err := tx.WithContext(ctx).Where("plan_id = ?", plan.PlanID).Delete(&membershipdomain.MembershipPlanBranches{}).Error; err != nil {
+			return err //rollback
+		}
+		for i := range plan.Branches {
+			plan.Branches[i].PlanID = plan.PlanID
+		}
+		if len(plan.Branches) > 0 {
+			if err := tx.WithContext(ctx).Create(&plan.Branches).Error; err != nil {
+				return mapMembershipError(err) //rollback
+			}
+		}
+		return nil //commit
+	})
+}
+
+func (s *PlanStore) Delete(ctx context.Context, planID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&membershipdomain.MembershipPlans{}, planID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared_errors.ErrNotFound
+	}
+	return nil
+}
+
+func mapMembershipError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return shared_errors.ErrConflict
+		case "23503":
+			return shared_errors.ErrNotFound
+		}
+	}
+	return err
+}
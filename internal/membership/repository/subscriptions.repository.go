@@ -0,0 +1,112 @@
+package membershiprepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SubscriptionStore struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionStore(db *gorm.DB) *SubscriptionStore {
+	return &SubscriptionStore{db: db}
+}
+
+func (s *SubscriptionStore) Create(ctx context.Context, subscription *membershipdomain.ClientSubscriptions) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Omit(clause.Associations).Create(subscription).Error; err != nil {
+		return mapMembershipError(err)
+	}
+	return nil
+}
+
+func (s *SubscriptionStore) GetByID(ctx context.Context, subscriptionID uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	var subscription membershipdomain.ClientSubscriptions
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.withPlan(s.db.WithContext(ctx)).
+		Where("subscription_id = ?", subscriptionID).
+		First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// GetCurrentByUser returns the open (active or frozen) subscription of the user
+func (s *SubscriptionStore) GetCurrentByUser(ctx context.Context, userID uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	var subscription membershipdomain.ClientSubscriptions
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.withPlan(s.db.WithContext(ctx)).
+		Where("user_id = ? AND status IN ?", userID, []membershipdomain.SubscriptionStatusEnum{
+			membershipdomain.SubscriptionActive,
+			membershipdomain.SubscriptionFrozen,
+		}).
+		Order("created_at DESC").
+		First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *SubscriptionStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]membershipdomain.ClientSubscriptions, error) {
+	var subscriptions []membershipdomain.ClientSubscriptions
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.withPlan(s.db.WithContext(ctx)).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// Update writes the lifecycle columns of the subscription. visits_used is only written when a new
+// period reset it, otherwise a stale copy would undo the check-ins counted since it was read.
+func (s *SubscriptionStore) Update(ctx context.Context, subscription *membershipdomain.ClientSubscriptions) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	columns := []string{"status", "start_date", "end_date", "period_end_date", "frozen_at", "cancelled_at", "cancel_reason", "updated_at"}
+	if subscription.VisitsReset() {
+		columns = append(columns, "visits_used")
+	}
+	err := s.db.WithContext(ctx).
+		Model(subscription).
+		Select(columns).
+		Updates(subscription).Error
+	if err != nil {
+		return mapMembershipError(err)
+	}
+	return nil
+}
+
+// withPlan preloads the plan even if it was removed from the catalog afterwards
+func (s *SubscriptionStore) withPlan(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Preload("Plan", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Preload("Plan.Branches")
+}
@@ -0,0 +1,167 @@
+package membershipservices
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type MembershipService struct {
+	store store.Storage
+}
+
+func NewMembershipService(store store.Storage) *MembershipService {
+	return &MembershipService{
+		store: store,
+	}
+}
+
+func (s *MembershipService) CreatePlan(ctx context.Context, plan *membershipdomain.MembershipPlans) error {
+	return s.store.Plans.Create(ctx, plan)
+}
+
+func (s *MembershipService) GetPlan(ctx context.Context, planID uuid.UUID) (*membershipdomain.MembershipPlans, error) {
+	return s.store.Plans.GetByID(ctx, planID)
+}
+
+func (s *MembershipService) ListPlans(ctx context.Context, filter membershipdomain.PlanFilter) ([]membershipdomain.MembershipPlans, error) {
+	return s.store.Plans.List(ctx, filter)
+}
+
+func (s *MembershipService) UpdatePlan(ctx context.Context, plan *membershipdomain.MembershipPlans) error {
+	return s.store.Plans.Update(ctx, plan)
+}
+
+func (s *MembershipService) DeletePlan(ctx context.Context, planID uuid.UUID) error {
+	return s.store.Plans.Delete(ctx, planID)
+}
+
+// Subscribe sells the plan to a client starting on the given day
+func (s *MembershipService) Subscribe(ctx context.Context, userID uuid.UUID, planID uuid.UUID, start time.Time, createdBy uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	user, err := s.store.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role.Name != "client" {
+		return nil, membershipdomain.ErrNotClient
+	}
+
+	plan, err := s.store.Plans.GetByID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.IsActive {
+		return nil, membershipdomain.ErrPlanUnavailable
+	}
+
+	// an active subscription past its end date no longer blocks a new one
+	_, err = s.GetCurrentSubscription(ctx, userID)
+	switch err {
+	case nil:
+		return nil, shared_errors.ErrConflict
+	case shared_errors.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	subscription := membershipdomain.NewSubscription(userID, plan, start)
+	subscription.CreatedBy = &createdBy
+	if err := s.store.Subscriptions.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetSubscription returns the subscription, expiring it first if its period is over
+func (s *MembershipService) GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	subscription, err := s.store.Subscriptions.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetCurrentSubscription returns the open subscription of the client, if it is still open
+func (s *MembershipService) GetCurrentSubscription(ctx context.Context, userID uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	subscription, err := s.store.Subscriptions.GetCurrentByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx, subscription); err != nil {
+		return nil, err
+	}
+	if subscription.Status == membershipdomain.SubscriptionExpired {
+		return nil, shared_errors.ErrNotFound
+	}
+	return subscription, nil
+}
+
+func (s *MembershipService) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]membershipdomain.ClientSubscriptions, error) {
+	subscriptions, err := s.store.Subscriptions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		if err := s.refresh(ctx, &subscriptions[i]); err != nil {
+			return nil, err
+		}
+	}
+	return subscriptions, nil
+}
+
+func (s *MembershipService) Freeze(ctx context.Context, subscriptionID uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	return s.apply(ctx, subscriptionID, func(sub *membershipdomain.ClientSubscriptions, now time.Time) error {
+		return sub.Freeze(now)
+	})
+}
+
+func (s *MembershipService) Unfreeze(ctx context.Context, subscriptionID uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	return s.apply(ctx, subscriptionID, func(sub *membershipdomain.ClientSubscriptions, now time.Time) error {
+		return sub.Unfreeze(now)
+	})
+}
+
+func (s *MembershipService) Renew(ctx context.Context, subscriptionID uuid.UUID) (*membershipdomain.ClientSubscriptions, error) {
+	return s.apply(ctx, subscriptionID, func(sub *membershipdomain.ClientSubscriptions, now time.Time) error {
+		if !sub.Plan.IsActive || sub.Plan.DeletedAt.Valid {
+			return membershipdomain.ErrPlanUnavailable
+		}
+		return sub.Renew(now)
+	})
+}
+
+func (s *MembershipService) Cancel(ctx context.Context, subscriptionID uuid.UUID, reason string) (*membershipdomain.ClientSubscriptions, error) {
+	return s.apply(ctx, subscriptionID, func(sub *membershipdomain.ClientSubscriptions, now time.Time) error {
+		return sub.Cancel(now, reason)
+	})
+}
+
+// apply loads the subscription, runs a state machine transition and persists it
+func (s *MembershipService) apply(ctx context.Context, subscriptionID uuid.UUID, fn func(*membershipdomain.ClientSubscriptions, time.Time) error) (*membershipdomain.ClientSubscriptions, error) {
+	subscription, err := s.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(subscription, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.store.Subscriptions.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// refresh persists the expiration of subscriptions whose period is over
+func (s *MembershipService) refresh(ctx context.Context, subscription *membershipdomain.ClientSubscriptions) error {
+	if subscription.RefreshStatus(time.Now()) {
+		return s.store.Subscriptions.Update(ctx, subscription)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+//go:embed migrations/*.sql
+var FS embed.FS
+
+// lockID is the advisory lock held while migrating so replicas starting together do not race
+const lockID int64 = 7_311_452_907
+
+// NilVersion is the version of a database without any migration applied
+const NilVersion = -1
+
+// the table is the one of golang-migrate so databases migrated with its CLI keep their version
+const (
+	createTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+	versionQuery     = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+)
+
+var (
+	ErrDirty      = errors.New("database is dirty, fix the failed migration and force its version")
+	ErrNoChange   = errors.New("no change")
+	ErrNoVersion  = errors.New("no migration has been applied")
+	ErrBadVersion = errors.New("unknown migration version")
+
+	fileName = regexp.MustCompile(`^([0-9]+)_(.+)\.(up|down)\.sql$`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is one migration and whether the database has it
+type Status struct {
+	Migration
+	Applied bool
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New reads the embedded migrations
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(FS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the <version>_<name>.(up|down).sql files of dir, ordered by version
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies the pending migrations, at most n of them when n is positive, and returns the applied ones
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w (version %d)", ErrDirty, version)
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if n > 0 && len(applied) == n {
+				break
+			}
+			if err := m.apply(ctx, conn, migration.Version, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last n migrations, every one when n is not positive, and returns the reverted ones
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w (version %d)", ErrDirty, version)
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			if n > 0 && len(reverted) == n {
+				break
+			}
+			previous := int64(NilVersion)
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, migration.Version, migration.Down, previous); err != nil {
+				return fmt.Errorf("revert %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Version returns the current version, ErrNoVersion when nothing was applied
+func (m *Migrator) Version(ctx context.Context) (int64, bool, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	version, dirty, err := m.version(ctx, conn)
+	if err == nil && version == NilVersion {
+		return version, dirty, ErrNoVersion
+	}
+	return version, dirty, err
+}
+
+// Force sets the version without running anything and clears the dirty flag, it is how a
+// failed migration is marked as fixed. NilVersion leaves the database without a version.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != NilVersion && !m.has(version) {
+		return fmt.Errorf("%w: %d", ErrBadVersion, version)
+	}
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		return m.setVersion(ctx, conn, version, false)
+	})
+}
+
+// Status lists every migration and whether it is applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, int64, bool, error) {
+	version, dirty, err := m.Version(ctx)
+	if err != nil && !errors.Is(err, ErrNoVersion) {
+		return nil, version, dirty, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, Status{Migration: migration, Applied: migration.Version <= version})
+	}
+	return statuses, version, dirty, nil
+}
+
+// apply runs one migration file. The version is marked dirty first so a failure halfway is
+// visible and must be forced, like golang-migrate does.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, version int64, query string, target int64) error {
+	if err := m.setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	if query != "" {
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return m.setVersion(ctx, conn, target, false)
+}
+
+func (m *Migrator) setVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE schema_migrations`); err != nil {
+		return err
+	}
+	if version != NilVersion || dirty {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, version, dirty); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (int64, bool, error) {
+	if _, err := conn.ExecContext(ctx, createTableQuery); err != nil {
+		return 0, false, err
+	}
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, versionQuery).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NilVersion, false, nil
+	}
+	return version, dirty, err
+}
+
+// locked runs fn on a single connection holding the session advisory lock, other callers wait for it
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	// unlock with a fresh context so a cancelled run still releases the lock
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	return fn(conn)
+}
+
+func (m *Migrator) has(version int64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
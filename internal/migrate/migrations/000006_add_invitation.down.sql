@@ -1 +1 @@
-DROP TABLE IF EXISTS user_invitations
\ No newline at end of file
+DROP TABLE IF EXISTS user_invitations;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS staff_branches;
+DROP TABLE IF EXISTS branch_opening_hours;
+DROP TABLE IF EXISTS branches;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS branches (
+    branch_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    address_line VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100),
+    country VARCHAR(100) NOT NULL,
+    postal_code VARCHAR(20),
+    phone VARCHAR(50),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS branch_opening_hours (
+    branch_id UUID NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    PRIMARY KEY (branch_id, weekday)
+);
+
+CREATE TABLE IF NOT EXISTS staff_branches (
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    branch_id UUID NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, branch_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_staff_branches_branch_id ON staff_branches(branch_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS client_subscriptions;
+DROP TABLE IF EXISTS membership_plan_branches;
+DROP TABLE IF EXISTS membership_plans;
+DROP TYPE IF EXISTS subscription_status;
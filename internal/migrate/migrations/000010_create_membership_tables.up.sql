@@ -0,0 +1,50 @@
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'subscription_status') THEN
+        CREATE TYPE subscription_status AS ENUM ('active', 'frozen', 'expired', 'cancelled');
+    END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS membership_plans (
+    plan_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    price NUMERIC(10, 2) NOT NULL CHECK (price >= 0),
+    duration_days INT NOT NULL CHECK (duration_days > 0),
+    visit_limit INT CHECK (visit_limit > 0), -- NULL means unlimited visits
+    all_branches BOOLEAN NOT NULL DEFAULT TRUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS membership_plan_branches (
+    plan_id UUID NOT NULL REFERENCES membership_plans(plan_id) ON DELETE CASCADE,
+    branch_id UUID NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    PRIMARY KEY (plan_id, branch_id)
+);
+
+CREATE TABLE IF NOT EXISTS client_subscriptions (
+    subscription_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    plan_id UUID NOT NULL REFERENCES membership_plans(plan_id),
+    status subscription_status NOT NULL DEFAULT 'active',
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    visits_used INT NOT NULL DEFAULT 0,
+    frozen_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    cancel_reason TEXT,
+    created_by UUID REFERENCES users(user_id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_client_subscriptions_dates CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_subscriptions_user_id ON client_subscriptions(user_id);
+
+-- a client can hold only one open (active or frozen) subscription at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_client_subscriptions_open_user
+    ON client_subscriptions(user_id)
+    WHERE status IN ('active', 'frozen');
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS checkins (
+    checkin_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    branch_id UUID NOT NULL REFERENCES branches(branch_id),
+    subscription_id UUID REFERENCES client_subscriptions(subscription_id),
+    checked_in_by UUID REFERENCES users(user_id),
+    checked_in_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkins_user_id ON checkins(user_id, checked_in_at);
+CREATE INDEX IF NOT EXISTS idx_checkins_branch_id ON checkins(branch_id, checked_in_at);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS class_bookings;
+DROP TABLE IF EXISTS class_sessions;
+DROP TABLE IF EXISTS class_schedules;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS class_types;
+DROP TYPE IF EXISTS booking_status;
+DROP TYPE IF EXISTS class_session_status;
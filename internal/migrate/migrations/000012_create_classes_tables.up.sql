@@ -0,0 +1,94 @@
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'class_session_status') THEN
+        CREATE TYPE class_session_status AS ENUM ('scheduled', 'cancelled');
+    END IF;
+END$$;
+
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'booking_status') THEN
+        CREATE TYPE booking_status AS ENUM ('booked', 'waitlisted', 'cancelled');
+    END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS class_types (
+    class_type_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    room_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    branch_id UUID NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    capacity INT NOT NULL CHECK (capacity > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rooms_branch_name ON rooms(branch_id, name) WHERE deleted_at IS NULL;
+
+-- weekly recurring slot, start_time is in the branch timezone
+CREATE TABLE IF NOT EXISTS class_schedules (
+    schedule_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    class_type_id UUID NOT NULL REFERENCES class_types(class_type_id),
+    branch_id UUID NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    room_id UUID REFERENCES rooms(room_id),
+    instructor_id UUID REFERENCES instructors(instructor_id),
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    capacity INT NOT NULL CHECK (capacity > 0),
+    valid_from DATE NOT NULL,
+    valid_until DATE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_class_schedules_dates CHECK (valid_until IS NULL OR valid_until >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_class_schedules_branch_id ON class_schedules(branch_id);
+
+-- concrete occurrences materialized from the schedules
+CREATE TABLE IF NOT EXISTS class_sessions (
+    session_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    schedule_id UUID REFERENCES class_schedules(schedule_id) ON DELETE SET NULL,
+    class_type_id UUID NOT NULL REFERENCES class_types(class_type_id),
+    branch_id UUID NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    room_id UUID REFERENCES rooms(room_id),
+    instructor_id UUID REFERENCES instructors(instructor_id),
+    starts_at TIMESTAMP(0) WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP(0) WITH TIME ZONE NOT NULL,
+    capacity INT NOT NULL CHECK (capacity > 0),
+    status class_session_status NOT NULL DEFAULT 'scheduled',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_class_sessions_dates CHECK (ends_at > starts_at)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_class_sessions_schedule_start ON class_sessions(schedule_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_class_sessions_branch_start ON class_sessions(branch_id, starts_at);
+
+CREATE TABLE IF NOT EXISTS class_bookings (
+    booking_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES class_sessions(session_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    status booking_status NOT NULL,
+    promoted_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- a client holds at most one open booking or waitlist spot per session
+CREATE UNIQUE INDEX IF NOT EXISTS idx_class_bookings_open_user
+    ON class_bookings(session_id, user_id)
+    WHERE status IN ('booked', 'waitlisted');
+CREATE INDEX IF NOT EXISTS idx_class_bookings_user_id ON class_bookings(user_id);
@@ -0,0 +1,13 @@
+-- full text search over the fields staff use to find an account
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        to_tsvector('simple',
+            coalesce(first_name, '') || ' ' ||
+            coalesce(last_name, '') || ' ' ||
+            coalesce(email::text, '') || ' ' ||
+            coalesce(phone, '') || ' ' ||
+            coalesce(identity_document, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS client_status_history (
+    history_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    previous_status client_status NOT NULL,
+    new_status client_status NOT NULL,
+    justification TEXT NOT NULL,
+    changed_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    changed_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_status_history_user_id ON client_status_history(user_id, changed_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_client_profiles_category;
+DROP TABLE IF EXISTS client_category_history;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS client_category_history (
+    history_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    previous_category client_category NOT NULL,
+    new_category client_category NOT NULL,
+    previous_scoring INT NOT NULL,
+    new_scoring INT NOT NULL,
+    changed_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_category_history_user_id ON client_category_history(user_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_client_category_history_changed_at ON client_category_history(changed_at, new_category);
+CREATE INDEX IF NOT EXISTS idx_client_profiles_category ON client_profiles(category);
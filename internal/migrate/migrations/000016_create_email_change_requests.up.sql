@@ -0,0 +1,8 @@
+-- one pending email change per user, a new request replaces the previous one
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    user_id UUID PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    token VARCHAR(255) NOT NULL,
+    new_email CITEXT NOT NULL,
+    expiry TIMESTAMP(0) WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+ALTER TABLE email_change_requests DROP COLUMN IF EXISTS attempts;
+ALTER TABLE password_reset_token DROP COLUMN IF EXISTS attempts;
+ALTER TABLE user_invitations DROP COLUMN IF EXISTS attempts;
+DROP TABLE IF EXISTS account_lockouts;
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,31 @@
+-- failed attempts per scope (login, activate, password_reset, mfa) and identifier (the email)
+CREATE TABLE IF NOT EXISTS login_attempts (
+    scope VARCHAR(32) NOT NULL,
+    identifier CITEXT NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP(0) WITH TIME ZONE,
+
+    PRIMARY KEY (scope, identifier)
+);
+
+CREATE TABLE IF NOT EXISTS account_lockouts (
+    lockout_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    scope VARCHAR(32) NOT NULL,
+    identifier CITEXT NOT NULL,
+    user_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    failures INT NOT NULL,
+    ip_address VARCHAR(64),
+    locked_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP(0) WITH TIME ZONE NOT NULL,
+    unlocked_at TIMESTAMP(0) WITH TIME ZONE,
+    unlocked_by UUID REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_lockouts_user ON account_lockouts(user_id, locked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_account_lockouts_identifier ON account_lockouts(identifier) WHERE unlocked_at IS NULL;
+
+-- wrong guesses against each one time code, the code is discarded once they reach the limit
+ALTER TABLE user_invitations ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE password_reset_token ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE email_change_requests ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    permission_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- super_admin is not mapped, it holds every permission
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id UUID NOT NULL REFERENCES roles(role_id) ON DELETE CASCADE,
+    permission_id UUID NOT NULL REFERENCES permissions(permission_id) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    granted_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (name, description) VALUES
+('users:read', 'List and view user accounts.'),
+('users:write', 'Block, unblock and unlock user accounts and register staff.'),
+('billing:read', 'View subscriptions, payments and invoices.'),
+('billing:write', 'Record payments and manage invoices.'),
+('reports:read', 'View client scoring, categories and at-risk lists.'),
+('reports:export', 'Export reports and data sets.'),
+('scoring:run', 'Trigger a client scoring run.'),
+('roles:manage', 'Edit the permissions granted to each role.')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id
+FROM (VALUES
+    ('branch_admin', 'users:read'),
+    ('branch_admin', 'users:write'),
+    ('branch_admin', 'billing:read'),
+    ('branch_admin', 'reports:read'),
+    ('accountant', 'billing:read'),
+    ('accountant', 'billing:write'),
+    ('accountant', 'reports:export'),
+    ('data_analyst', 'reports:read'),
+    ('data_analyst', 'reports:export')
+) AS grants(role_name, permission_name)
+JOIN roles r ON r.name = grants.role_name
+JOIN permissions p ON p.name = grants.permission_name
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE name = 'outbox:manage';
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,29 @@
+-- messages written in the same transaction as the change that triggers them,
+-- the dispatcher delivers them and dead-letters those that keep failing
+CREATE TABLE IF NOT EXISTS outbox (
+    message_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idempotency_key VARCHAR(255) UNIQUE NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    recipient_name VARCHAR(255),
+    payload BYTEA,
+    user_id UUID REFERENCES users(user_id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP(0) WITH TIME ZONE,
+    dead_at TIMESTAMP(0) WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(available_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_status ON outbox(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_outbox_user ON outbox(user_id);
+
+INSERT INTO permissions (name, description) VALUES
+('outbox:manage', 'View queued and dead-lettered messages and retry them.')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,4 @@
+ALTER TABLE outbox DROP COLUMN IF EXISTS locale;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_preferred_language_check;
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_language;
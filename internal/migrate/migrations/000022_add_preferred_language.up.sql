@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_language VARCHAR(5) NOT NULL DEFAULT 'es';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_preferred_language_check;
+ALTER TABLE users ADD CONSTRAINT users_preferred_language_check CHECK (preferred_language IN ('es', 'en'));
+
+-- the locale travels with the queued email so the dispatcher renders the variant the user asked for
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS locale VARCHAR(5) NOT NULL DEFAULT 'es';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_password_reset_token_user_id;
+DROP INDEX IF EXISTS idx_user_invitations_user_id;
+ALTER TABLE password_reset_token DROP CONSTRAINT IF EXISTS fk_password_reset_token_users;
+ALTER TABLE user_invitations DROP CONSTRAINT IF EXISTS fk_user_invitations_users;
@@ -0,0 +1,14 @@
+-- codes of users deleted before the constraint existed
+DELETE FROM user_invitations i WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.user_id = i.user_id);
+DELETE FROM password_reset_token p WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.user_id = p.user_id);
+
+ALTER TABLE user_invitations DROP CONSTRAINT IF EXISTS fk_user_invitations_users;
+ALTER TABLE user_invitations ADD CONSTRAINT fk_user_invitations_users
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+
+ALTER TABLE password_reset_token DROP CONSTRAINT IF EXISTS fk_password_reset_token_users;
+ALTER TABLE password_reset_token ADD CONSTRAINT fk_password_reset_token_users
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_user_invitations_user_id ON user_invitations(user_id);
+CREATE INDEX IF NOT EXISTS idx_password_reset_token_user_id ON password_reset_token(user_id);
@@ -0,0 +1,5 @@
+-- the counters are short lived, merging them per identifier is not worth it
+DELETE FROM login_attempts;
+ALTER TABLE login_attempts DROP CONSTRAINT IF EXISTS login_attempts_pkey;
+ALTER TABLE login_attempts DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE login_attempts ADD PRIMARY KEY (scope, identifier);
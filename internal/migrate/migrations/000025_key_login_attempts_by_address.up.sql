@@ -0,0 +1,4 @@
+-- failures are counted per identifier and address so nobody can lock out an email they do not own
+ALTER TABLE login_attempts ADD COLUMN IF NOT EXISTS ip_address VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE login_attempts DROP CONSTRAINT IF EXISTS login_attempts_pkey;
+ALTER TABLE login_attempts ADD PRIMARY KEY (scope, identifier, ip_address);
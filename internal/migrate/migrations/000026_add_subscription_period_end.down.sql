@@ -0,0 +1 @@
+ALTER TABLE client_subscriptions DROP COLUMN IF EXISTS period_end_date;
@@ -0,0 +1,4 @@
+-- visits are counted per plan period, a renewal of an active subscription only adds the next one
+ALTER TABLE client_subscriptions ADD COLUMN IF NOT EXISTS period_end_date DATE;
+UPDATE client_subscriptions SET period_end_date = end_date WHERE period_end_date IS NULL;
+ALTER TABLE client_subscriptions ALTER COLUMN period_end_date SET NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scoring_runs_started_at;
+DROP TABLE IF EXISTS scoring_runs;
@@ -0,0 +1,11 @@
+-- the scheduled job skips a run when another replica finished one recently
+CREATE TABLE IF NOT EXISTS scoring_runs (
+    run_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    started_at TIMESTAMP(0) WITH TIME ZONE NOT NULL,
+    evaluated INT NOT NULL,
+    updated INT NOT NULL,
+    category_changes INT NOT NULL,
+    finished_at TIMESTAMP(0) WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scoring_runs_started_at ON scoring_runs(started_at);
@@ -0,0 +1,29 @@
+INSERT INTO permissions (name, description) VALUES
+('reports:export', 'Export reports and data sets.')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id
+FROM (VALUES
+    ('accountant', 'reports:export'),
+    ('data_analyst', 'reports:export')
+) AS grants(role_name, permission_name)
+JOIN roles r ON r.name = grants.role_name
+JOIN permissions p ON p.name = grants.permission_name
+ON CONFLICT DO NOTHING;
+
+DELETE FROM role_permissions rp
+USING roles r, permissions p, (VALUES
+    ('branch_admin', 'billing:write'),
+    ('recepcionist', 'billing:read'),
+    ('recepcionist', 'billing:write')
+) AS grants(role_name, permission_name)
+WHERE rp.role_id = r.role_id
+  AND rp.permission_id = p.permission_id
+  AND r.name = grants.role_name
+  AND p.name = grants.permission_name;
+
+DELETE FROM permissions WHERE name IN ('plans:manage', 'checkins:write');
+
+UPDATE permissions SET description = 'View subscriptions, payments and invoices.' WHERE name = 'billing:read';
+UPDATE permissions SET description = 'Record payments and manage invoices.' WHERE name = 'billing:write';
@@ -0,0 +1,24 @@
+-- the billing permissions guard the subscription routes, which the front desk keeps selling
+UPDATE permissions SET description = 'View client subscriptions.' WHERE name = 'billing:read';
+UPDATE permissions SET description = 'Sell, freeze, renew and cancel client subscriptions.' WHERE name = 'billing:write';
+
+INSERT INTO permissions (name, description) VALUES
+('plans:manage', 'Create, edit and remove membership plans.'),
+('checkins:write', 'Check clients in at a branch.')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id
+FROM (VALUES
+    ('branch_admin', 'billing:write'),
+    ('branch_admin', 'checkins:write'),
+    ('recepcionist', 'billing:read'),
+    ('recepcionist', 'billing:write'),
+    ('recepcionist', 'checkins:write')
+) AS grants(role_name, permission_name)
+JOIN roles r ON r.name = grants.role_name
+JOIN permissions p ON p.name = grants.permission_name
+ON CONFLICT DO NOTHING;
+
+-- no route exports reports, the grants are removed with it
+DELETE FROM permissions WHERE name = 'reports:export';
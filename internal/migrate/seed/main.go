@@ -11,6 +11,9 @@ import (
 	env "github.com/vitalfit/api/pkg/Env"
 	dbg "github.com/vitalfit/api/pkg/db"
 	"github.com/vitalfit/api/pkg/mailer"
+	"github.com/vitalfit/api/pkg/notifier"
+	"github.com/vitalfit/api/pkg/secretbox"
+	"github.com/vitalfit/api/pkg/storage"
 	"gorm.io/gorm"
 
 	_ "github.com/lib/pq"
@@ -65,14 +68,33 @@ func main() {
 	}
 	cfg := config.LoadConfig()
 
-	mailer, err := mailer.NewResendClient(cfg.Mail.Resend.ApiKey, cfg.Mail.FromEmail)
+	templates, err := mailer.NewTemplates(cfg.Mail.Brand, cfg.Mail.DefaultLocale)
+	if err != nil {
+		log.Fatal(err)
+	}
+	//the seed never sends email, so the log driver gets no logger
+	mailer, err := mailer.New(cfg.Mail.Config, templates, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	notifier, err := notifier.New(cfg.Notifier, mailer, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	auth := authservices.NewJWTAuthenticator(cfg.Auth.Token.Secret, cfg.Auth.Token.Iss, cfg.Auth.Token.Iss)
 
-	store := store.NewStorage(conn, *cfg, mailer, auth)
+	files, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	secrets, err := secretbox.New(cfg.Auth.MFA.Key)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	store := store.NewStorage(conn, *cfg, notifier, templates, auth, files, secrets)
 
 	Seed(store, conn)
 }
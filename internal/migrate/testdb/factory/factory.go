@@ -0,0 +1,255 @@
+// Package factory builds valid rows for integration tests. Every builder takes overrides applied
+// before the insert and fails the test on error, so a test only spells out what it asserts on:
+//
+//	f := factory.New(t, testdb.New(t))
+//	user := f.User(func(u *authdomain.Users) { u.Email = "Ana@Example.com" })
+//	f.Invitation(user, "123456", time.Hour)
+package factory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	checkindomain "github.com/vitalfit/api/internal/checkins/domain"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	"github.com/vitalfit/api/pkg/secretbox"
+	"gorm.io/gorm"
+)
+
+// Password is the plain password of every user built by the factory
+const Password = "Password123!"
+
+var (
+	// Box seals the outbox payloads of the factory messages
+	Box = mustBox("vitalfit-test-secret")
+
+	sequence atomic.Int64
+
+	// bcrypt is slow on purpose, every user shares one hash
+	passwordOnce sync.Once
+	passwordHash authdomain.Password
+)
+
+type Factory struct {
+	t  testing.TB
+	DB *gorm.DB
+}
+
+func New(t testing.TB, db *testdb.DB) *Factory {
+	return &Factory{t: t, DB: db.Gorm}
+}
+
+func mustBox(passphrase string) *secretbox.Box {
+	box, err := secretbox.New(passphrase)
+	if err != nil {
+		panic(err)
+	}
+	return box
+}
+
+// Seq returns a number unique within the test binary, for emails, documents and names
+func Seq() int64 {
+	return sequence.Add(1)
+}
+
+// HashCode is what the repositories store in place of a one time code
+func HashCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+// Role returns one of the roles the migrations seed: super_admin, branch_admin, accountant,
+// data_analyst, instructor, recepcionist or client
+func (f *Factory) Role(name string) *authdomain.Roles {
+	f.t.Helper()
+	var role authdomain.Roles
+	if err := f.DB.Where("name = ?", name).First(&role).Error; err != nil {
+		f.t.Fatalf("factory: seeded role %s: %v", name, err)
+	}
+	return &role
+}
+
+// NewRole builds a role that is not saved
+func (f *Factory) NewRole(overrides ...func(*authdomain.Roles)) *authdomain.Roles {
+	n := Seq()
+	role := &authdomain.Roles{
+		Name:        fmt.Sprintf("role_%d", n),
+		Level:       2,
+		Description: "Created by a test",
+	}
+	for _, override := range overrides {
+		override(role)
+	}
+	return role
+}
+
+// CreateRole saves a new role
+func (f *Factory) CreateRole(overrides ...func(*authdomain.Roles)) *authdomain.Roles {
+	f.t.Helper()
+	role := f.NewRole(overrides...)
+	f.create(role)
+	return role
+}
+
+// NewUser builds a validated client with its profile that is not saved, its password is Password
+func (f *Factory) NewUser(overrides ...func(*authdomain.Users)) *authdomain.Users {
+	f.t.Helper()
+	passwordOnce.Do(func() {
+		if err := passwordHash.Set(Password); err != nil {
+			f.t.Fatalf("factory: hash password: %v", err)
+		}
+	})
+
+	n := Seq()
+	user := &authdomain.Users{
+		FirstName:         "Test",
+		LastName:          fmt.Sprintf("User %d", n),
+		Email:             fmt.Sprintf("user%d@vitalfit.test", n),
+		Phone:             fmt.Sprintf("+58412%07d", n),
+		IdentityDocument:  fmt.Sprintf("V-%08d", n),
+		PasswordHash:      passwordHash,
+		BirthDate:         time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Gender:            authdomain.GenderPreferNotToSay,
+		PreferredLanguage: "es",
+		IsValidated:       true,
+		RoleID:            f.Role("client").RoleID,
+		ClientProfile: authdomain.ClientProfiles{
+			Status:   authdomain.ClientStatusActive,
+			Category: authdomain.ClientCategoryNew,
+		},
+	}
+	for _, override := range overrides {
+		override(user)
+	}
+	return user
+}
+
+// User saves a new user, see NewUser
+func (f *Factory) User(overrides ...func(*authdomain.Users)) *authdomain.Users {
+	f.t.Helper()
+	user := f.NewUser(overrides...)
+	f.create(user)
+	return user
+}
+
+// Staff saves a new user with the seeded role
+func (f *Factory) Staff(roleName string, overrides ...func(*authdomain.Users)) *authdomain.Users {
+	f.t.Helper()
+	role := f.Role(roleName)
+	return f.User(append([]func(*authdomain.Users){func(u *authdomain.Users) {
+		u.RoleID = role.RoleID
+		u.ClientProfile = authdomain.ClientProfiles{}
+	}}, overrides...)...)
+}
+
+// Branch saves a new active branch with a unique name
+func (f *Factory) Branch(overrides ...func(*branchdomain.Branches)) *branchdomain.Branches {
+	f.t.Helper()
+	branch := &branchdomain.Branches{
+		Name:        fmt.Sprintf("Branch %d", Seq()),
+		AddressLine: "Av. Principal",
+		City:        "Caracas",
+		Country:     "Venezuela",
+		Timezone:    "UTC",
+		IsActive:    true,
+	}
+	for _, override := range overrides {
+		override(branch)
+	}
+	f.create(branch)
+	return branch
+}
+
+// AssignStaff assigns the staff member to the branch
+func (f *Factory) AssignStaff(user *authdomain.Users, branch *branchdomain.Branches) {
+	f.t.Helper()
+	f.create(&branchdomain.StaffBranches{UserID: user.UserID, BranchID: branch.BranchID})
+}
+
+// Checkin records a visit of the client to the branch
+func (f *Factory) Checkin(user *authdomain.Users, branch *branchdomain.Branches) *checkindomain.Checkins {
+	f.t.Helper()
+	checkin := &checkindomain.Checkins{UserID: user.UserID, BranchID: branch.BranchID}
+	f.create(checkin)
+	return checkin
+}
+
+// Subscription sells the client a new plan starting today, a nil visitLimit means unlimited visits
+func (f *Factory) Subscription(user *authdomain.Users, visitLimit *int) *membershipdomain.ClientSubscriptions {
+	f.t.Helper()
+	plan := &membershipdomain.MembershipPlans{
+		Name:         fmt.Sprintf("Plan %d", Seq()),
+		Price:        30,
+		DurationDays: 30,
+		VisitLimit:   visitLimit,
+		AllBranches:  true,
+		IsActive:     true,
+	}
+	f.create(plan)
+	subscription := membershipdomain.NewSubscription(user.UserID, plan, time.Now())
+	f.create(subscription)
+	return subscription
+}
+
+// Invitation stores the activation code of the user, a negative expiresIn makes it already expired
+func (f *Factory) Invitation(user *authdomain.Users, code string, expiresIn time.Duration) *authdomain.UserInvitations {
+	f.t.Helper()
+	invitation := &authdomain.UserInvitations{
+		Token:  HashCode(code),
+		UserID: user.UserID,
+		Expiry: time.Now().Add(expiresIn),
+	}
+	f.create(invitation)
+	return invitation
+}
+
+// ResetToken stores the password reset code of the user, a negative expiresIn makes it already expired
+func (f *Factory) ResetToken(user *authdomain.Users, code string, expiresIn time.Duration) *authdomain.PasswordResetToken {
+	f.t.Helper()
+	token := &authdomain.PasswordResetToken{
+		Token:  HashCode(code),
+		UserID: user.UserID,
+		Expiry: time.Now().Add(expiresIn),
+	}
+	f.create(token)
+	return token
+}
+
+// Message builds an email for the outbox that is not saved, its payload is sealed with Box
+func (f *Factory) Message(template string, data any) *outboxdomain.Outbox {
+	f.t.Helper()
+	message, err := outboxdomain.NewEmail(Box, template, "es", "Test", fmt.Sprintf("user%d@vitalfit.test", Seq()), data, 3)
+	if err != nil {
+		f.t.Fatalf("factory: message: %v", err)
+	}
+	return message
+}
+
+// Count returns the rows of the model's table matching the condition
+func (f *Factory) Count(model any, query string, args ...any) int64 {
+	f.t.Helper()
+	var count int64
+	if err := f.DB.Model(model).Where(query, args...).Count(&count).Error; err != nil {
+		f.t.Fatalf("factory: count %T: %v", model, err)
+	}
+	return count
+}
+
+func (f *Factory) create(value any) {
+	f.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := f.DB.WithContext(ctx).Create(value).Error; err != nil {
+		f.t.Fatalf("factory: create %T: %v", value, err)
+	}
+}
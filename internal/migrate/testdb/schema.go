@@ -0,0 +1,313 @@
+package testdb
+
+import (
+	"database/sql"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+// column is a column of the migrated schema as Postgres reports it
+type column struct {
+	Type     string
+	NotNull  bool
+	Default  bool
+	Identity bool
+}
+
+// constraint is a primary key, unique or foreign key constraint, or a unique index
+type constraint struct {
+	Kind       string // p, u or f
+	Columns    []string
+	References string
+	RefColumns []string
+}
+
+var typeModifier = regexp.MustCompile(`\(.*\)`)
+
+// CheckModels asserts that every GORM model matches the migrated table it maps to: each field has
+// a column of a compatible type, not null, default, primary key and unique tags hold in the
+// database, NOT NULL columns without default are mapped, and every relationship has its foreign key.
+func CheckModels(t *testing.T, db *DB, models ...any) {
+	t.Helper()
+
+	namer := db.Gorm.NamingStrategy
+	cache := &sync.Map{}
+	for _, model := range models {
+		s, err := schema.Parse(model, cache, namer)
+		if err != nil {
+			t.Fatalf("parse %T: %v", model, err)
+		}
+		t.Run(s.Name, func(t *testing.T) {
+			columns, err := loadColumns(db.SQL, s.Table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(columns) == 0 {
+				t.Fatalf("%s maps to table %s, which the migrations do not create", s.Name, s.Table)
+			}
+			constraints, err := loadConstraints(db.SQL, s.Table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkFields(t, s, columns, constraints)
+			checkRelationships(t, db, s)
+		})
+	}
+}
+
+func checkFields(t *testing.T, s *schema.Schema, columns map[string]column, constraints []constraint) {
+	mapped := map[string]bool{}
+	var primaryKey []string
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		mapped[field.DBName] = true
+
+		col, ok := columns[field.DBName]
+		if !ok {
+			t.Errorf("%s.%s: column %s.%s does not exist", s.Name, field.Name, s.Table, field.DBName)
+			continue
+		}
+		if want, tagged := fieldType(field); !typeMatches(want, col.Type, !tagged) {
+			t.Errorf("%s.%s: model type %s, column %s.%s is %s", s.Name, field.Name, want, s.Table, field.DBName, col.Type)
+		}
+		if (field.NotNull || field.PrimaryKey) && !col.NotNull {
+			t.Errorf("%s.%s: model is not null, column %s.%s is nullable", s.Name, field.Name, s.Table, field.DBName)
+		}
+		if field.HasDefaultValue && field.DefaultValueInterface == nil && field.AutoCreateTime == 0 && !col.Default && !col.Identity {
+			// gorm leaves the column out of INSERT and expects the database to fill it
+			t.Errorf("%s.%s: model relies on a default, column %s.%s has none", s.Name, field.Name, s.Table, field.DBName)
+		}
+		if field.PrimaryKey {
+			primaryKey = append(primaryKey, field.DBName)
+		}
+		if field.Unique && !hasConstraint(constraints, "u", []string{field.DBName}) && !hasConstraint(constraints, "p", []string{field.DBName}) {
+			t.Errorf("%s.%s: model is unique, %s.%s has no unique constraint", s.Name, field.Name, s.Table, field.DBName)
+		}
+	}
+
+	if len(primaryKey) == 0 {
+		t.Errorf("%s: model has no primary key", s.Name)
+	} else if !hasConstraint(constraints, "p", primaryKey) {
+		t.Errorf("%s: model primary key is (%s), the table's is (%s)", s.Name, strings.Join(primaryKey, ", "), strings.Join(tableKey(constraints), ", "))
+	}
+
+	for name, col := range columns {
+		if !mapped[name] && col.NotNull && !col.Default && !col.Identity {
+			t.Errorf("%s: column %s.%s is NOT NULL without default and the model does not map it, inserts would fail", s.Name, s.Table, name)
+		}
+	}
+}
+
+// checkRelationships looks for the foreign key in both directions: when both structs have the
+// referenced field gorm cannot tell a belongs to from a has one and may report it reversed
+func checkRelationships(t *testing.T, db *DB, s *schema.Schema) {
+	for _, rel := range s.Relationships.Relations {
+		c := rel.ParseConstraint()
+		if c == nil || c.Schema == nil || c.ReferenceSchema == nil {
+			continue
+		}
+		from, to := dbNames(c.ForeignKeys), dbNames(c.References)
+		found := false
+		for _, side := range []struct {
+			table, references   string
+			columns, refColumns []string
+		}{
+			{c.Schema.Table, c.ReferenceSchema.Table, from, to},
+			{c.ReferenceSchema.Table, c.Schema.Table, to, from},
+		} {
+			constraints, err := loadConstraints(db.SQL, side.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found = found || slices.ContainsFunc(constraints, func(got constraint) bool {
+				return got.Kind == "f" && got.References == side.references &&
+					slices.Equal(got.Columns, side.columns) && slices.Equal(got.RefColumns, side.refColumns)
+			})
+		}
+		if !found {
+			t.Errorf("%s.%s: no foreign key between %s(%s) and %s(%s)", s.Name, rel.Name,
+				c.Schema.Table, strings.Join(from, ", "), c.ReferenceSchema.Table, strings.Join(to, ", "))
+		}
+	}
+}
+
+// fieldType is the explicit type tag, or the family the Go type maps to when there is none
+func fieldType(field *schema.Field) (string, bool) {
+	if tag := field.TagSettings["TYPE"]; tag != "" {
+		return strings.ToLower(tag), true
+	}
+	switch field.DataType {
+	case schema.Bool:
+		return "boolean", false
+	case schema.Int, schema.Uint:
+		return "integer", false
+	case schema.Float:
+		return "numeric", false
+	case schema.String:
+		return "text", false
+	case schema.Time:
+		return "timestamp", false
+	case schema.Bytes:
+		return "bytea", false
+	}
+	return string(field.DataType), false
+}
+
+// aliases maps the spellings used in tags to the names format_type returns
+var aliases = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"int2":        "smallint",
+	"serial":      "integer",
+	"bigserial":   "bigint",
+	"bool":        "boolean",
+	"varchar":     "character varying",
+	"char":        "character",
+	"decimal":     "numeric",
+	"timestamptz": "timestamp with time zone",
+	"float8":      "double precision",
+}
+
+// families are the column types a Go type without a type tag may be stored in
+var families = map[string][]string{
+	"integer":   {"smallint", "integer", "bigint"},
+	"numeric":   {"numeric", "real", "double precision"},
+	"text":      {"text", "character varying", "character", "citext"},
+	"timestamp": {"timestamp without time zone", "timestamp with time zone", "date"},
+}
+
+func typeMatches(want, got string, family bool) bool {
+	want, got = normalizeType(want), normalizeType(got)
+	if want == got {
+		return true
+	}
+	// a length in the tag must match, a tag without one accepts any
+	if !strings.Contains(want, "(") && typeModifier.ReplaceAllString(got, "") == want {
+		return true
+	}
+	return family && slices.Contains(families[want], typeModifier.ReplaceAllString(got, ""))
+}
+
+func normalizeType(t string) string {
+	t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+	name, modifier, _ := strings.Cut(t, "(")
+	if modifier != "" {
+		modifier = "(" + modifier
+	}
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	// timestamp(0) with time zone: move the precision to the end so it is compared like a length
+	if strings.HasPrefix(name, "timestamp") && modifier != "" {
+		precision, rest, _ := strings.Cut(modifier, ")")
+		return strings.TrimSpace(name+" "+strings.TrimSpace(rest)) + precision + ")"
+	}
+	return name + modifier
+}
+
+func loadColumns(db *sql.DB, table string) (map[string]column, error) {
+	rows, err := db.Query(`
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+		       a.atthasdef, a.attidentity <> '' OR a.attgenerated <> ''
+		FROM pg_attribute a
+		WHERE a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := map[string]column{}
+	for rows.Next() {
+		var name string
+		var col column
+		if err := rows.Scan(&name, &col.Type, &col.NotNull, &col.Default, &col.Identity); err != nil {
+			return nil, err
+		}
+		columns[name] = col
+	}
+	return columns, rows.Err()
+}
+
+// loadConstraints reads the key constraints of a table, unique indexes count as unique constraints
+func loadConstraints(db *sql.DB, table string) ([]constraint, error) {
+	rows, err := db.Query(`
+		SELECT c.contype::text,
+		       ARRAY(SELECT a.attname FROM unnest(c.conkey) WITH ORDINALITY k(n, i)
+		             JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.n ORDER BY k.i)::text,
+		       CASE WHEN c.confrelid = 0 THEN '' ELSE c.confrelid::regclass::text END,
+		       ARRAY(SELECT a.attname FROM unnest(coalesce(c.confkey, '{}')) WITH ORDINALITY k(n, i)
+		             JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.n ORDER BY k.i)::text
+		FROM pg_constraint c
+		WHERE c.conrelid = to_regclass($1) AND c.contype IN ('p', 'u', 'f')
+		UNION ALL
+		SELECT 'u',
+		       ARRAY(SELECT a.attname FROM unnest(i.indkey::int2[]) WITH ORDINALITY k(n, o)
+		             JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.n ORDER BY k.o)::text,
+		       '', '{}'
+		FROM pg_index i
+		WHERE i.indrelid = to_regclass($1) AND i.indisunique AND i.indpred IS NULL`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var constraints []constraint
+	for rows.Next() {
+		var c constraint
+		var columns, refColumns string
+		if err := rows.Scan(&c.Kind, &columns, &c.References, &refColumns); err != nil {
+			return nil, err
+		}
+		c.Columns, c.RefColumns = parseArray(columns), parseArray(refColumns)
+		constraints = append(constraints, c)
+	}
+	return constraints, rows.Err()
+}
+
+func hasConstraint(constraints []constraint, kind string, columns []string) bool {
+	return slices.ContainsFunc(constraints, func(c constraint) bool {
+		if c.Kind != kind || len(c.Columns) != len(columns) {
+			return false
+		}
+		// key order is irrelevant for uniqueness
+		return !slices.ContainsFunc(columns, func(name string) bool { return !slices.Contains(c.Columns, name) })
+	})
+}
+
+func tableKey(constraints []constraint) []string {
+	for _, c := range constraints {
+		if c.Kind == "p" {
+			return c.Columns
+		}
+	}
+	return nil
+}
+
+func dbNames(fields []*schema.Field) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.DBName)
+	}
+	return names
+}
+
+// parseArray reads the text form of a name[], {a,b} or {"a b"}
+func parseArray(s string) []string {
+	s = strings.Trim(s, "{}")
+	if s == "" {
+		return nil
+	}
+	names := strings.Split(s, ",")
+	for i, name := range names {
+		names[i] = strings.Trim(name, `"`)
+	}
+	return names
+}
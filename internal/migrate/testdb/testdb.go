@@ -0,0 +1,253 @@
+// Package testdb gives integration tests a disposable Postgres with every migration applied.
+//
+// The server is TEST_DATABASE_URL when it is set, a CI service container for example. Otherwise
+// a throwaway cluster is started with the initdb and pg_ctl binaries found in PG_BIN or the PATH,
+// and removed when the tests end. Without either the tests are skipped, so `go test ./...` stays
+// green on machines without Postgres. When the CI variable is set they fail instead, a pipeline
+// must never pass by skipping them.
+//
+// Every test gets its own database, cloned from a template migrated once per test binary:
+//
+//	func TestMain(m *testing.M) { os.Exit(testdb.Main(m)) }
+//
+//	func TestSomething(t *testing.T) {
+//		db := testdb.New(t)
+//		...
+//	}
+package testdb
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vitalfit/api/internal/migrate"
+	pgdb "github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	ormlogger "gorm.io/gorm/logger"
+
+	_ "github.com/lib/pq"
+)
+
+var (
+	ErrUnavailable = errors.New("no postgres for integration tests, set TEST_DATABASE_URL or install the postgres server binaries")
+
+	server struct {
+		once     sync.Once
+		dsn      string
+		template string
+		stop     func()
+		err      error
+	}
+)
+
+// DB is a migrated database owned by a single test
+type DB struct {
+	Name string
+	DSN  string
+	Gorm *gorm.DB
+	SQL  *sql.DB
+}
+
+// Main runs the tests of a package and tears down the server and template it started
+func Main(m *testing.M) int {
+	code := m.Run()
+	if server.stop != nil {
+		server.stop()
+	}
+	return code
+}
+
+// New creates an empty database with every migration applied, dropped when the test ends.
+// The test is skipped when no Postgres is available, outside of CI.
+func New(t testing.TB) *DB {
+	t.Helper()
+
+	server.once.Do(func() { server.err = start() })
+	if errors.Is(server.err, ErrUnavailable) {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("testdb: %v", server.err)
+		}
+		t.Skip(server.err)
+	}
+	if server.err != nil {
+		t.Fatalf("testdb: %v", server.err)
+	}
+
+	name := "vitalfit_test_" + randomHex(6)
+	if err := exec1(server.dsn, fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, server.template)); err != nil {
+		t.Fatalf("testdb: create database: %v", err)
+	}
+
+	dsn := withDatabase(server.dsn, name)
+	gdb, err := pgdb.New(dsn, 5, 5, "1m")
+	if err != nil {
+		t.Fatalf("testdb: %v", err)
+	}
+	gdb.Logger = ormlogger.Discard
+	sqlDB, err := gdb.DB()
+	if err != nil {
+		t.Fatalf("testdb: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sqlDB.Close()
+		if err := exec1(server.dsn, fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", name)); err != nil {
+			t.Logf("testdb: drop database %s: %v", name, err)
+		}
+	})
+
+	return &DB{Name: name, DSN: dsn, Gorm: gdb, SQL: sqlDB}
+}
+
+// start finds or boots the server and migrates the template the test databases are cloned from
+func start() error {
+	server.dsn = os.Getenv("TEST_DATABASE_URL")
+	if server.dsn == "" {
+		dsn, stop, err := startCluster()
+		if err != nil {
+			return err
+		}
+		server.dsn, server.stop = dsn, stop
+	}
+
+	server.template = "vitalfit_template_" + randomHex(6)
+	if err := exec1(server.dsn, "CREATE DATABASE "+server.template); err != nil {
+		return fmt.Errorf("create template: %w", err)
+	}
+	stopCluster := server.stop
+	server.stop = func() {
+		if err := exec1(server.dsn, fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", server.template)); err != nil {
+			fmt.Fprintf(os.Stderr, "testdb: drop template %s: %v\n", server.template, err)
+		}
+		if stopCluster != nil {
+			stopCluster()
+		}
+	}
+
+	conn, err := sql.Open("postgres", withDatabase(server.dsn, server.template))
+	if err != nil {
+		return err
+	}
+	// the template cannot have open connections while it is cloned
+	defer conn.Close()
+
+	m, err := migrate.New(conn)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := m.Up(ctx, 0); err != nil {
+		return fmt.Errorf("migrate template: %w", err)
+	}
+	return nil
+}
+
+// startCluster runs initdb and pg_ctl in a temporary directory listening only on a unix socket there
+func startCluster() (string, func(), error) {
+	initdb, err := lookPG("initdb")
+	if err != nil {
+		return "", nil, ErrUnavailable
+	}
+	pgctl, err := lookPG("pg_ctl")
+	if err != nil {
+		return "", nil, ErrUnavailable
+	}
+
+	// short path, the socket name is limited to about a hundred bytes
+	dir, err := os.MkdirTemp("", "vitalfit-pg")
+	if err != nil {
+		return "", nil, err
+	}
+	data := filepath.Join(dir, "data")
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("initdb: %w: %s", err, out)
+	}
+
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses='' -c fsync=off -c full_page_writes=off", port, dir)
+	out, err = exec.Command(pgctl, "-D", data, "-l", filepath.Join(dir, "postgres.log"), "-o", options, "-w", "start").CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("pg_ctl start: %w: %s", err, out)
+	}
+
+	stop := func() {
+		exec.Command(pgctl, "-D", data, "-m", "immediate", "-w", "stop").Run()
+		os.RemoveAll(dir)
+	}
+	return fmt.Sprintf("host=%s port=%d user=postgres dbname=postgres sslmode=disable", dir, port), stop, nil
+}
+
+func lookPG(name string) (string, error) {
+	if dir := os.Getenv("PG_BIN"); dir != "" {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return exec.LookPath(name)
+}
+
+// freePort picks a port nothing listens on, the server only uses it to name its socket
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// exec1 runs a statement on its own connection, CREATE and DROP DATABASE cannot run in a transaction
+func exec1(dsn, query string) error {
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err = conn.ExecContext(ctx, query)
+	return err
+}
+
+// withDatabase points a URL or keyword/value DSN at another database
+func withDatabase(dsn, name string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			u.Path = "/" + name
+			return u.String()
+		}
+	}
+	// the last occurrence of a keyword wins
+	return dsn + " dbname=" + name
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,185 @@
+package outboxdomain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotDead   = errors.New("only dead messages can be retried")
+	ErrLeaseLost = errors.New("the lease on the message ran out before it was settled")
+)
+
+type MessageStatus string
+
+const (
+	MessageStatusPending MessageStatus = "pending"
+	MessageStatusSent    MessageStatus = "sent"
+	MessageStatusDead    MessageStatus = "dead"
+)
+
+// The kind of a message is the channel it is delivered through
+const (
+	KindEmail    = "email"
+	KindSMS      = "sms"
+	KindWhatsApp = "whatsapp"
+)
+
+// Outbox holds the messages written in the same transaction as the change that triggers them and
+// delivered later by the dispatcher. The payload is sealed because it carries one time codes,
+// it is cleared once the message is sent.
+type Outbox struct {
+	MessageID      uuid.UUID     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"message_id"`
+	IdempotencyKey string        `gorm:"type:varchar(255);unique;not null" json:"idempotency_key"`
+	Kind           string        `gorm:"type:varchar(32);not null" json:"kind"`
+	Template       string        `gorm:"type:varchar(100);not null" json:"template"`
+	Locale         string        `gorm:"type:varchar(5);not null;default:es" json:"locale"`
+	Recipient      string        `gorm:"type:varchar(255);not null" json:"recipient"`
+	RecipientName  string        `gorm:"type:varchar(255)" json:"recipient_name"`
+	Payload        []byte        `gorm:"type:bytea" json:"-"`
+	UserID         *uuid.UUID    `gorm:"type:uuid" json:"user_id"`
+	Status         MessageStatus `gorm:"type:varchar(16);not null;default:pending" json:"status"`
+	Attempts       int           `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts    int           `gorm:"not null" json:"max_attempts"`
+	AvailableAt    time.Time     `gorm:"not null" json:"available_at"`
+	LockedUntil    *time.Time    `json:"-"`
+	LastError      string        `gorm:"type:text" json:"last_error"`
+	CreatedAt      time.Time     `json:"created_at"`
+	SentAt         *time.Time    `json:"sent_at"`
+	DeadAt         *time.Time    `json:"dead_at"`
+}
+
+// Sealer encrypts the payloads at rest, *secretbox.Box implements it
+type Sealer interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(sealed []byte) ([]byte, error)
+}
+
+// NewEmail builds an email for the template with its data sealed by box
+func NewEmail(box Sealer, template, locale, recipientName, recipient string, data any, maxAttempts int) (*Outbox, error) {
+	return NewMessage(box, KindEmail, template, locale, recipientName, recipient, data, maxAttempts)
+}
+
+// NewMessage seals the template values of a message sent through the channel of its kind,
+// the recipient is an email address or a phone number accordingly
+func NewMessage(box Sealer, kind, template, locale, recipientName, recipient string, data any, maxAttempts int) (*Outbox, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := box.Seal(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &Outbox{
+		Kind:          kind,
+		Template:      template,
+		Locale:        locale,
+		Recipient:     recipient,
+		RecipientName: recipientName,
+		Payload:       payload,
+		MaxAttempts:   maxAttempts,
+	}, nil
+}
+
+// Data opens the payload into the values the template renders
+func (m *Outbox) Data(box Sealer) (map[string]interface{}, error) {
+	plaintext, err := box.Open(m.Payload)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// IdempotencyKey hashes the parts that identify a message, enqueueing the same key twice keeps
+// the first message and providers use it to drop a send they already delivered. The hash keeps
+// the key within its column whatever the length of the parts.
+func IdempotencyKey(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, ":")))
+	return hex.EncodeToString(hash[:])
+}
+
+// AttachTo ties the message to the user and derives its key from the code it carries.
+// Messages of deleted users are deleted with them.
+func (m *Outbox) AttachTo(userID uuid.UUID, token string) {
+	m.UserID = &userID
+	m.IdempotencyKey = IdempotencyKey(m.Template, userID.String(), token)
+}
+
+// RetryPolicy sets how failed deliveries are retried before the message is dead-lettered
+type RetryPolicy struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	MaxAttempts int
+}
+
+// Backoff returns the wait after the given failed attempt, doubling from BaseDelay up to MaxDelay
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// DispatchSummary counts what a dispatch did with the messages it claimed
+type DispatchSummary struct {
+	Claimed int `json:"claimed"`
+	Sent    int `json:"sent"`
+	Retried int `json:"retried"`
+	Dead    int `json:"dead"`
+}
+
+// MessageListQuery is bound from the admin listing query string
+type MessageListQuery struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending sent dead"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type MessageFilter struct {
+	Status   MessageStatus
+	Page     int
+	PageSize int
+}
+
+const DefaultMessagesPageSize = 20
+
+func NewMessageFilter(query MessageListQuery) MessageFilter {
+	filter := MessageFilter{
+		Status:   MessageStatus(query.Status),
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultMessagesPageSize
+	}
+	return filter
+}
+
+func (f MessageFilter) Offset() int {
+	return (f.Page - 1) * f.PageSize
+}
+
+type MessagePage struct {
+	Messages []Outbox `json:"messages"`
+	Total    int64    `json:"total"`
+	Page     int      `json:"page"`
+	PageSize int      `json:"page_size"`
+}
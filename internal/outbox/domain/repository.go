@@ -0,0 +1,19 @@
+package outboxdomain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, tx *gorm.DB, message *Outbox) error
+	Claim(ctx context.Context, lease time.Duration) (*Outbox, error)
+	MarkSent(ctx context.Context, messageID uuid.UUID, leaseUntil time.Time) error
+	MarkFailed(ctx context.Context, messageID uuid.UUID, leaseUntil time.Time, lastError string, retryAt time.Time) error
+	MarkDead(ctx context.Context, messageID uuid.UUID, leaseUntil time.Time, lastError string) error
+	List(ctx context.Context, filter MessageFilter) ([]Outbox, int64, error)
+	Retry(ctx context.Context, messageID uuid.UUID) (*Outbox, error)
+}
@@ -0,0 +1,15 @@
+package outboxdomain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type OutboxServicesInterface interface {
+	Dispatch(ctx context.Context) (*DispatchSummary, error)
+	List(ctx context.Context, filter MessageFilter) (*MessagePage, error)
+	Retry(ctx context.Context, messageID uuid.UUID) (*Outbox, error)
+	Templates() TemplateList
+	Preview(template, locale string) (*TemplatePreview, error)
+}
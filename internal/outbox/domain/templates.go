@@ -0,0 +1,29 @@
+package outboxdomain
+
+// TemplateList is what admins can preview
+type TemplateList struct {
+	Templates []string `json:"templates"`
+	Locales   []string `json:"locales"`
+}
+
+// TemplatePreviewQuery is bound from the preview query string, the default locale is used when it is empty
+type TemplatePreviewQuery struct {
+	Locale string `form:"locale" binding:"omitempty,max=10"`
+	Format string `form:"format" binding:"omitempty,oneof=json html text"`
+}
+
+// TemplatePreview is a template rendered with the sample values
+type TemplatePreview struct {
+	Template string `json:"template"`
+	Locale   string `json:"locale"`
+	Subject  string `json:"subject"`
+	HTML     string `json:"html"`
+	Text     string `json:"text"`
+}
+
+// PreviewData fills the variables of every template with sample values
+var PreviewData = map[string]interface{}{
+	"Username": "Ana",
+	"CODE":     "123456",
+	"NewEmail": "ana.nueva@example.com",
+}
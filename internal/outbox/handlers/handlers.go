@@ -0,0 +1,155 @@
+package outboxhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	appservices "github.com/vitalfit/api/internal/app/services"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+	"github.com/vitalfit/api/pkg/mailer"
+)
+
+type OutboxHandlersInterface interface {
+	OutboxRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+}
+
+type OutboxHandlers struct {
+	services appservices.Services
+}
+
+func NewOutboxHandlers(services appservices.Services) *OutboxHandlers {
+	return &OutboxHandlers{services: services}
+}
+
+// @Summary		List outbox messages
+// @Description	Returns the queued, sent and dead-lettered messages, newest first. Dead messages ran out of attempts and keep their last error.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			status		query		string						false	"Status (pending, sent, dead)"
+// @Param			page		query		int							false	"Page, starting at 1"
+// @Param			page_size	query		int							false	"Page size, up to 100"
+// @Success		200			{object}	outboxdomain.MessagePage	"messages"
+// @Failure		400			{object}	map[string]interface{}		"invalid filter"
+// @Failure		401			{object}	map[string]interface{}		"unauthorized"
+// @Failure		403			{object}	map[string]interface{}		"forbidden"
+// @Failure		500			{object}	map[string]interface{}		"internal server error"
+// @Router			/admin/outbox [get]
+func (h *OutboxHandlers) listMessagesHandler(c *gin.Context) {
+	var query outboxdomain.MessageListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	page, err := h.services.OutboxServices.List(c.Request.Context(), outboxdomain.NewMessageFilter(query))
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary		Retry a dead message
+// @Description	Puts a dead-lettered message back in the queue with a fresh set of attempts.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			message_id	path		string					true	"Message ID"
+// @Success		200			{object}	outboxdomain.Outbox		"queued message"
+// @Failure		400			{object}	map[string]interface{}	"invalid message id"
+// @Failure		401			{object}	map[string]interface{}	"unauthorized"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		404			{object}	map[string]interface{}	"message not found"
+// @Failure		409			{object}	map[string]interface{}	"message is not dead"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/admin/outbox/{message_id}/retry [post]
+func (h *OutboxHandlers) retryMessageHandler(c *gin.Context) {
+	messageID, err := uuid.Parse(c.Param("message_id"))
+	if err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	message, err := h.services.OutboxServices.Retry(c.Request.Context(), messageID)
+	if err != nil {
+		switch {
+		case errors.Is(err, shared_errors.ErrNotFound):
+			h.services.LogErrors.NotFoundResponse(c)
+		case errors.Is(err, outboxdomain.ErrNotDead):
+			h.services.LogErrors.ConflictResponse(c, err)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "outbox.retry",
+		TargetType: auditdomain.TargetOutbox,
+		TargetID:   message.MessageID.String(),
+		Before:     gin.H{"status": outboxdomain.MessageStatusDead},
+		After:      gin.H{"status": message.Status},
+	})
+	c.JSON(http.StatusOK, message)
+}
+
+// @Summary		List email templates
+// @Description	Returns the email templates and the locales they are written in.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	outboxdomain.TemplateList	"templates"
+// @Failure		401	{object}	map[string]interface{}		"unauthorized"
+// @Failure		403	{object}	map[string]interface{}		"forbidden"
+// @Router			/admin/outbox/templates [get]
+func (h *OutboxHandlers) listTemplatesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, h.services.OutboxServices.Templates())
+}
+
+// @Summary		Preview an email template
+// @Description	Renders a template with sample values and the configured branding. Locales without a variant fall back to the default one. The format html or text returns the body alone so it can be opened in a browser.
+// @Tags			Admin
+// @Security		ApiKeyAuth
+// @Produce		json,html,plain
+// @Param			template	path		string							true	"Template name, e.g. user_reset"
+// @Param			locale		query		string							false	"Locale (es, en)"
+// @Param			format		query		string							false	"Format (json, html, text), json by default"
+// @Success		200			{object}	outboxdomain.TemplatePreview	"rendered template"
+// @Failure		400			{object}	map[string]interface{}			"invalid query"
+// @Failure		401			{object}	map[string]interface{}			"unauthorized"
+// @Failure		403			{object}	map[string]interface{}			"forbidden"
+// @Failure		404			{object}	map[string]interface{}			"template not found"
+// @Failure		500			{object}	map[string]interface{}			"internal server error"
+// @Router			/admin/outbox/templates/{template}/preview [get]
+func (h *OutboxHandlers) previewTemplateHandler(c *gin.Context) {
+	var query outboxdomain.TemplatePreviewQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	rendered, err := h.services.OutboxServices.Preview(c.Param("template"), query.Locale)
+	if err != nil {
+		switch {
+		case errors.Is(err, mailer.ErrUnknownTemplate):
+			h.services.LogErrors.NotFoundResponse(c)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+
+	switch query.Format {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rendered.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(rendered.Text))
+	default:
+		c.JSON(http.StatusOK, rendered)
+	}
+}
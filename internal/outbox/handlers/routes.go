@@ -0,0 +1,16 @@
+package outboxhandlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+func (r *OutboxHandlers) OutboxRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+	outboxGroup := rg.Group("/admin/outbox").Use(m.AuthJwtTokenMiddleware(), m.RequirePermission("outbox:manage"))
+	{ //private routes
+		outboxGroup.GET("", r.listMessagesHandler)
+		outboxGroup.GET("/templates", r.listTemplatesHandler)
+		outboxGroup.GET("/templates/:template/preview", r.previewTemplateHandler)
+		outboxGroup.POST("/:message_id/retry", r.retryMessageHandler)
+	}
+}
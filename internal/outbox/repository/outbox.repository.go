@@ -0,0 +1,203 @@
+package outboxrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// claimQuery leases the next due message and counts the attempt up front, so a dispatcher
+// that dies while sending still spends one. SKIP LOCKED lets several dispatchers run at once.
+const claimQuery = `
+UPDATE outbox SET locked_until = @lease_until, attempts = attempts + 1
+WHERE message_id IN (
+	SELECT message_id FROM outbox
+	WHERE status = 'pending' AND available_at <= @now
+		AND (locked_until IS NULL OR locked_until < @now)
+	ORDER BY available_at
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`
+
+type OutboxStore struct {
+	db *gorm.DB
+}
+
+func NewOutboxStore(db *gorm.DB) *OutboxStore {
+	return &OutboxStore{db: db}
+}
+
+// Enqueue writes the message within tx, or on its own when tx is nil. A message whose
+// idempotency key is already queued is dropped.
+func (s *OutboxStore) Enqueue(ctx context.Context, tx *gorm.DB, message *outboxdomain.Outbox) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if tx == nil {
+		tx = s.db
+	}
+	if message.Kind == "" {
+		message.Kind = outboxdomain.KindEmail
+	}
+	if message.AvailableAt.IsZero() {
+		message.AvailableAt = time.Now()
+	}
+	return tx.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "idempotency_key"}},
+			DoNothing: true,
+		}).
+		Create(message).Error
+}
+
+// Claim leases a single message so the lease only has to outlast one send, it returns nil when
+// nothing is due. The lease end read back is the token the Mark* updates are checked against.
+func (s *OutboxStore) Claim(ctx context.Context, lease time.Duration) (*outboxdomain.Outbox, error) {
+	var messages []outboxdomain.Outbox
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	now := time.Now()
+	err := s.db.WithContext(ctx).
+		Raw(claimQuery,
+			sql.Named("now", now),
+			sql.Named("lease_until", now.Add(lease)),
+		).
+		Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	return &messages[0], nil
+}
+
+// MarkSent clears the payload, the codes it carries are not needed anymore
+func (s *OutboxStore) MarkSent(ctx context.Context, messageID uuid.UUID, leaseUntil time.Time) error {
+	return s.release(ctx, messageID, leaseUntil, map[string]interface{}{
+		"status":       outboxdomain.MessageStatusSent,
+		"sent_at":      time.Now(),
+		"payload":      nil,
+		"locked_until": nil,
+		"last_error":   "",
+	})
+}
+
+func (s *OutboxStore) MarkFailed(ctx context.Context, messageID uuid.UUID, leaseUntil time.Time, lastError string, retryAt time.Time) error {
+	return s.release(ctx, messageID, leaseUntil, map[string]interface{}{
+		"available_at": retryAt,
+		"locked_until": nil,
+		"last_error":   lastError,
+	})
+}
+
+func (s *OutboxStore) MarkDead(ctx context.Context, messageID uuid.UUID, leaseUntil time.Time, lastError string) error {
+	return s.release(ctx, messageID, leaseUntil, map[string]interface{}{
+		"status":       outboxdomain.MessageStatusDead,
+		"dead_at":      time.Now(),
+		"locked_until": nil,
+		"last_error":   lastError,
+	})
+}
+
+// List returns the messages with the status, newest first
+func (s *OutboxStore) List(ctx context.Context, filter outboxdomain.MessageFilter) ([]outboxdomain.Outbox, int64, error) {
+	var (
+		messages []outboxdomain.Outbox
+		total    int64
+	)
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx).Model(&outboxdomain.Outbox{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.
+		Order("created_at DESC, message_id").
+		Offset(filter.Offset()).
+		Limit(filter.PageSize).
+		Find(&messages).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// Retry puts a dead message back in the queue with a fresh set of attempts
+func (s *OutboxStore) Retry(ctx context.Context, messageID uuid.UUID) (*outboxdomain.Outbox, error) {
+	var message outboxdomain.Outbox
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := db.WithTX(s.db, func(tx *gorm.DB) error {
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("message_id = ?", messageID).
+			First(&message).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shared_errors.ErrNotFound //rollback
+			}
+			return err //rollback
+		}
+		if message.Status != outboxdomain.MessageStatusDead {
+			return outboxdomain.ErrNotDead //rollback
+		}
+
+		message.Status = outboxdomain.MessageStatusPending
+		message.Attempts = 0
+		message.AvailableAt = time.Now()
+		message.DeadAt = nil
+		err = tx.WithContext(ctx).
+			Model(&message).
+			Updates(map[string]interface{}{
+				"status":       message.Status,
+				"attempts":     message.Attempts,
+				"available_at": message.AvailableAt,
+				"dead_at":      nil,
+			}).Error
+		if err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// release settles a claimed message only while the lease taken by the caller is still the current
+// one, a message whose lease ran out and was claimed again belongs to the new holder.
+func (s *OutboxStore) release(ctx context.Context, messageID uuid.UUID, leaseUntil time.Time, fields map[string]interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).
+		Model(&outboxdomain.Outbox{}).
+		Where("message_id = ? AND locked_until = ?", messageID, leaseUntil).
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return outboxdomain.ErrLeaseLost
+	}
+	return nil
+}
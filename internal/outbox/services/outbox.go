@@ -0,0 +1,154 @@
+package outboxservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	"github.com/vitalfit/api/internal/store"
+	"github.com/vitalfit/api/pkg/notifier"
+)
+
+// sendTimeout bounds a single delivery, it has to stay well below the lease of a message
+const sendTimeout = time.Second * 30
+
+var errNoNotifier = errors.New("no notifier configured")
+
+type OutboxService struct {
+	store store.Storage
+}
+
+func NewOutboxService(store store.Storage) *OutboxService {
+	return &OutboxService{store: store}
+}
+
+// Dispatch delivers up to a batch of due messages, leasing them one at a time so a lease never
+// has to cover more than one send. Failures are retried with exponential backoff and dead-lettered
+// once they run out of attempts, payloads that cannot be opened are dead-lettered at once.
+func (s *OutboxService) Dispatch(ctx context.Context) (*outboxdomain.DispatchSummary, error) {
+	cfg := s.store.Config.Outbox
+	summary := &outboxdomain.DispatchSummary{}
+	for summary.Claimed < cfg.BatchSize {
+		// a message left behind is claimed again once its lease is over
+		if ctx.Err() != nil {
+			break
+		}
+		message, err := s.store.Outbox.Claim(ctx, cfg.Lease)
+		if err != nil {
+			return summary, err
+		}
+		if message == nil {
+			break
+		}
+		summary.Claimed++
+
+		if err := s.deliver(ctx, message, summary); err != nil && !errors.Is(err, outboxdomain.ErrLeaseLost) {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// deliver sends a claimed message and settles it under its lease, a message whose lease ran out
+// is left to the dispatcher that holds it now and is not counted
+func (s *OutboxService) deliver(ctx context.Context, message *outboxdomain.Outbox, summary *outboxdomain.DispatchSummary) error {
+	leaseUntil := *message.LockedUntil
+
+	data, err := message.Data(s.store.Secrets)
+	if err != nil {
+		if err := s.store.Outbox.MarkDead(ctx, message.MessageID, leaseUntil, fmt.Sprintf("open payload: %v", err)); err != nil {
+			return err
+		}
+		summary.Dead++
+		return nil
+	}
+
+	err = s.send(ctx, message, data)
+	switch {
+	case err == nil:
+		if err := s.store.Outbox.MarkSent(ctx, message.MessageID, leaseUntil); err != nil {
+			return err
+		}
+		summary.Sent++
+	case message.Attempts >= message.MaxAttempts:
+		if err := s.store.Outbox.MarkDead(ctx, message.MessageID, leaseUntil, err.Error()); err != nil {
+			return err
+		}
+		summary.Dead++
+	default:
+		retryAt := time.Now().Add(s.store.Config.Outbox.Retry.Backoff(message.Attempts))
+		if err := s.store.Outbox.MarkFailed(ctx, message.MessageID, leaseUntil, err.Error(), retryAt); err != nil {
+			return err
+		}
+		summary.Retried++
+	}
+	return nil
+}
+
+func (s *OutboxService) send(ctx context.Context, message *outboxdomain.Outbox, data map[string]interface{}) error {
+	if s.store.Notifier == nil {
+		return errNoNotifier
+	}
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	return s.store.Notifier.Send(ctx, notifier.Notification{
+		Channel:        notifier.Channel(message.Kind),
+		Template:       message.Template,
+		Locale:         message.Locale,
+		Name:           message.RecipientName,
+		To:             message.Recipient,
+		Data:           data,
+		IdempotencyKey: message.IdempotencyKey,
+	})
+}
+
+func (s *OutboxService) List(ctx context.Context, filter outboxdomain.MessageFilter) (*outboxdomain.MessagePage, error) {
+	messages, total, err := s.store.Outbox.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &outboxdomain.MessagePage{
+		Messages: messages,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, nil
+}
+
+// Retry gives a dead message a fresh set of attempts
+func (s *OutboxService) Retry(ctx context.Context, messageID uuid.UUID) (*outboxdomain.Outbox, error) {
+	return s.store.Outbox.Retry(ctx, messageID)
+}
+
+func (s *OutboxService) Templates() outboxdomain.TemplateList {
+	return outboxdomain.TemplateList{
+		Templates: s.store.MailTemplates.Names(),
+		Locales:   s.store.MailTemplates.Locales(),
+	}
+}
+
+// Preview renders a template with sample values, the .tmpl extension of the name is optional
+func (s *OutboxService) Preview(template, locale string) (*outboxdomain.TemplatePreview, error) {
+	if !strings.HasSuffix(template, ".tmpl") {
+		template += ".tmpl"
+	}
+	if locale == "" {
+		locale = s.store.Config.Mail.DefaultLocale
+	}
+	rendered, err := s.store.MailTemplates.Render(template, locale, outboxdomain.PreviewData)
+	if err != nil {
+		return nil, err
+	}
+	return &outboxdomain.TemplatePreview{
+		Template: template,
+		Locale:   rendered.Locale,
+		Subject:  rendered.Subject,
+		HTML:     rendered.HTML,
+		Text:     rendered.Text,
+	}, nil
+}
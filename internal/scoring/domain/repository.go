@@ -0,0 +1,15 @@
+package scoringdomain
+
+import (
+	"context"
+	"time"
+)
+
+type ScoringRepository interface {
+	ListMetrics(ctx context.Context, now time.Time, rules Rules) ([]ClientMetrics, error)
+	Apply(ctx context.Context, run *ScoringRuns, scores []ClientScore) error
+	LastRun(ctx context.Context) (*ScoringRuns, error)
+	Exclusive(ctx context.Context, fn func(ctx context.Context) error) error
+	ListChanges(ctx context.Context, filter CategoryChangeFilter) ([]ClientCategoryHistory, error)
+	ListAtRisk(ctx context.Context) ([]AtRiskClient, error)
+}
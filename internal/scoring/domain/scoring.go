@@ -0,0 +1,201 @@
+package scoringdomain
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+)
+
+var (
+	ErrRunInProgress = errors.New("a scoring run is already in progress")
+	ErrRunNotDue     = errors.New("the last scoring run is recent")
+)
+
+// Rules holds the weights and thresholds used to score and categorize clients.
+// Weights are the points each factor adds to the 0-100 score.
+type Rules struct {
+	AttendanceWeight  int `json:"attendance_weight"`
+	TenureWeight      int `json:"tenure_weight"`
+	PunctualityWeight int `json:"punctuality_weight"`
+	RecencyWeight     int `json:"recency_weight"`
+
+	// AttendanceWindowDays visits are counted over this window, TargetVisits of them earn every attendance point
+	AttendanceWindowDays int `json:"attendance_window_days"`
+	TargetVisits         int `json:"target_visits"`
+	// TenureFullDays of membership earn every tenure point
+	TenureFullDays int `json:"tenure_full_days"`
+	// PaymentWindowDays is the window whose coverage by a paid subscription measures punctuality
+	PaymentWindowDays int `json:"payment_window_days"`
+	// InactivityDays without a visit loses every recency point and flags the client as AtRisk
+	InactivityDays int `json:"inactivity_days"`
+
+	NewClientDays int `json:"new_client_days"`
+	VIPScore      int `json:"vip_score"`
+	AtRiskScore   int `json:"at_risk_score"`
+}
+
+// ClientMetrics are the raw facts a client is scored on
+type ClientMetrics struct {
+	UserID       uuid.UUID
+	MemberSince  time.Time
+	RecentVisits int
+	LastVisitAt  *time.Time
+	CoveredDays  int
+	Scoring      int
+	Category     authdomain.ClientCategoryEnum
+}
+
+// ClientScore is the outcome of evaluating a client
+type ClientScore struct {
+	UserID           uuid.UUID
+	PreviousScoring  int
+	Scoring          int
+	PreviousCategory authdomain.ClientCategoryEnum
+	Category         authdomain.ClientCategoryEnum
+}
+
+func (s ClientScore) Changed() bool {
+	return s.Scoring != s.PreviousScoring || s.CategoryChanged()
+}
+
+func (s ClientScore) CategoryChanged() bool {
+	return s.Category != s.PreviousCategory
+}
+
+// Evaluate scores the client and picks its category.
+// New clients keep the New category until NewClientDays, inactive or low scoring clients become AtRisk.
+func (r Rules) Evaluate(m ClientMetrics, now time.Time) ClientScore {
+	tenureDays := daysBetween(m.MemberSince, now)
+	idleDays := tenureDays
+	if m.LastVisitAt != nil {
+		idleDays = daysBetween(*m.LastVisitAt, now)
+	}
+
+	attendance := ratio(m.RecentVisits, r.TargetVisits)
+	tenure := ratio(tenureDays, r.TenureFullDays)
+	// clients younger than the payment window can only be judged on the days they have been members
+	punctuality := ratio(m.CoveredDays, min(r.PaymentWindowDays, tenureDays+1))
+	recency := 1 - ratio(idleDays, r.InactivityDays)
+
+	score := int(math.Round(
+		attendance*float64(r.AttendanceWeight) +
+			tenure*float64(r.TenureWeight) +
+			punctuality*float64(r.PunctualityWeight) +
+			recency*float64(r.RecencyWeight),
+	))
+
+	var category authdomain.ClientCategoryEnum
+	switch {
+	case tenureDays < r.NewClientDays:
+		category = authdomain.ClientCategoryNew
+	case idleDays >= r.InactivityDays, score < r.AtRiskScore:
+		category = authdomain.ClientCategoryAtRisk
+	case score >= r.VIPScore:
+		category = authdomain.ClientCategoryVIP
+	default:
+		category = authdomain.ClientCategoryRegular
+	}
+
+	return ClientScore{
+		UserID:           m.UserID,
+		PreviousScoring:  m.Scoring,
+		Scoring:          score,
+		PreviousCategory: m.Category,
+		Category:         category,
+	}
+}
+
+// ratio returns value/target capped to [0, 1]
+func ratio(value, target int) float64 {
+	if target <= 0 {
+		return 1
+	}
+	return math.Max(0, math.Min(float64(value)/float64(target), 1))
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// ClientCategoryHistory records each automatic change of a client category
+type ClientCategoryHistory struct {
+	HistoryID        uuid.UUID                     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"history_id"`
+	UserID           uuid.UUID                     `gorm:"type:uuid;not null" json:"user_id"`
+	PreviousCategory authdomain.ClientCategoryEnum `gorm:"type:client_category;not null" json:"previous_category"`
+	NewCategory      authdomain.ClientCategoryEnum `gorm:"type:client_category;not null" json:"new_category"`
+	PreviousScoring  int                           `gorm:"not null" json:"previous_scoring"`
+	NewScoring       int                           `gorm:"not null" json:"new_scoring"`
+	ChangedAt        time.Time                     `gorm:"autoCreateTime" json:"changed_at"`
+}
+
+type CategoryChangeFilter struct {
+	UserID   *uuid.UUID
+	Category authdomain.ClientCategoryEnum
+	From     *time.Time
+	To       *time.Time
+}
+
+type CategoryChangeQuery struct {
+	UserID   string `form:"user_id" binding:"omitempty,uuid"`
+	Category string `form:"category" binding:"omitempty,oneof=VIP Regular New AtRisk"`
+	From     string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To       string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// NewCategoryChangeFilter converts the query string, To includes the whole day
+func NewCategoryChangeFilter(q CategoryChangeQuery) CategoryChangeFilter {
+	filter := CategoryChangeFilter{
+		Category: authdomain.ClientCategoryEnum(q.Category),
+	}
+	if id, err := uuid.Parse(q.UserID); err == nil {
+		filter.UserID = &id
+	}
+	if from, err := time.Parse(time.DateOnly, q.From); err == nil {
+		filter.From = &from
+	}
+	if to, err := time.Parse(time.DateOnly, q.To); err == nil {
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	return filter
+}
+
+// AtRiskClient is a row of the list handed to the retention team
+type AtRiskClient struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	Email       string     `json:"email"`
+	Phone       string     `json:"phone"`
+	Scoring     int        `json:"scoring"`
+	LastVisitAt *time.Time `json:"last_visit_at"`
+	AtRiskSince *time.Time `json:"at_risk_since"`
+}
+
+// RunSummary reports what a scoring run did
+type RunSummary struct {
+	StartedAt       time.Time                             `json:"started_at"`
+	Evaluated       int                                   `json:"evaluated"`
+	Updated         int                                   `json:"updated"`
+	CategoryChanges int                                   `json:"category_changes"`
+	Categories      map[authdomain.ClientCategoryEnum]int `json:"categories"`
+}
+
+// ScoringRuns records a finished run, the scheduled job reads the latest to skip runs that are not due
+type ScoringRuns struct {
+	RunID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"run_id"`
+	StartedAt       time.Time `gorm:"not null" json:"started_at"`
+	Evaluated       int       `gorm:"not null" json:"evaluated"`
+	Updated         int       `gorm:"not null" json:"updated"`
+	CategoryChanges int       `gorm:"not null" json:"category_changes"`
+	FinishedAt      time.Time `gorm:"autoCreateTime" json:"finished_at"`
+}
+
+// Due reports whether a scheduled run should start after this one. Half an interval is enough so
+// the tick of the replica that ran it, which comes a little before a full interval, is not skipped.
+func (r *ScoringRuns) Due(now time.Time, interval time.Duration) bool {
+	return now.Sub(r.StartedAt) >= interval/2
+}
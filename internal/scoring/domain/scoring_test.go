@@ -0,0 +1,30 @@
+package scoringdomain_test
+
+import (
+	"testing"
+	"time"
+
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+)
+
+func TestScoringRunDue(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		started time.Time
+		want    bool
+	}{
+		{name: "another replica just ran it", started: now.Add(-time.Minute), want: false},
+		{name: "before half the interval", started: now.Add(-11 * time.Hour), want: false},
+		{name: "tick of the replica that ran it", started: now.Add(-24*time.Hour + time.Second), want: true},
+		{name: "overdue", started: now.Add(-48 * time.Hour), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := &scoringdomain.ScoringRuns{StartedAt: tt.started}
+			if got := run.Due(now, 24*time.Hour); got != tt.want {
+				t.Errorf("Due = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package scoringdomain
+
+import (
+	"context"
+	"time"
+)
+
+type ScoringServicesInterface interface {
+	Run(ctx context.Context) (*RunSummary, error)
+	RunDue(ctx context.Context, interval time.Duration) (*RunSummary, error)
+	Rules() Rules
+	Changes(ctx context.Context, filter CategoryChangeFilter) ([]ClientCategoryHistory, error)
+	AtRisk(ctx context.Context) ([]AtRiskClient, error)
+}
@@ -0,0 +1,120 @@
+package scoringhandlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	appservices "github.com/vitalfit/api/internal/app/services"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+type ScoringHandlersInterface interface {
+	ScoringRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware)
+}
+
+type ScoringHandlers struct {
+	services appservices.Services
+}
+
+func NewScoringHandlers(services appservices.Services) *ScoringHandlers {
+	return &ScoringHandlers{services: services}
+}
+
+// @Summary		Get scoring rules
+// @Description	Returns the weights and thresholds the categorization job uses. They are configured through SCORING_* environment variables.
+// @Tags			Scoring
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	scoringdomain.Rules		"rules"
+// @Failure		401	{object}	map[string]interface{}	"unauthorized"
+// @Failure		403	{object}	map[string]interface{}	"forbidden"
+// @Router			/scoring/rules [get]
+func (h *ScoringHandlers) rulesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, h.services.ScoringServices.Rules())
+}
+
+// @Summary		List at risk clients
+// @Description	Returns the clients the last scoring run categorized as AtRisk, longest inactive first, for the retention team.
+// @Tags			Scoring
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}	"clients"
+// @Failure		401	{object}	map[string]interface{}	"unauthorized"
+// @Failure		403	{object}	map[string]interface{}	"forbidden"
+// @Failure		500	{object}	map[string]interface{}	"internal server error"
+// @Router			/scoring/at-risk [get]
+func (h *ScoringHandlers) atRiskHandler(c *gin.Context) {
+	clients, err := h.services.ScoringServices.AtRisk(c.Request.Context())
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"clients": clients,
+	})
+}
+
+// @Summary		List category changes
+// @Description	Returns the category changes recorded by the scoring job, newest first. from and to are inclusive dates (YYYY-MM-DD).
+// @Tags			Scoring
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			user_id		query		string					false	"User ID"
+// @Param			category	query		string					false	"New category (VIP, Regular, New, AtRisk)"
+// @Param			from		query		string					false	"From date"
+// @Param			to			query		string					false	"To date"
+// @Success		200			{object}	map[string]interface{}	"changes"
+// @Failure		400			{object}	map[string]interface{}	"invalid filter"
+// @Failure		401			{object}	map[string]interface{}	"unauthorized"
+// @Failure		403			{object}	map[string]interface{}	"forbidden"
+// @Failure		500			{object}	map[string]interface{}	"internal server error"
+// @Router			/scoring/changes [get]
+func (h *ScoringHandlers) changesHandler(c *gin.Context) {
+	var query scoringdomain.CategoryChangeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.services.LogErrors.BadRequestResponse(c, err)
+		return
+	}
+
+	changes, err := h.services.ScoringServices.Changes(c.Request.Context(), scoringdomain.NewCategoryChangeFilter(query))
+	if err != nil {
+		h.services.LogErrors.InternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+	})
+}
+
+// @Summary		Run client scoring
+// @Description	Recomputes the scoring and category of every client now instead of waiting for the scheduled run.
+// @Tags			Scoring
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Success		200	{object}	scoringdomain.RunSummary	"run summary"
+// @Failure		401	{object}	map[string]interface{}		"unauthorized"
+// @Failure		403	{object}	map[string]interface{}		"forbidden"
+// @Failure		409	{object}	map[string]interface{}		"a run is already in progress"
+// @Failure		500	{object}	map[string]interface{}		"internal server error"
+// @Router			/scoring/run [post]
+func (h *ScoringHandlers) runHandler(c *gin.Context) {
+	summary, err := h.services.ScoringServices.Run(c.Request.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, scoringdomain.ErrRunInProgress):
+			h.services.LogErrors.ConflictResponse(c, err)
+		default:
+			h.services.LogErrors.InternalServerError(c, err)
+		}
+		return
+	}
+	h.services.AuditLogger.Log(c, auditdomain.Entry{
+		Action:     "scoring.run",
+		TargetType: auditdomain.TargetScoring,
+		After:      summary,
+	})
+	c.JSON(http.StatusOK, summary)
+}
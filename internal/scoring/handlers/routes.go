@@ -0,0 +1,20 @@
+package scoringhandlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitalfit/api/internal/shared/middleware/auth"
+)
+
+func (r *ScoringHandlers) ScoringRoutes(rg *gin.RouterGroup, m *auth.AuthMiddleware) {
+	scoringGroup := rg.Group("/scoring").Use(m.AuthJwtTokenMiddleware(), m.RequirePermission("reports:read"))
+	{ //private routes
+		scoringGroup.GET("/rules", r.rulesHandler)
+		scoringGroup.GET("/at-risk", r.atRiskHandler)
+		scoringGroup.GET("/changes", r.changesHandler)
+	}
+
+	runGroup := rg.Group("/scoring").Use(m.AuthJwtTokenMiddleware(), m.RequirePermission("scoring:run"))
+	{ //private routes
+		runGroup.POST("/run", r.runHandler)
+	}
+}
@@ -0,0 +1,200 @@
+package scoringrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/pkg/db"
+	"gorm.io/gorm"
+)
+
+// runLockKey identifies the scoring run among postgres advisory locks
+const runLockKey = 7_301_001
+
+// jobTimeout bounds the queries of a run, they go over every client
+const jobTimeout = time.Minute
+
+const metricsQuery = `
+SELECT
+	u.user_id,
+	u.created_at AS member_since,
+	cp.scoring,
+	cp.category,
+	(SELECT COUNT(*) FROM checkins c
+		WHERE c.user_id = u.user_id AND c.checked_in_at >= @attendance_since) AS recent_visits,
+	(SELECT MAX(c.checked_in_at) FROM checkins c
+		WHERE c.user_id = u.user_id) AS last_visit_at,
+	COALESCE((SELECT SUM(GREATEST(0,
+			LEAST(s.end_date, COALESCE(s.cancelled_at::date, s.end_date), @today::date)
+			- GREATEST(s.start_date, @payment_since::date) + 1))
+		FROM client_subscriptions s
+		WHERE s.user_id = u.user_id), 0) AS covered_days
+FROM users u
+JOIN client_profiles cp ON cp.user_id = u.user_id AND cp.deleted_at IS NULL
+WHERE u.deleted_at IS NULL AND u.is_validated = TRUE`
+
+const atRiskQuery = `
+SELECT
+	u.user_id, u.first_name, u.last_name, u.email, u.phone, cp.scoring,
+	(SELECT MAX(c.checked_in_at) FROM checkins c
+		WHERE c.user_id = u.user_id) AS last_visit_at,
+	(SELECT MAX(h.changed_at) FROM client_category_history h
+		WHERE h.user_id = u.user_id AND h.new_category = 'AtRisk') AS at_risk_since
+FROM users u
+JOIN client_profiles cp ON cp.user_id = u.user_id AND cp.deleted_at IS NULL
+WHERE u.deleted_at IS NULL AND cp.category = 'AtRisk'
+ORDER BY last_visit_at ASC NULLS FIRST, cp.scoring ASC`
+
+type ScoringStore struct {
+	db *gorm.DB
+}
+
+func NewScoringStore(db *gorm.DB) *ScoringStore {
+	return &ScoringStore{db: db}
+}
+
+// ListMetrics gathers the attendance, tenure and payment facts of every validated client
+func (s *ScoringStore) ListMetrics(ctx context.Context, now time.Time, rules scoringdomain.Rules) ([]scoringdomain.ClientMetrics, error) {
+	var metrics []scoringdomain.ClientMetrics
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	today := now.Truncate(24 * time.Hour)
+	err := s.db.WithContext(ctx).
+		Raw(metricsQuery,
+			sql.Named("attendance_since", now.AddDate(0, 0, -rules.AttendanceWindowDays)),
+			sql.Named("today", today),
+			sql.Named("payment_since", today.AddDate(0, 0, -(rules.PaymentWindowDays-1))),
+		).
+		Scan(&metrics).Error
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// Exclusive runs fn while holding the session advisory lock of the scoring run on a connection of
+// its own, as the migrator does. It returns ErrRunInProgress when another process holds the lock.
+func (s *ScoringStore) Exclusive(ctx context.Context, fn func(ctx context.Context) error) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, runLockKey).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return scoringdomain.ErrRunInProgress
+	}
+	// unlock with a fresh context so a cancelled run still releases the lock
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, runLockKey)
+
+	return fn(ctx)
+}
+
+// LastRun returns the latest finished run
+func (s *ScoringStore) LastRun(ctx context.Context) (*scoringdomain.ScoringRuns, error) {
+	var run scoringdomain.ScoringRuns
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Order("started_at DESC").First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared_errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// Apply stores the new scores, records every category change and the run itself. Profiles
+// changed since their metrics were read are skipped, so overlapping runs never duplicate history rows.
+func (s *ScoringStore) Apply(ctx context.Context, run *scoringdomain.ScoringRuns, scores []scoringdomain.ClientScore) error {
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	return db.WithTX(s.db, func(tx *gorm.DB) error {
+		for _, score := range scores {
+			if !score.Changed() {
+				continue
+			}
+			result := tx.WithContext(ctx).
+				Model(&authdomain.ClientProfiles{}).
+				Where("user_id = ? AND scoring = ? AND category = ?", score.UserID, score.PreviousScoring, score.PreviousCategory).
+				Updates(map[string]interface{}{
+					"scoring":  score.Scoring,
+					"category": score.Category,
+				})
+			if result.Error != nil {
+				return result.Error //rollback
+			}
+			if result.RowsAffected == 0 || !score.CategoryChanged() {
+				continue
+			}
+			err := tx.WithContext(ctx).Create(&scoringdomain.ClientCategoryHistory{
+				UserID:           score.UserID,
+				PreviousCategory: score.PreviousCategory,
+				NewCategory:      score.Category,
+				PreviousScoring:  score.PreviousScoring,
+				NewScoring:       score.Scoring,
+			}).Error
+			if err != nil {
+				return err //rollback
+			}
+		}
+		if err := tx.WithContext(ctx).Create(run).Error; err != nil {
+			return err //rollback
+		}
+		return nil //commit
+	})
+}
+
+// ListChanges returns the category changes matching the filter, newest first
+func (s *ScoringStore) ListChanges(ctx context.Context, filter scoringdomain.CategoryChangeFilter) ([]scoringdomain.ClientCategoryHistory, error) {
+	var changes []scoringdomain.ClientCategoryHistory
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	query := s.db.WithContext(ctx)
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Category != "" {
+		query = query.Where("new_category = ?", filter.Category)
+	}
+	if filter.From != nil {
+		query = query.Where("changed_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("changed_at < ?", *filter.To)
+	}
+	if err := query.Order("changed_at DESC").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ListAtRisk returns the clients currently categorized as AtRisk, longest inactive first
+func (s *ScoringStore) ListAtRisk(ctx context.Context) ([]scoringdomain.AtRiskClient, error) {
+	var clients []scoringdomain.AtRiskClient
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Raw(atRiskQuery).Scan(&clients).Error; err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
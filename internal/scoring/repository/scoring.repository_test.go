@@ -0,0 +1,60 @@
+package scoringrepository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/internal/migrate/testdb/factory"
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+	scoringrepository "github.com/vitalfit/api/internal/scoring/repository"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+)
+
+func TestExclusive(t *testing.T) {
+	f := factory.New(t, testdb.New(t))
+	store := scoringrepository.NewScoringStore(f.DB)
+	other := scoringrepository.NewScoringStore(f.DB)
+	ctx := context.Background()
+
+	err := store.Exclusive(ctx, func(ctx context.Context) error {
+		// a second run, from this or another replica, is refused while the lock is held
+		if err := other.Exclusive(ctx, func(context.Context) error { return nil }); !errors.Is(err, scoringdomain.ErrRunInProgress) {
+			t.Errorf("Exclusive while held = %v, want ErrRunInProgress", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Exclusive: %v", err)
+	}
+
+	ran := false
+	if err := other.Exclusive(ctx, func(context.Context) error { ran = true; return nil }); err != nil || !ran {
+		t.Errorf("Exclusive after release = %v, ran %v, want the lock free", err, ran)
+	}
+}
+
+func TestApplyRecordsTheRun(t *testing.T) {
+	f := factory.New(t, testdb.New(t))
+	store := scoringrepository.NewScoringStore(f.DB)
+	ctx := context.Background()
+
+	if _, err := store.LastRun(ctx); !errors.Is(err, shared_errors.ErrNotFound) {
+		t.Fatalf("LastRun without runs = %v, want ErrNotFound", err)
+	}
+
+	started := time.Now().Add(-time.Minute).Truncate(time.Second)
+	run := &scoringdomain.ScoringRuns{StartedAt: started, Evaluated: 3}
+	if err := store.Apply(ctx, run, nil); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	last, err := store.LastRun(ctx)
+	if err != nil {
+		t.Fatalf("LastRun: %v", err)
+	}
+	if last.RunID != run.RunID || !last.StartedAt.Equal(started) || last.Evaluated != 3 {
+		t.Errorf("last run = %+v, want %+v", last, run)
+	}
+}
@@ -0,0 +1,101 @@
+package scoringservices
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	authdomain "github.com/vitalfit/api/internal/auth/domain"
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+	shared_errors "github.com/vitalfit/api/internal/shared/errors"
+	"github.com/vitalfit/api/internal/store"
+)
+
+type ScoringService struct {
+	store store.Storage
+}
+
+func NewScoringService(store store.Storage) *ScoringService {
+	return &ScoringService{store: store}
+}
+
+// Run recomputes the scoring and category of every client with the configured rules, one
+// process at a time
+func (s *ScoringService) Run(ctx context.Context) (*scoringdomain.RunSummary, error) {
+	var summary *scoringdomain.RunSummary
+	err := s.store.Scoring.Exclusive(ctx, func(ctx context.Context) error {
+		var err error
+		summary, err = s.run(ctx)
+		return err
+	})
+	return summary, err
+}
+
+// RunDue runs the scoring unless the last run started within the interval, so replicas starting
+// one after another do not repeat it. It returns ErrRunNotDue when it skips the run.
+func (s *ScoringService) RunDue(ctx context.Context, interval time.Duration) (*scoringdomain.RunSummary, error) {
+	var summary *scoringdomain.RunSummary
+	err := s.store.Scoring.Exclusive(ctx, func(ctx context.Context) error {
+		last, err := s.store.Scoring.LastRun(ctx)
+		if err != nil && !errors.Is(err, shared_errors.ErrNotFound) {
+			return err
+		}
+		if last != nil && !last.Due(time.Now(), interval) {
+			return scoringdomain.ErrRunNotDue
+		}
+		summary, err = s.run(ctx)
+		return err
+	})
+	return summary, err
+}
+
+func (s *ScoringService) run(ctx context.Context) (*scoringdomain.RunSummary, error) {
+	now := time.Now()
+	rules := s.Rules()
+
+	metrics, err := s.store.Scoring.ListMetrics(ctx, now, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &scoringdomain.RunSummary{
+		StartedAt:  now,
+		Evaluated:  len(metrics),
+		Categories: map[authdomain.ClientCategoryEnum]int{},
+	}
+	scores := make([]scoringdomain.ClientScore, 0, len(metrics))
+	for _, m := range metrics {
+		score := rules.Evaluate(m, now)
+		scores = append(scores, score)
+		summary.Categories[score.Category]++
+		if score.Changed() {
+			summary.Updated++
+		}
+		if score.CategoryChanged() {
+			summary.CategoryChanges++
+		}
+	}
+
+	run := &scoringdomain.ScoringRuns{
+		StartedAt:       now,
+		Evaluated:       summary.Evaluated,
+		Updated:         summary.Updated,
+		CategoryChanges: summary.CategoryChanges,
+	}
+	if err := s.store.Scoring.Apply(ctx, run, scores); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (s *ScoringService) Rules() scoringdomain.Rules {
+	return s.store.Config.Scoring.Rules
+}
+
+func (s *ScoringService) Changes(ctx context.Context, filter scoringdomain.CategoryChangeFilter) ([]scoringdomain.ClientCategoryHistory, error) {
+	return s.store.Scoring.ListChanges(ctx, filter)
+}
+
+func (s *ScoringService) AtRisk(ctx context.Context) ([]scoringdomain.AtRiskClient, error) {
+	return s.store.Scoring.ListAtRisk(ctx)
+}
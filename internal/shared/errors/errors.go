@@ -3,14 +3,30 @@ package errors
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 var (
-	ErrNotFound = errors.New("resource not found")
-	ErrConflict = errors.New("resource already exists")
+	ErrNotFound      = errors.New("resource not found")
+	ErrConflict      = errors.New("resource already exists")
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrTokenReused   = errors.New("token reuse detected")
+	ErrClientBlocked = errors.New("client is blocked")
+)
+
+const (
+	// CodeClientBlocked identifies the response sent to blocked clients
+	CodeClientBlocked = "CLIENT_BLOCKED"
+	// CodeMFAEnrollmentRequired is sent to staff whose role requires two-factor until they enroll
+	CodeMFAEnrollmentRequired = "MFA_ENROLLMENT_REQUIRED"
+	// CodeTooManyAttempts is sent while an identifier waits out the delay after repeated failures
+	CodeTooManyAttempts = "TOO_MANY_ATTEMPTS"
+	// CodeAccountLocked is sent while an identifier is locked after reaching the failure limit
+	CodeAccountLocked = "ACCOUNT_LOCKED"
 )
 
 type LogErrors struct {
@@ -34,6 +50,11 @@ func (l *LogErrors) BadRequestResponse(c *gin.Context, err error) {
 	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 }
 
+func (l *LogErrors) RequestTooLargeResponse(c *gin.Context, err error) {
+	l.logger.Warnw("request too large", "method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+}
+
 func (l *LogErrors) NotFoundResponse(c *gin.Context) {
 	l.logger.Warnw("not found error", "method", c.Request.Method, "path", c.Request.URL.Path)
 	c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
@@ -59,3 +80,25 @@ func (l *LogErrors) UnauthorizedErrorResponse(c *gin.Context, err error) {
 	l.logger.Errorw("unauthorized error", "method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
 	c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 }
+
+// ClientBlockedResponse is returned wherever a blocked client is refused, so apps can tell it apart from other 403s
+func (l *LogErrors) ClientBlockedResponse(c *gin.Context) {
+	l.logger.Warnw("client blocked", "method", c.Request.Method, "path", c.Request.URL.Path)
+	c.JSON(http.StatusForbidden, gin.H{"error": ErrClientBlocked.Error(), "code": CodeClientBlocked})
+}
+
+func (l *LogErrors) MFAEnrollmentRequiredResponse(c *gin.Context) {
+	l.logger.Warnw("mfa enrollment required", "method", c.Request.Method, "path", c.Request.URL.Path)
+	c.JSON(http.StatusForbidden, gin.H{"error": "two-factor authentication is mandatory for this role, enroll before continuing", "code": CodeMFAEnrollmentRequired})
+}
+
+func (l *LogErrors) TooManyAttemptsResponse(c *gin.Context, retryAfter time.Duration, locked bool) {
+	code, message := CodeTooManyAttempts, "too many failed attempts, try again later"
+	if locked {
+		code, message = CodeAccountLocked, "account temporarily locked after too many failed attempts"
+	}
+	l.logger.Warnw("too many failed attempts", "method", c.Request.Method, "path", c.Request.URL.Path, "client_ip", c.ClientIP(), "locked", locked)
+	seconds := int(retryAfter / time.Second)
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": message, "code": code, "retry_after_seconds": seconds})
+}
@@ -23,6 +23,16 @@ func NewAuthMiddleware(services appservices.Services) *AuthMiddleware {
 }
 
 func (j *AuthMiddleware) AuthJwtTokenMiddleware() gin.HandlerFunc {
+	return j.authenticate(true)
+}
+
+// AuthMFAEnrollmentMiddleware authenticates like AuthJwtTokenMiddleware but lets through staff
+// that still have to enroll in two-factor, it guards the routes they need to do so
+func (j *AuthMiddleware) AuthMFAEnrollmentMiddleware() gin.HandlerFunc {
+	return j.authenticate(false)
+}
+
+func (j *AuthMiddleware) authenticate(enforceMFA bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		authHeader := c.GetHeader("Authorization")
@@ -57,13 +67,38 @@ func (j *AuthMiddleware) AuthJwtTokenMiddleware() gin.HandlerFunc {
 
 		ctx := c.Request.Context()
 
+		sid, _ := claims["sid"].(string)
+		sessionID, err := uuid.Parse(sid)
+		if err != nil {
+			j.services.LogErrors.UnauthorizedErrorResponse(c, err)
+			c.Abort()
+			return
+		}
+		active, err := j.services.AuthServices.IsSessionActive(ctx, sessionID)
+		if err != nil {
+			j.services.LogErrors.InternalServerError(c, err)
+			c.Abort()
+			return
+		}
+		if !active {
+			j.services.LogErrors.UnauthorizedErrorResponse(c, fmt.Errorf("session has been revoked"))
+			c.Abort()
+			return
+		}
+
 		user, err := j.services.UserServices.GetByID(ctx, userID)
 		if err != nil {
 			j.services.LogErrors.UnauthorizedErrorResponse(c, err)
 			c.Abort()
 			return
 		}
+		if enforceMFA && !user.MFAEnabled && j.services.MFAServices.Required(user) {
+			j.services.LogErrors.MFAEnrollmentRequiredResponse(c)
+			c.Abort()
+			return
+		}
 		c.Set("user", user)
+		c.Set("session_id", sessionID)
 		c.Next()
 	}
 }
@@ -96,6 +131,46 @@ func (j *AuthMiddleware) CheckRoleAccess(requiredRole string) gin.HandlerFunc {
 	}
 }
 
+// permissionsKey caches the permissions of the user for the rest of the request
+const permissionsKey = "permissions"
+
+// RequirePermission checks the role of the user grants the permission, the permissions are resolved once per request
+func (j *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := j.services.UserServices.GetUserFromContext(c)
+		if user == nil {
+			j.services.LogErrors.UnauthorizedErrorResponse(c, fmt.Errorf("user not found in context for permission check"))
+			c.Abort()
+			return
+		}
+
+		permissions, err := j.permissions(c, user)
+		if err != nil {
+			j.services.LogErrors.InternalServerError(c, err)
+			c.Abort()
+			return
+		}
+		if !permissions.Has(permission) {
+			j.services.LogErrors.ForbiddenResponse(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (j *AuthMiddleware) permissions(c *gin.Context, user *authdomain.Users) (authdomain.PermissionSet, error) {
+	if cached, ok := c.Value(permissionsKey).(authdomain.PermissionSet); ok {
+		return cached, nil
+	}
+	permissions, err := j.services.PermissionServices.Resolve(c.Request.Context(), user)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(permissionsKey, permissions)
+	return permissions, nil
+}
+
 // compares users level with the level required
 func (j *AuthMiddleware) CheckRolePrecedence(ctx context.Context, user *authdomain.Users, roleName string) (bool, error) {
 	role, err := j.services.UserServices.GetRoleByName(ctx, roleName)
@@ -104,3 +179,69 @@ func (j *AuthMiddleware) CheckRolePrecedence(ctx context.Context, user *authdoma
 	}
 	return user.Role.Level >= role.Level, nil
 }
+
+// restricts branch scoped staff to the users of the branches they are assigned to
+func (j *AuthMiddleware) CheckUserAccess(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := j.services.UserServices.GetUserFromContext(c)
+		if user == nil {
+			j.services.LogErrors.UnauthorizedErrorResponse(c, fmt.Errorf("user not found in context for user check"))
+			c.Abort()
+			return
+		}
+
+		userID, err := uuid.Parse(c.Param(param))
+		if err != nil {
+			j.services.LogErrors.BadRequestResponse(c, err)
+			c.Abort()
+			return
+		}
+
+		allowed, err := j.services.BranchServices.CanAccessUser(c.Request.Context(), user, userID)
+		if err != nil {
+			j.services.LogErrors.InternalServerError(c, err)
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			j.services.LogErrors.ForbiddenResponse(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// restricts branch scoped staff to the branches they are assigned to
+func (j *AuthMiddleware) CheckBranchAccess(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := j.services.UserServices.GetUserFromContext(c)
+		if user == nil {
+			j.services.LogErrors.UnauthorizedErrorResponse(c, fmt.Errorf("user not found in context for branch check"))
+			c.Abort()
+			return
+		}
+
+		branchID, err := uuid.Parse(c.Param(param))
+		if err != nil {
+			j.services.LogErrors.BadRequestResponse(c, err)
+			c.Abort()
+			return
+		}
+
+		allowed, err := j.services.BranchServices.CanAccessBranch(c.Request.Context(), user, branchID)
+		if err != nil {
+			j.services.LogErrors.InternalServerError(c, err)
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			j.services.LogErrors.ForbiddenResponse(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
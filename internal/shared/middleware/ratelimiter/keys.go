@@ -0,0 +1,107 @@
+package ratelimiter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// maxKeyBody bounds how much of a body is read to find a key
+const maxKeyBody = 1 << 20
+
+// bodyContextKey caches the body read by ByJSONField for the other rules of the request
+const bodyContextKey = "ratelimit_body"
+
+// KeyFunc resolves what a request is counted on, false when the request does not carry it
+type KeyFunc func(c *gin.Context) (string, bool)
+
+// ByIP keys on the client IP, resolved through the trusted proxies only
+func ByIP(c *gin.Context) (string, bool) {
+	return "ip:" + c.ClientIP(), true
+}
+
+// ByJSONField keys on a string field of the JSON body, lowercased. The body is put back for the handler.
+// A body over maxKeyBody is refused with 413 and aborts the request, padding the body past the limit
+// must not be a way around the budget of the field.
+func ByJSONField(field string) KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		if c.Request.Body == nil {
+			return "", false
+		}
+		// reuse what an earlier rule of the same request already read
+		raw, ok := c.Get(bodyContextKey)
+		if !ok {
+			body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxKeyBody))
+			c.Request.Body.Close()
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+						"status":  "error",
+						"message": "Request body too large.",
+					})
+				}
+				return "", false
+			}
+			c.Set(bodyContextKey, body)
+			raw = body
+		}
+		body := raw.([]byte)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return "", false
+		}
+		var value string
+		if err := json.Unmarshal(fields[field], &value); err != nil {
+			return "", false
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			return "", false
+		}
+		return field + ":" + value, true
+	}
+}
+
+// BearerSubject keys on the subject of a valid bearer token. Only the signature is checked,
+// which is enough to stop clients from spending the budget of somebody else.
+func BearerSubject(validate func(token string) (*jwt.Token, error)) KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			return "", false
+		}
+		jwtToken, err := validate(token)
+		if err != nil {
+			return "", false
+		}
+		sub, err := jwtToken.Claims.GetSubject()
+		if err != nil || sub == "" {
+			return "", false
+		}
+		return "user:" + sub, true
+	}
+}
+
+// Join keys on all of the keys together, the request must carry every one
+func Join(keys ...KeyFunc) KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			part, ok := key(c)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, "|"), true
+	}
+}
@@ -0,0 +1,90 @@
+package ratelimiter_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	ratelimiterm "github.com/vitalfit/api/internal/shared/middleware/ratelimiter"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newContext(body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(body))
+	c.Request.RemoteAddr = "203.0.113.7:4321"
+	return c, w
+}
+
+func TestByJSONField(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantKey string
+		wantOK  bool
+	}{
+		{name: "field", body: `{"email":"ana@example.com"}`, wantKey: "email:ana@example.com", wantOK: true},
+		{name: "lowercased and trimmed", body: `{"email":"  Ana@Example.COM "}`, wantKey: "email:ana@example.com", wantOK: true},
+		{name: "missing field", body: `{"password":"secret"}`},
+		{name: "empty field", body: `{"email":" "}`},
+		{name: "not a string", body: `{"email":42}`},
+		{name: "not json", body: `email=ana@example.com`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newContext(tt.body)
+
+			key, ok := ratelimiterm.ByJSONField("email")(c)
+			if key != tt.wantKey || ok != tt.wantOK {
+				t.Errorf("ByJSONField = %q, %v, want %q, %v", key, ok, tt.wantKey, tt.wantOK)
+			}
+			// the handler still reads the whole body
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil || string(body) != tt.body {
+				t.Errorf("body left for the handler = %q, %v, want %q", body, err, tt.body)
+			}
+		})
+	}
+}
+
+func TestByJSONFieldReadsTheBodyOnce(t *testing.T) {
+	c, _ := newContext(`{"email":"ana@example.com","phone":"+584141234567"}`)
+
+	email, _ := ratelimiterm.ByJSONField("email")(c)
+	phone, _ := ratelimiterm.ByJSONField("phone")(c)
+	if email != "email:ana@example.com" || phone != "phone:+584141234567" {
+		t.Errorf("keys = %q, %q", email, phone)
+	}
+}
+
+func TestByJSONFieldRefusesLargeBodies(t *testing.T) {
+	body := `{"email":"ana@example.com","padding":"` + strings.Repeat("a", 1<<20) + `"}`
+	c, w := newContext(body)
+
+	if key, ok := ratelimiterm.ByJSONField("email")(c); ok {
+		t.Errorf("ByJSONField = %q, want no key", key)
+	}
+	if !c.IsAborted() || w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("aborted = %v, status = %d, want the request refused with 413", c.IsAborted(), w.Code)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	c, _ := newContext(`{"email":"ana@example.com"}`)
+	key, ok := ratelimiterm.Join(ratelimiterm.ByIP, ratelimiterm.ByJSONField("email"))(c)
+	if !ok || key != "ip:203.0.113.7|email:ana@example.com" {
+		t.Errorf("Join = %q, %v", key, ok)
+	}
+
+	c, _ = newContext(`{}`)
+	if key, ok := ratelimiterm.Join(ratelimiterm.ByIP, ratelimiterm.ByJSONField("email"))(c); ok {
+		t.Errorf("Join without email = %q, want no key", key)
+	}
+}
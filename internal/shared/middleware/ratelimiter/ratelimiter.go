@@ -1,7 +1,11 @@
 package ratelimiter
 
 import (
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,21 +13,53 @@ import (
 	"go.uber.org/zap"
 )
 
+// Rule counts requests under a policy, keyed on what Key resolves from the request
+type Rule struct {
+	Policy string
+	Key    KeyFunc
+}
+
+type route struct {
+	prefix string
+	rules  []Rule
+}
+
 type RateLimiterMiddleware struct {
-	rateLimiter ratelimiter.Limiter
-	cfg         ratelimiter.Config
-	logger      *zap.SugaredLogger
+	limiters *ratelimiter.Limiters
+	cfg      ratelimiter.Config
+	logger   *zap.SugaredLogger
+	exempt   []*net.IPNet
+	routes   []route
 }
 
-func NewRateLimiterMiddleware(rateLimiter ratelimiter.Limiter, cfg ratelimiter.Config, logger *zap.SugaredLogger) *RateLimiterMiddleware {
-	return &RateLimiterMiddleware{
-		rateLimiter: rateLimiter,
-		cfg:         cfg,
-		logger:      logger,
+func NewRateLimiterMiddleware(limiters *ratelimiter.Limiters, cfg ratelimiter.Config, logger *zap.SugaredLogger) *RateLimiterMiddleware {
+	r := &RateLimiterMiddleware{
+		limiters: limiters,
+		cfg:      cfg,
+		logger:   logger,
+	}
+	for _, entry := range cfg.Exempt {
+		network, err := parseNetwork(entry)
+		if err != nil {
+			logger.Errorw("invalid rate limiter exempt entry", "entry", entry, "error", err)
+			continue
+		}
+		r.exempt = append(r.exempt, network)
 	}
+	return r
 }
 
-func (r *RateLimiterMiddleware) RateLimiterMiddleware() gin.HandlerFunc {
+// Route attaches rules to the routes under the path prefix, they are checked before the
+// defaults of Limit and must all pass
+func (r *RateLimiterMiddleware) Route(prefix string, rules ...Rule) *RateLimiterMiddleware {
+	r.routes = append(r.routes, route{prefix: prefix, rules: rules})
+	return r
+}
+
+// Limit enforces the rules attached to the matched route and then the first of the defaults
+// whose key resolves, e.g. per user when the request carries a valid token and per IP otherwise.
+// The most restrictive budget is reported in the RateLimit-* headers.
+func (r *RateLimiterMiddleware) Limit(defaults ...Rule) gin.HandlerFunc {
 	if !r.cfg.Enabled {
 		return func(c *gin.Context) {
 			c.Next()
@@ -32,18 +68,55 @@ func (r *RateLimiterMiddleware) RateLimiterMiddleware() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 		clientIP := c.ClientIP()
-		if allow, retryAfter := r.rateLimiter.Allow(clientIP); !allow {
+		if r.isExempt(clientIP) {
+			c.Next()
+			return
+		}
 
+		var keys []counted
+		for _, route := range r.routes {
+			if strings.HasPrefix(c.FullPath(), route.prefix) {
+				keys = append(keys, resolve(c, route.rules, false)...)
+			}
+		}
+		keys = append(keys, resolve(c, defaults, true)...)
+		// a key function refused the request, e.g. a body too large to read
+		if c.IsAborted() {
+			return
+		}
+
+		var tightest *applied
+		for _, k := range keys {
+			result, err := r.limiters.Allow(c.Request.Context(), k.policy, k.key)
+			if err != nil {
+				// a failing backend must not take the API down with it, the request goes through
+				r.logger.Errorw("rate limiter failed", "policy", k.policy, "client_ip", clientIP, "error", err)
+				continue
+			}
+			if tightest == nil || !result.Allowed || result.Remaining < tightest.result.Remaining {
+				tightest = &applied{policy: k.policy, result: result}
+			}
+			if !result.Allowed {
+				break
+			}
+		}
+		if tightest == nil {
+			c.Next()
+			return
+		}
+
+		r.setHeaders(c, tightest)
+		if !tightest.result.Allowed {
 			r.logger.Warnw("rate limit exceeded",
+				"policy", tightest.policy,
 				"client_ip", clientIP,
 				"method", c.Request.Method,
 				"path", c.Request.URL.Path,
-				"retry_after_duration", retryAfter.String(),
+				"retry_after_duration", tightest.result.RetryAfter.String(),
 			)
 
-			retryAfterSeconds := int(retryAfter / time.Second)
-
-			c.Header("Retry-After", retryAfter.String())
+			retryAfterSeconds := ceilSeconds(tightest.result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfterSeconds))
 
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"status":              "error",
@@ -56,3 +129,73 @@ func (r *RateLimiterMiddleware) RateLimiterMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+type counted struct {
+	policy string
+	key    string
+}
+
+type applied struct {
+	policy string
+	result ratelimiter.Result
+}
+
+// resolve returns the keys of the rules the request carries, only the first one when first is set
+func resolve(c *gin.Context, rules []Rule, first bool) []counted {
+	var keys []counted
+	for _, rule := range rules {
+		key, ok := rule.Key(c)
+		if !ok {
+			continue
+		}
+		keys = append(keys, counted{policy: rule.Policy, key: key})
+		if first {
+			break
+		}
+	}
+	return keys
+}
+
+// setHeaders writes the RateLimit fields of the IETF draft, the policy is "<limit>;w=<window seconds>"
+func (r *RateLimiterMiddleware) setHeaders(c *gin.Context, a *applied) {
+	policy, _ := r.limiters.Policy(a.policy)
+	c.Header("RateLimit-Limit", strconv.Itoa(policy.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(a.result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(a.result.Reset)))
+	c.Header("RateLimit-Policy", strconv.Itoa(policy.Limit)+";w="+strconv.Itoa(ceilSeconds(policy.Window)))
+}
+
+func (r *RateLimiterMiddleware) isExempt(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range r.exempt {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetwork accepts a CIDR or a single IP
+func parseNetwork(entry string) (*net.IPNet, error) {
+	if !strings.Contains(entry, "/") {
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, &net.ParseError{Type: "IP address", Text: entry}
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, network, err := net.ParseCIDR(entry)
+	return network, err
+}
+
+// ceilSeconds rounds up so clients never retry early, token bucket waits are often under a second
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
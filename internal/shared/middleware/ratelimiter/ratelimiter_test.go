@@ -0,0 +1,132 @@
+package ratelimiter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	ratelimiterm "github.com/vitalfit/api/internal/shared/middleware/ratelimiter"
+	"github.com/vitalfit/api/pkg/ratelimiter"
+	"go.uber.org/zap"
+)
+
+// newRouter limits /v1/auth/login by email with a budget tighter than the IP default
+func newRouter(t *testing.T, cfg ratelimiter.Config) *gin.Engine {
+	t.Helper()
+	cfg.Policies = map[string]ratelimiter.Policy{
+		ratelimiter.PolicyIP:    {Limit: 10, Window: time.Minute},
+		ratelimiter.PolicyLogin: {Limit: 2, Window: time.Minute},
+	}
+	limiters, err := ratelimiter.New(cfg, nil)
+	if err != nil {
+		t.Fatalf("ratelimiter.New: %v", err)
+	}
+	rate := ratelimiterm.NewRateLimiterMiddleware(limiters, cfg, zap.NewNop().Sugar()).
+		Route("/v1/auth/login", ratelimiterm.Rule{Policy: ratelimiter.PolicyLogin, Key: ratelimiterm.ByJSONField("email")})
+
+	r := gin.New()
+	api := r.Group("/v1", rate.Limit(ratelimiterm.Rule{Policy: ratelimiter.PolicyIP, Key: ratelimiterm.ByIP}))
+	api.POST("/auth/login", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	api.GET("/branches", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func serve(r *gin.Engine, method, path, body, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = ip + ":4321"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLimitReportsTheTightestPolicy(t *testing.T) {
+	r := newRouter(t, ratelimiter.Config{Enabled: true})
+	const login = `{"email":"ana@example.com"}`
+
+	w := serve(r, http.MethodPost, "/v1/auth/login", login, "203.0.113.7")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	want := map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "1",
+		"RateLimit-Reset":     "30",
+		"RateLimit-Policy":    "2;w=60",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+
+	// routes without rules report the default
+	w = serve(r, http.MethodGet, "/v1/branches", "", "203.0.113.7")
+	if got := w.Header().Get("RateLimit-Policy"); got != "10;w=60" {
+		t.Errorf("RateLimit-Policy = %q, want the ip policy", got)
+	}
+}
+
+func TestLimitDenies(t *testing.T) {
+	r := newRouter(t, ratelimiter.Config{Enabled: true})
+	const login = `{"email":"ana@example.com"}`
+
+	for i := 0; i < 2; i++ {
+		if w := serve(r, http.MethodPost, "/v1/auth/login", login, "203.0.113.7"); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d, want 204", i, w.Code)
+		}
+	}
+	w := serve(r, http.MethodPost, "/v1/auth/login", login, "203.0.113.8")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429 for the email from any address", w.Code)
+	}
+	// the next token is 30s away, rounded up to whole seconds
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+	if got := w.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+
+	// another email is not affected
+	if w := serve(r, http.MethodPost, "/v1/auth/login", `{"email":"luis@example.com"}`, "203.0.113.8"); w.Code != http.StatusNoContent {
+		t.Errorf("other email: status = %d, want 204", w.Code)
+	}
+}
+
+func TestLimitRefusesLargeBodies(t *testing.T) {
+	r := newRouter(t, ratelimiter.Config{Enabled: true})
+	body := `{"email":"ana@example.com","padding":"` + strings.Repeat("a", 1<<20) + `"}`
+
+	if w := serve(r, http.MethodPost, "/v1/auth/login", body, "203.0.113.7"); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", w.Code)
+	}
+}
+
+func TestLimitSkips(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ratelimiter.Config
+		ip   string
+	}{
+		{name: "disabled", cfg: ratelimiter.Config{Enabled: false}, ip: "203.0.113.7"},
+		{name: "exempt ip", cfg: ratelimiter.Config{Enabled: true, Exempt: []string{"203.0.113.7"}}, ip: "203.0.113.7"},
+		{name: "exempt network", cfg: ratelimiter.Config{Enabled: true, Exempt: []string{"10.0.0.0/8"}}, ip: "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRouter(t, tt.cfg)
+			for i := 0; i < 5; i++ {
+				w := serve(r, http.MethodPost, "/v1/auth/login", `{"email":"ana@example.com"}`, tt.ip)
+				if w.Code != http.StatusNoContent {
+					t.Fatalf("request %d: status = %d, want 204", i, w.Code)
+				}
+				if got := w.Header().Get("RateLimit-Limit"); got != "" {
+					t.Errorf("RateLimit-Limit = %q, want no headers", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package requestid
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// Header carries the request id in both directions
+	Header = "X-Request-ID"
+	// ContextKey holds the request id in the gin context
+	ContextKey = "request_id"
+)
+
+// validID bounds the ids accepted from callers so they are safe to store and log
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestIDMiddleware keeps the request id sent by the caller or generates one,
+// and echoes it in the response
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !validID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set(ContextKey, id)
+		c.Header(Header, id)
+		c.Next()
+	}
+}
+
+// Get returns the request id of the request, or an empty string outside the middleware
+func Get(c *gin.Context) string {
+	return c.GetString(ContextKey)
+}
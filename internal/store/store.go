@@ -2,26 +2,86 @@ package store
 
 import (
 	"github.com/vitalfit/api/config"
+	auditdomain "github.com/vitalfit/api/internal/audit/domain"
+	auditrepository "github.com/vitalfit/api/internal/audit/repository"
 	authdomain "github.com/vitalfit/api/internal/auth/domain"
 	authrepository "github.com/vitalfit/api/internal/auth/repository"
+	branchdomain "github.com/vitalfit/api/internal/branches/domain"
+	branchrepository "github.com/vitalfit/api/internal/branches/repository"
+	checkindomain "github.com/vitalfit/api/internal/checkins/domain"
+	checkinrepository "github.com/vitalfit/api/internal/checkins/repository"
+	classdomain "github.com/vitalfit/api/internal/classes/domain"
+	classrepository "github.com/vitalfit/api/internal/classes/repository"
+	membershipdomain "github.com/vitalfit/api/internal/membership/domain"
+	membershiprepository "github.com/vitalfit/api/internal/membership/repository"
+	outboxdomain "github.com/vitalfit/api/internal/outbox/domain"
+	outboxrepository "github.com/vitalfit/api/internal/outbox/repository"
+	scoringdomain "github.com/vitalfit/api/internal/scoring/domain"
+	scoringrepository "github.com/vitalfit/api/internal/scoring/repository"
 	"github.com/vitalfit/api/pkg/mailer"
+	"github.com/vitalfit/api/pkg/notifier"
+	"github.com/vitalfit/api/pkg/qrcode"
+	"github.com/vitalfit/api/pkg/secretbox"
+	"github.com/vitalfit/api/pkg/storage"
 	"gorm.io/gorm"
 )
 
 type Storage struct {
-	Users authdomain.UserRepository
-	Roles authdomain.RolesRepository
+	Users         authdomain.UserRepository
+	Roles         authdomain.RolesRepository
+	Permissions   authdomain.PermissionRepository
+	RefreshTokens authdomain.RefreshTokenRepository
+	MFA           authdomain.MFARepository
+	Lockouts      authdomain.LockoutRepository
+	Instructors   authdomain.InstructorRepository
+	Branches      branchdomain.BranchRepository
+	Plans         membershipdomain.PlanRepository
+	Subscriptions membershipdomain.SubscriptionRepository
+	Checkins      checkindomain.CheckinRepository
+	ClassTypes    classdomain.ClassTypeRepository
+	Rooms         classdomain.RoomRepository
+	Schedules     classdomain.ScheduleRepository
+	Sessions      classdomain.SessionRepository
+	Bookings      classdomain.BookingRepository
+	Scoring       scoringdomain.ScoringRepository
+	Audit         auditdomain.AuditRepository
+	Outbox        outboxdomain.OutboxRepository
 	config.Config
-	Mailer mailer.Client
-	Auth   authdomain.Authenticator
+	Notifier      *notifier.Notifier
+	MailTemplates *mailer.Templates
+	Auth          authdomain.Authenticator
+	QRCodes       *qrcode.Signer
+	Files         storage.Storage
+	Secrets       *secretbox.Box
 }
 
-func NewStorage(db *gorm.DB, cfg config.Config, mailer mailer.Client, Auth authdomain.Authenticator) Storage {
+func NewStorage(db *gorm.DB, cfg config.Config, notifier *notifier.Notifier, templates *mailer.Templates, Auth authdomain.Authenticator, files storage.Storage, secrets *secretbox.Box) Storage {
 	return Storage{
-		Users:  authrepository.NewUserRepositoryDAO(db),
-		Roles:  authrepository.NewRoleStore(db),
-		Config: cfg,
-		Mailer: mailer,
-		Auth:   Auth,
+		Users:         authrepository.NewUserRepositoryDAO(db),
+		Roles:         authrepository.NewRoleStore(db),
+		Permissions:   authrepository.NewPermissionStore(db),
+		RefreshTokens: authrepository.NewRefreshTokenStore(db),
+		MFA:           authrepository.NewMFAStore(db),
+		Lockouts:      authrepository.NewLockoutStore(db),
+		Instructors:   authrepository.NewInstructorStore(db),
+		Branches:      branchrepository.NewBranchStore(db),
+		Plans:         membershiprepository.NewPlanStore(db),
+		Subscriptions: membershiprepository.NewSubscriptionStore(db),
+		Checkins:      checkinrepository.NewCheckinStore(db),
+		ClassTypes:    classrepository.NewClassTypeStore(db),
+		Rooms:         classrepository.NewRoomStore(db),
+		Schedules:     classrepository.NewScheduleStore(db),
+		Sessions:      classrepository.NewSessionStore(db),
+		Bookings:      classrepository.NewBookingStore(db),
+		Scoring:       scoringrepository.NewScoringStore(db),
+		Audit:         auditrepository.NewAuditStore(db),
+		Outbox:        outboxrepository.NewOutboxStore(db),
+		Config:        cfg,
+		Notifier:      notifier,
+		MailTemplates: templates,
+		Auth:          Auth,
+		QRCodes:       qrcode.NewSigner(cfg.Auth.QR.Secret, cfg.Auth.QR.Exp),
+		Files:         files,
+		Secrets:       secrets,
 	}
 }
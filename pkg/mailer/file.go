@@ -0,0 +1,73 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+type FileConfig struct {
+	Dir string
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fileClient writes every email as a .eml file instead of sending it, for offline runs and tests
+type fileClient struct {
+	from      string
+	templates *Templates
+	dir       string
+}
+
+func NewFileClient(dir, from string, templates *Templates) (fileClient, error) {
+	if dir == "" {
+		return fileClient{}, errors.New("mail file dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fileClient{}, err
+	}
+
+	return fileClient{
+		from:      from,
+		templates: templates,
+		dir:       dir,
+	}, nil
+}
+
+func (f fileClient) Send(ctx context.Context, msg Message) (int, error) {
+	rendered, err := f.templates.Render(msg.Template, msg.Locale, msg.Data)
+	if err != nil {
+		return -1, err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml",
+		time.Now().UTC().Format("20060102T150405.000000000"),
+		unsafeFileChars.ReplaceAllString(msg.Email, "_"),
+	)
+
+	//write to a temp file and rename so readers never see a partial email
+	tmp, err := os.CreateTemp(f.dir, ".mail-*")
+	if err != nil {
+		return -1, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := newMIMEMessage(f.from, msg, rendered).WriteTo(tmp); err != nil {
+		tmp.Close()
+		return -1, err
+	}
+	if err := tmp.Close(); err != nil {
+		return -1, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return -1, err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(f.dir, name)); err != nil {
+		return -1, err
+	}
+	return 200, nil
+}
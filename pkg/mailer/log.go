@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+)
+
+// logClient prints the rendered email instead of sending it. Development only, the body
+// contains the codes sent to the user.
+type logClient struct {
+	logger    Logger
+	templates *Templates
+}
+
+func NewLogClient(logger Logger, templates *Templates) logClient {
+	return logClient{logger: logger, templates: templates}
+}
+
+func (l logClient) Send(ctx context.Context, msg Message) (int, error) {
+	if l.logger == nil {
+		return -1, errors.New("log mailer requires a logger")
+	}
+
+	rendered, err := l.templates.Render(msg.Template, msg.Locale, msg.Data)
+	if err != nil {
+		return -1, err
+	}
+
+	l.logger.Infow("email",
+		"to", msg.Email,
+		"template", msg.Template,
+		"locale", rendered.Locale,
+		"subject", rendered.Subject,
+		"idempotency_key", msg.IdempotencyKey,
+		"text", rendered.Text,
+	)
+	return 200, nil
+}
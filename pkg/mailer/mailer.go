@@ -1,17 +1,126 @@
 package mailer
 
-import "embed"
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	gomail "gopkg.in/mail.v2"
+)
 
 const (
-	FromName             = "GopherSocial"
-	maxRetries           = 3
-	UserWelcomeTemplate  = "user_invitation.tmpl"
-	UserResetPwsTemplate = "user_reset.tmpl"
+	UserWelcomeTemplate      = "user_invitation.tmpl"
+	UserResetPwsTemplate     = "user_reset.tmpl"
+	UserEmailChangeTemplate  = "user_email_change.tmpl"
+	UserEmailChangedTemplate = "user_email_changed.tmpl"
+)
+
+var (
+	ErrUnknownDriver = errors.New("unknown mail driver")
+	// ErrLogDriverInProduction is returned for the log driver in production, it writes the
+	// codes the emails carry to the logs
+	ErrLogDriverInProduction = errors.New("the log mail driver is not allowed in production")
 )
 
 //go:embed "templates"
 var FS embed.FS
 
+// Message is one email to render from a template and send
+type Message struct {
+	Template string
+	Username string
+	Email    string
+	Data     any
+	// Locale picks the template variant, the default locale is used when it has none
+	Locale string
+	// IdempotencyKey lets providers that support it drop a retried send they already delivered
+	IdempotencyKey string
+}
+
+// Client sends a single attempt, retries are left to the caller
 type Client interface {
-	Send(templateFile string, username, email string, data any, isSandbox bool) (int, error)
+	Send(ctx context.Context, message Message) (int, error)
+}
+
+// Logger is what the log driver writes to, *zap.SugaredLogger implements it
+type Logger interface {
+	Infow(msg string, keysAndValues ...interface{})
+}
+
+type Config struct {
+	// Driver is resend, smtp, file or log
+	Driver    string
+	FromEmail string
+	FromName  string
+	// DefaultLocale is used for recipients without a preferred language
+	DefaultLocale string
+	Brand         Branding
+	Resend        ResendConfig
+	SMTP          SMTPConfig
+	File          FileConfig
+	// Production refuses the log driver
+	Production bool
+}
+
+type ResendConfig struct {
+	ApiKey string
+}
+
+// New returns the client selected by cfg.Driver, Resend is the default
+func New(cfg Config, templates *Templates, logger Logger) (Client, error) {
+	if templates == nil {
+		return nil, errors.New("mail templates are required")
+	}
+	from := cfg.From()
+	switch cfg.Driver {
+	case "", "resend":
+		return NewResendClient(cfg.Resend.ApiKey, from, templates)
+	case "smtp":
+		return NewSMTPClient(cfg.SMTP, from, templates)
+	case "file":
+		return NewFileClient(cfg.File.Dir, from, templates)
+	case "log":
+		if cfg.Production {
+			return nil, ErrLogDriverInProduction
+		}
+		return NewLogClient(logger, templates), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, cfg.Driver)
+	}
+}
+
+// From is the sender address with the display name, FromEmail is kept as is when it already has one
+func (cfg Config) From() string {
+	if cfg.FromName == "" || strings.Contains(cfg.FromEmail, "<") {
+		return cfg.FromEmail
+	}
+	return (&mail.Address{Name: cfg.FromName, Address: cfg.FromEmail}).String()
+}
+
+// newMIMEMessage builds the message the smtp and file drivers write. The Message-ID is derived
+// from the idempotency key so a retried send can be recognized as the same email.
+func newMIMEMessage(from string, msg Message, rendered *Rendered) *gomail.Message {
+	message := gomail.NewMessage()
+	message.SetHeader("From", from)
+	message.SetHeader("To", msg.Email)
+	message.SetHeader("Subject", rendered.Subject)
+	message.SetHeader("Content-Language", rendered.Locale)
+	message.SetDateHeader("Date", time.Now())
+	if msg.IdempotencyKey != "" {
+		hash := sha256.Sum256([]byte(msg.IdempotencyKey))
+		domain := "localhost"
+		if at := strings.LastIndex(from, "@"); at >= 0 {
+			domain = strings.Trim(from[at+1:], "> ")
+		}
+		message.SetHeader("Message-ID", fmt.Sprintf("<%s@%s>", hex.EncodeToString(hash[:16]), domain))
+	}
+	message.SetBody("text/plain", rendered.Text)
+	message.AddAlternative("text/html", rendered.HTML)
+	return message
 }
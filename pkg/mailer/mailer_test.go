@@ -0,0 +1,24 @@
+package mailer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vitalfit/api/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+func TestNewRefusesTheLogDriverInProduction(t *testing.T) {
+	templates, err := mailer.NewTemplates(mailer.Branding{Name: "VitalFit"}, "es")
+	if err != nil {
+		t.Fatalf("NewTemplates: %v", err)
+	}
+	logger := zap.NewNop().Sugar()
+
+	if _, err := mailer.New(mailer.Config{Driver: "log", Production: true}, templates, logger); !errors.Is(err, mailer.ErrLogDriverInProduction) {
+		t.Errorf("New in production = %v, want ErrLogDriverInProduction", err)
+	}
+	if _, err := mailer.New(mailer.Config{Driver: "log"}, templates, logger); err != nil {
+		t.Errorf("New outside production = %v", err)
+	}
+}
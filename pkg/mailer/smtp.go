@@ -0,0 +1,74 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	gomail "gopkg.in/mail.v2"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// TLS is starttls (default), tls for implicit TLS on connect, or none
+	TLS string
+}
+
+type smtpClient struct {
+	from      string
+	templates *Templates
+	dialer    *gomail.Dialer
+}
+
+func NewSMTPClient(cfg SMTPConfig, from string, templates *Templates) (smtpClient, error) {
+	if cfg.Host == "" {
+		return smtpClient{}, errors.New("smtp host is required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+
+	// gomail only authenticates when a username is set
+	dialer := gomail.NewDialer(cfg.Host, cfg.Port, cfg.Username, cfg.Password)
+	dialer.Timeout = 10 * time.Second
+	dialer.TLSConfig = &tls.Config{ServerName: cfg.Host, MinVersion: tls.VersionTLS12}
+
+	switch cfg.TLS {
+	case "", "starttls":
+		dialer.StartTLSPolicy = gomail.MandatoryStartTLS
+	case "tls":
+		dialer.SSL = true
+	case "none":
+		dialer.StartTLSPolicy = gomail.NoStartTLS
+	default:
+		return smtpClient{}, fmt.Errorf("unknown smtp tls mode: %s", cfg.TLS)
+	}
+
+	return smtpClient{
+		from:      from,
+		templates: templates,
+		dialer:    dialer,
+	}, nil
+}
+
+func (s smtpClient) Send(ctx context.Context, msg Message) (int, error) {
+	rendered, err := s.templates.Render(msg.Template, msg.Locale, msg.Data)
+	if err != nil {
+		return -1, err
+	}
+
+	// gomail does not take a context, the dialer timeout bounds the connection instead
+	if err := ctx.Err(); err != nil {
+		return -1, err
+	}
+
+	if err := s.dialer.DialAndSend(newMIMEMessage(s.from, msg, rendered)); err != nil {
+		return -1, fmt.Errorf("failed to send email: %w", err)
+	}
+	return 200, nil
+}
@@ -0,0 +1,172 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+const layoutFile = "layout.tmpl"
+
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// Branding is exposed to every template as .Brand
+type Branding struct {
+	Name         string
+	LogoURL      string
+	PrimaryColor string
+	SupportEmail string
+	WebsiteURL   string
+}
+
+// Rendered is a template executed for one locale, Text is the plain alternative of HTML
+type Rendered struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+	Locale  string `json:"locale"`
+}
+
+// view is what the templates see: the branding, the locale and the values of the message as .Data
+type view struct {
+	Brand  Branding
+	Locale string
+	Data   any
+}
+
+type templateSet struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// Templates is the registry of the embedded templates, parsed once at startup. Each locale
+// directory holds a layout plus one file per template defining subject, content and text.
+type Templates struct {
+	brand         Branding
+	defaultLocale string
+	sets          map[string]map[string]templateSet
+}
+
+func NewTemplates(brand Branding, defaultLocale string) (*Templates, error) {
+	locales, err := fs.ReadDir(FS, "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{
+		brand:         brand,
+		defaultLocale: defaultLocale,
+		sets:          map[string]map[string]templateSet{},
+	}
+	for _, locale := range locales {
+		if !locale.IsDir() {
+			continue
+		}
+		dir := path.Join("templates", locale.Name())
+		files, err := fs.Glob(FS, path.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+
+		t.sets[locale.Name()] = map[string]templateSet{}
+		for _, file := range files {
+			name := path.Base(file)
+			if name == layoutFile {
+				continue
+			}
+			// the layout goes first so the template can override its blocks
+			patterns := []string{path.Join(dir, layoutFile), file}
+			html, err := htmltemplate.ParseFS(FS, patterns...)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", file, err)
+			}
+			text, err := texttemplate.ParseFS(FS, patterns...)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", file, err)
+			}
+			t.sets[locale.Name()][name] = templateSet{html: html, text: text}
+		}
+	}
+
+	if _, ok := t.sets[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no templates for default locale %q", defaultLocale)
+	}
+	return t, nil
+}
+
+// Render executes the template in the closest available locale: the exact tag, then its
+// language ("en-US" falls back to "en"), then the default locale
+func (t *Templates) Render(name, locale string, data any) (*Rendered, error) {
+	locale = t.resolve(name, locale)
+	set, ok := t.sets[locale][name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	v := view{Brand: t.brand, Locale: locale, Data: data}
+	subject := new(bytes.Buffer)
+	if err := set.text.ExecuteTemplate(subject, "subject", v); err != nil {
+		return nil, err
+	}
+	html := new(bytes.Buffer)
+	if err := set.html.ExecuteTemplate(html, "html", v); err != nil {
+		return nil, err
+	}
+	text := new(bytes.Buffer)
+	if err := set.text.ExecuteTemplate(text, "text", v); err != nil {
+		return nil, err
+	}
+
+	return &Rendered{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		Locale:  locale,
+	}, nil
+}
+
+func (t *Templates) resolve(name, locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	candidates := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		candidates = append(candidates, locale[:i])
+	}
+	for _, candidate := range candidates {
+		if _, ok := t.sets[candidate][name]; ok {
+			return candidate
+		}
+	}
+	return t.defaultLocale
+}
+
+// Names lists the templates of the default locale, which every template must exist in
+func (t *Templates) Names() []string {
+	names := make([]string, 0, len(t.sets[t.defaultLocale]))
+	for name := range t.sets[t.defaultLocale] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Locales lists the locales that have templates
+func (t *Templates) Locales() []string {
+	locales := make([]string, 0, len(t.sets))
+	for locale := range t.sets {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Supports reports whether there are templates for the locale
+func (t *Templates) Supports(locale string) bool {
+	_, ok := t.sets[locale]
+	return ok
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+)
+
+// Sent is a message the fake provider accepted
+type Sent struct {
+	To   string
+	Body string
+}
+
+// FakeProvider keeps the messages in memory so tests can read the codes they carry.
+// Err, when set, is returned by every Send instead.
+type FakeProvider struct {
+	mu   sync.Mutex
+	sent []Sent
+	Err  error
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (f *FakeProvider) Send(ctx context.Context, to, body string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+	f.sent = append(f.sent, Sent{To: to, Body: body})
+	return nil
+}
+
+// Sent returns a copy of the messages accepted so far, oldest first
+func (f *FakeProvider) Sent() []Sent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Sent(nil), f.sent...)
+}
+
+// Last returns the latest message sent to the number
+func (f *FakeProvider) Last(to string) (Sent, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := len(f.sent) - 1; i >= 0; i-- {
+		if f.sent[i].To == to {
+			return f.sent[i], true
+		}
+	}
+	return Sent{}, false
+}
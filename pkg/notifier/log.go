@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+)
+
+// logProvider prints the message instead of sending it. Development only, the body contains the code.
+type logProvider struct {
+	logger  Logger
+	channel Channel
+}
+
+func NewLogProvider(logger Logger, channel Channel) logProvider {
+	return logProvider{logger: logger, channel: channel}
+}
+
+func (l logProvider) Send(ctx context.Context, to, body string) error {
+	if l.logger == nil {
+		return errors.New("log provider requires a logger")
+	}
+	l.logger.Infow("notification", "channel", l.channel, "to", to, "body", body)
+	return nil
+}
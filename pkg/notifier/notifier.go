@@ -0,0 +1,180 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vitalfit/api/pkg/mailer"
+)
+
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelSMS      Channel = "sms"
+	ChannelWhatsApp Channel = "whatsapp"
+)
+
+var (
+	ErrUnknownDriver      = errors.New("unknown notifier driver")
+	ErrChannelUnavailable = errors.New("notification channel is not available")
+	ErrInvalidPhone       = errors.New("phone must be in international format, e.g. +584121234567")
+	// ErrLogDriverInProduction is returned for the log driver in production, it writes the
+	// codes the messages carry to the logs
+	ErrLogDriverInProduction = errors.New("the log notifier driver is not allowed in production")
+)
+
+// e164 is the format SMS and WhatsApp providers take phone numbers in
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// Notification is one message to render from a template and deliver through a channel.
+// To is an email address for the email channel and a phone number for the others.
+type Notification struct {
+	Channel  Channel
+	Template string
+	Locale   string
+	Name     string
+	To       string
+	Data     any
+	// IdempotencyKey lets providers that support it drop a retried send they already delivered
+	IdempotencyKey string
+}
+
+// Provider delivers a rendered text message to a phone number
+type Provider interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// Logger is what the log provider writes to, *zap.SugaredLogger implements it
+type Logger interface {
+	Infow(msg string, keysAndValues ...interface{})
+}
+
+type Config struct {
+	// SMS and WhatsApp drivers are twilio, log or fake, empty leaves the channel off
+	SMS       ProviderConfig
+	WhatsApp  ProviderConfig
+	BrandName string
+	// DefaultLocale is used for recipients without a preferred language
+	DefaultLocale string
+	// Production refuses the log driver
+	Production bool
+}
+
+type ProviderConfig struct {
+	Driver string
+	Twilio TwilioConfig
+}
+
+// Notifier routes each notification to its channel: email goes through the mailer, SMS and
+// WhatsApp are rendered from short text templates and handed to their provider
+type Notifier struct {
+	mailer    mailer.Client
+	providers map[Channel]Provider
+	templates *Templates
+}
+
+func New(cfg Config, mail mailer.Client, logger Logger) (*Notifier, error) {
+	templates, err := NewTemplates(cfg.BrandName, cfg.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Notifier{
+		mailer:    mail,
+		providers: map[Channel]Provider{},
+		templates: templates,
+	}
+	for channel, provider := range map[Channel]ProviderConfig{ChannelSMS: cfg.SMS, ChannelWhatsApp: cfg.WhatsApp} {
+		p, err := newProvider(channel, provider, cfg.Production, logger)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			n.providers[channel] = p
+		}
+	}
+	return n, nil
+}
+
+func newProvider(channel Channel, cfg ProviderConfig, production bool, logger Logger) (Provider, error) {
+	switch cfg.Driver {
+	case "":
+		return nil, nil
+	case "twilio":
+		return NewTwilioProvider(cfg.Twilio, channel == ChannelWhatsApp)
+	case "log":
+		if production {
+			return nil, ErrLogDriverInProduction
+		}
+		return NewLogProvider(logger, channel), nil
+	case "fake":
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, cfg.Driver)
+	}
+}
+
+// WithProvider sets the provider of a phone channel, tests use it to plug in a FakeProvider
+func (n *Notifier) WithProvider(channel Channel, provider Provider) *Notifier {
+	n.providers[channel] = provider
+	return n
+}
+
+// Supports reports whether notifications can be sent through the channel
+func (n *Notifier) Supports(channel Channel) bool {
+	if n == nil {
+		return false
+	}
+	if channel == ChannelEmail {
+		return n.mailer != nil
+	}
+	_, ok := n.providers[channel]
+	return ok
+}
+
+// Send delivers a single attempt, retries are left to the caller
+func (n *Notifier) Send(ctx context.Context, notification Notification) error {
+	if !n.Supports(notification.Channel) {
+		return fmt.Errorf("%w: %s", ErrChannelUnavailable, notification.Channel)
+	}
+
+	if notification.Channel == ChannelEmail {
+		_, err := n.mailer.Send(ctx, mailer.Message{
+			Template:       notification.Template,
+			Username:       notification.Name,
+			Email:          notification.To,
+			Data:           notification.Data,
+			Locale:         notification.Locale,
+			IdempotencyKey: notification.IdempotencyKey,
+		})
+		return err
+	}
+
+	body, err := n.templates.Render(notification.Template, notification.Locale, notification.Data)
+	if err != nil {
+		return err
+	}
+	return n.providers[notification.Channel].Send(ctx, notification.To, body)
+}
+
+// NormalizePhone strips the separators people type in phone numbers and checks the result is E.164
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, phone)
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + phone[2:]
+	}
+	if !e164.MatchString(phone) {
+		return "", ErrInvalidPhone
+	}
+	return phone, nil
+}
@@ -0,0 +1,108 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vitalfit/api/pkg/notifier"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+		err   error
+	}{
+		{phone: "+584121234567", want: "+584121234567"},
+		{phone: "+58 412-123.45.67", want: "+584121234567"},
+		{phone: "+1 (415) 555-0100", want: "+14155550100"},
+		{phone: "00584121234567", want: "+584121234567"},
+		{phone: "04121234567", err: notifier.ErrInvalidPhone},
+		{phone: "+0584121234567", err: notifier.ErrInvalidPhone},
+		{phone: "+58412", err: notifier.ErrInvalidPhone},
+		{phone: "+5841212345678901", err: notifier.ErrInvalidPhone},
+		{phone: "+58412abc4567", err: notifier.ErrInvalidPhone},
+		{phone: "", err: notifier.ErrInvalidPhone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			got, err := notifier.NormalizePhone(tt.phone)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Errorf("NormalizePhone(%q) = %q, %v, want %q, %v", tt.phone, got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	n, err := notifier.New(notifier.Config{BrandName: "VitalFit", DefaultLocale: "es"}, nil, nil)
+	if err != nil {
+		t.Fatalf("notifier.New: %v", err)
+	}
+	sms := notifier.NewFakeProvider()
+	n.WithProvider(notifier.ChannelSMS, sms)
+
+	err = n.Send(context.Background(), notifier.Notification{
+		Channel:  notifier.ChannelSMS,
+		Template: "user_reset.tmpl",
+		Locale:   "en",
+		To:       "+584121234567",
+		Data:     map[string]string{"CODE": "123456"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	sent, ok := sms.Last("+584121234567")
+	want := "VitalFit: your password reset code is 123456. If you did not request it, ignore this message."
+	if !ok || sent.Body != want {
+		t.Errorf("sent %q, want %q", sent.Body, want)
+	}
+
+	// channels without a provider or a mailer are refused
+	for _, channel := range []notifier.Channel{notifier.ChannelWhatsApp, notifier.ChannelEmail} {
+		if n.Supports(channel) {
+			t.Errorf("Supports(%s) = true, want false", channel)
+		}
+		err := n.Send(context.Background(), notifier.Notification{Channel: channel, Template: "user_reset.tmpl", To: "+584121234567"})
+		if !errors.Is(err, notifier.ErrChannelUnavailable) {
+			t.Errorf("Send through %s = %v, want ErrChannelUnavailable", channel, err)
+		}
+	}
+}
+
+func TestSendProviderError(t *testing.T) {
+	n, err := notifier.New(notifier.Config{BrandName: "VitalFit", DefaultLocale: "es"}, nil, nil)
+	if err != nil {
+		t.Fatalf("notifier.New: %v", err)
+	}
+	failing := errors.New("provider down")
+	n.WithProvider(notifier.ChannelWhatsApp, &notifier.FakeProvider{Err: failing})
+
+	err = n.Send(context.Background(), notifier.Notification{
+		Channel:  notifier.ChannelWhatsApp,
+		Template: "user_invitation.tmpl",
+		To:       "+584121234567",
+		Data:     map[string]string{"CODE": "123456"},
+	})
+	if !errors.Is(err, failing) {
+		t.Errorf("Send = %v, want the provider error", err)
+	}
+}
+
+func TestNewRefusesTheLogDriverInProduction(t *testing.T) {
+	cfg := notifier.Config{
+		WhatsApp:      notifier.ProviderConfig{Driver: "log"},
+		DefaultLocale: "es",
+		Production:    true,
+	}
+	if _, err := notifier.New(cfg, nil, nil); !errors.Is(err, notifier.ErrLogDriverInProduction) {
+		t.Errorf("New in production = %v, want ErrLogDriverInProduction", err)
+	}
+
+	cfg.Production = false
+	n, err := notifier.New(cfg, nil, nil)
+	if err != nil || !n.Supports(notifier.ChannelWhatsApp) {
+		t.Errorf("New outside production = %v, want the log provider", err)
+	}
+}
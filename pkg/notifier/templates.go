@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+var ErrUnknownTemplate = errors.New("unknown text template")
+
+//go:embed "templates"
+var FS embed.FS
+
+// view is what the text templates see: the brand name and the values of the notification as .Data
+type view struct {
+	Brand string
+	Data  any
+}
+
+// Templates holds the short text variants of the email templates sent by SMS and WhatsApp.
+// The files are named like the email template they stand for.
+type Templates struct {
+	brand         string
+	defaultLocale string
+	locales       map[string]*template.Template
+}
+
+func NewTemplates(brand, defaultLocale string) (*Templates, error) {
+	dirs, err := fs.ReadDir(FS, "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{
+		brand:         brand,
+		defaultLocale: defaultLocale,
+		locales:       map[string]*template.Template{},
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		tmpl, err := template.ParseFS(FS, path.Join("templates", dir.Name(), "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s text templates: %w", dir.Name(), err)
+		}
+		t.locales[dir.Name()] = tmpl
+	}
+
+	if _, ok := t.locales[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no text templates for default locale %q", defaultLocale)
+	}
+	return t, nil
+}
+
+// Render executes the template in the locale, its language or else the default locale
+func (t *Templates) Render(name, locale string, data any) (string, error) {
+	tmpl := t.lookup(name, locale)
+	if tmpl == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	body := new(bytes.Buffer)
+	if err := tmpl.Execute(body, view{Brand: t.brand, Data: data}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(body.String()), nil
+}
+
+func (t *Templates) lookup(name, locale string) *template.Template {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	candidates := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		candidates = append(candidates, locale[:i])
+	}
+	candidates = append(candidates, t.defaultLocale)
+	for _, candidate := range candidates {
+		if set, ok := t.locales[candidate]; ok {
+			if tmpl := set.Lookup(name); tmpl != nil {
+				return tmpl
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const twilioAPI = "https://api.twilio.com/2010-04-01"
+
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// From is the sender number, for WhatsApp it must be enabled as a WhatsApp sender
+	From string
+}
+
+// twilioProvider sends through the Messages API, which takes both SMS and WhatsApp numbers
+type twilioProvider struct {
+	cfg      TwilioConfig
+	whatsapp bool
+	client   *http.Client
+}
+
+func NewTwilioProvider(cfg TwilioConfig, whatsapp bool) (twilioProvider, error) {
+	if cfg.AccountSID == "" || cfg.AuthToken == "" || cfg.From == "" {
+		return twilioProvider{}, errors.New("twilio account sid, auth token and from number are required")
+	}
+
+	return twilioProvider{
+		cfg:      cfg,
+		whatsapp: whatsapp,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (t twilioProvider) Send(ctx context.Context, to, body string) error {
+	from := t.cfg.From
+	if t.whatsapp {
+		from, to = "whatsapp:"+from, "whatsapp:"+to
+	}
+	form := url.Values{
+		"From": {from},
+		"To":   {to},
+		"Body": {body},
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioAPI, url.PathEscape(t.cfg.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.cfg.AccountSID, t.cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("failed to send message: twilio %d: %s", apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("failed to send message: twilio status %d", resp.StatusCode)
+	}
+	return nil
+}
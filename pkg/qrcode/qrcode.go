@@ -0,0 +1,86 @@
+package qrcode
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// prefix versions the payload format, VF2 added the nonce
+const prefix = "VF2"
+
+// bodySize is the subject, the nonce and the expiration
+const bodySize = 16 + 8 + 8
+
+var (
+	ErrInvalidPayload = errors.New("invalid qr payload")
+	ErrExpiredPayload = errors.New("qr payload has expired")
+)
+
+// Signer issues short-lived HMAC-signed payloads that identify a client at the front desk
+type Signer struct {
+	key []byte
+	ttl time.Duration
+}
+
+func NewSigner(key string, ttl time.Duration) *Signer {
+	return &Signer{
+		key: []byte(key),
+		ttl: ttl,
+	}
+}
+
+// Sign returns a payload for the subject that is valid until the returned time. A random nonce
+// makes every payload unique, so one consumed payload cannot be issued again.
+func (s *Signer) Sign(subject uuid.UUID, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+
+	nonce := make([]byte, 8)
+	rand.Read(nonce) // never fails since Go 1.24
+
+	body := make([]byte, 0, bodySize)
+	body = append(body, subject[:]...)
+	body = append(body, nonce...)
+	body = binary.BigEndian.AppendUint64(body, uint64(expiresAt.Unix()))
+
+	encoded := base64.RawURLEncoding.EncodeToString(body)
+	return prefix + "." + encoded + "." + s.signature(encoded), expiresAt
+}
+
+// Verify checks the signature and expiration of the payload and returns its subject
+func (s *Signer) Verify(payload string, now time.Time) (uuid.UUID, error) {
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 || parts[0] != prefix {
+		return uuid.Nil, ErrInvalidPayload
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.signature(parts[1]))) {
+		return uuid.Nil, ErrInvalidPayload
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || len(body) != bodySize {
+		return uuid.Nil, ErrInvalidPayload
+	}
+	subject, err := uuid.FromBytes(body[:16])
+	if err != nil {
+		return uuid.Nil, ErrInvalidPayload
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(body[24:])), 0)
+	if now.After(expiresAt) {
+		return uuid.Nil, ErrExpiredPayload
+	}
+	return subject, nil
+}
+
+func (s *Signer) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(prefix + "." + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
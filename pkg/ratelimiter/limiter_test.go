@@ -0,0 +1,187 @@
+package ratelimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source the tests move forward by hand
+type clock struct {
+	t time.Time
+}
+
+func newClock() *clock {
+	return &clock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *clock) Now() time.Time { return c.t }
+
+func (c *clock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+type step struct {
+	advance       time.Duration
+	key           string
+	wantAllowed   bool
+	wantRemaining int
+	wantRetry     time.Duration
+	wantReset     time.Duration
+}
+
+func runSteps(t *testing.T, limiter Limiter, c *clock, steps []step) {
+	t.Helper()
+	for i, s := range steps {
+		c.Advance(s.advance)
+		key := s.key
+		if key == "" {
+			key = "a"
+		}
+		got, err := limiter.Allow(context.Background(), key)
+		if err != nil {
+			t.Fatalf("step %d: Allow: %v", i, err)
+		}
+		want := Result{Allowed: s.wantAllowed, Remaining: s.wantRemaining, RetryAfter: s.wantRetry, Reset: s.wantReset}
+		if got != want {
+			t.Errorf("step %d: Allow(%s) = %+v, want %+v", i, key, got, want)
+		}
+	}
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst then deny",
+			steps: []step{
+				{wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{wantAllowed: true, wantRemaining: 1, wantReset: 2 * time.Second},
+				{wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+				{wantAllowed: false, wantRemaining: 0, wantRetry: time.Second, wantReset: 3 * time.Second},
+			},
+		},
+		{
+			name: "retry after counts the partial refill",
+			steps: []step{
+				{wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{wantAllowed: true, wantRemaining: 1, wantReset: 2 * time.Second},
+				{wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+				{advance: 250 * time.Millisecond, wantAllowed: false, wantRetry: 750 * time.Millisecond, wantReset: 2750 * time.Millisecond},
+				{advance: 750 * time.Millisecond, wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+			},
+		},
+		{
+			name: "refill stops at the burst",
+			steps: []step{
+				{wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{advance: time.Hour, wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+			},
+		},
+		{
+			name: "keys have their own bucket",
+			steps: []step{
+				{wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{wantAllowed: true, wantRemaining: 1, wantReset: 2 * time.Second},
+				{wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+				{key: "b", wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClock()
+			limiter := NewTokenBucketLimiter(Policy{Limit: 1, Window: time.Second, Burst: 3})
+			limiter.now = c.Now
+			runSteps(t, limiter, c, tt.steps)
+		})
+	}
+}
+
+func TestTokenBucketLimiterSweep(t *testing.T) {
+	c := newClock()
+	limiter := NewTokenBucketLimiter(Policy{Limit: 1, Window: time.Second, Burst: 3})
+	limiter.now = c.Now
+	ctx := context.Background()
+
+	// full spends one token at the start, partial at 2.5s, by 3s only full has refilled
+	limiter.Allow(ctx, "full")
+	c.Advance(2500 * time.Millisecond)
+	limiter.Allow(ctx, "partial")
+	c.Advance(500 * time.Millisecond)
+
+	if err := limiter.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if _, ok := limiter.buckets["full"]; ok {
+		t.Error("full bucket was kept")
+	}
+	if _, ok := limiter.buckets["partial"]; !ok {
+		t.Error("partial bucket was dropped")
+	}
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "limit then deny until the oldest request leaves",
+			steps: []step{
+				{wantAllowed: true, wantRemaining: 1, wantReset: 10 * time.Second},
+				{advance: 4 * time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+				{advance: time.Second, wantAllowed: false, wantRemaining: 0, wantRetry: 5 * time.Second, wantReset: 9 * time.Second},
+			},
+		},
+		{
+			name: "requests are evicted once the window passes",
+			steps: []step{
+				{wantAllowed: true, wantRemaining: 1, wantReset: 10 * time.Second},
+				{advance: 4 * time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+				{advance: 6 * time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+				{advance: time.Second, wantAllowed: false, wantRemaining: 0, wantRetry: 3 * time.Second, wantReset: 9 * time.Second},
+			},
+		},
+		{
+			name: "denied requests are not recorded",
+			steps: []step{
+				{wantAllowed: true, wantRemaining: 1, wantReset: 10 * time.Second},
+				{wantAllowed: true, wantRemaining: 0, wantReset: 10 * time.Second},
+				{advance: 5 * time.Second, wantAllowed: false, wantRetry: 5 * time.Second, wantReset: 5 * time.Second},
+				{advance: 5 * time.Second, wantAllowed: true, wantRemaining: 1, wantReset: 10 * time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClock()
+			limiter := NewSlidingWindowLimiter(Policy{Limit: 2, Window: 10 * time.Second})
+			limiter.now = c.Now
+			runSteps(t, limiter, c, tt.steps)
+		})
+	}
+}
+
+func TestSlidingWindowLimiterSweep(t *testing.T) {
+	c := newClock()
+	limiter := NewSlidingWindowLimiter(Policy{Limit: 2, Window: 10 * time.Second})
+	limiter.now = c.Now
+	ctx := context.Background()
+
+	limiter.Allow(ctx, "idle")
+	limiter.Allow(ctx, "active")
+	c.Advance(6 * time.Second)
+	limiter.Allow(ctx, "active")
+	c.Advance(4 * time.Second)
+
+	if err := limiter.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if _, ok := limiter.logs["idle"]; ok {
+		t.Error("idle key was kept")
+	}
+	if got := len(limiter.logs["active"]); got != 1 {
+		t.Errorf("active key keeps %d requests, want the 1 still in the window", got)
+	}
+}
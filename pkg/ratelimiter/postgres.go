@@ -0,0 +1,79 @@
+package ratelimiter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// queryTimeout keeps a slow database from holding requests, the middleware lets them through on error
+const queryTimeout = time.Second * 1
+
+// A request moves the theoretical arrival time (tat) of the key one interval forward from now, or
+// from the current tat if it is ahead. It is allowed while tat stays within burst intervals of now.
+// This is a token bucket kept in a single timestamp, so one upsert both checks and spends.
+const allowQuery = `
+INSERT INTO rate_limits AS r (key, tat) VALUES ($1, now() + $2 * interval '1 microsecond')
+ON CONFLICT (key) DO UPDATE
+SET tat = GREATEST(r.tat, now()) + $2 * interval '1 microsecond'
+WHERE GREATEST(r.tat, now()) + $2 * interval '1 microsecond' <= now() + $3 * interval '1 microsecond'
+RETURNING (EXTRACT(EPOCH FROM (tat - now())) * 1000000)::bigint`
+
+// aheadQuery reads how far the tat of a key runs ahead of now, in microseconds
+const aheadQuery = `
+SELECT GREATEST(EXTRACT(EPOCH FROM (tat - now())) * 1000000, 0)::bigint
+FROM rate_limits WHERE key = $1`
+
+const sweepQuery = `DELETE FROM rate_limits WHERE tat < now()`
+
+// PostgresLimiter keeps the budgets in the rate_limits table, so every replica of the API
+// spends from the same one
+type PostgresLimiter struct {
+	db        *sql.DB
+	interval  int64 // microseconds between requests at the sustained rate
+	tolerance int64 // microseconds tat may run ahead of now, burst intervals
+}
+
+func NewPostgresLimiter(db *sql.DB, policy Policy) *PostgresLimiter {
+	interval := policy.Window.Microseconds() / int64(policy.Limit)
+	return &PostgresLimiter{
+		db:        db,
+		interval:  interval,
+		tolerance: interval * int64(policy.burst()),
+	}
+}
+
+func (rl *PostgresLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	// ahead is how far tat runs in front of now once the request is counted
+	var ahead int64
+	result := Result{Allowed: true}
+	err := rl.db.QueryRowContext(ctx, allowQuery, key, rl.interval, rl.tolerance).Scan(&ahead)
+	if errors.Is(err, sql.ErrNoRows) {
+		//the update was skipped, the key is over its budget
+		result.Allowed = false
+		err = rl.db.QueryRowContext(ctx, aheadQuery, key).Scan(&ahead)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(max(ahead+rl.interval-rl.tolerance, 0)) * time.Microsecond
+	}
+	result.Remaining = int(max(rl.tolerance-ahead, 0) / rl.interval)
+	result.Reset = time.Duration(ahead) * time.Microsecond
+	return result, nil
+}
+
+// Sweep deletes the keys whose tat is in the past, they have their whole burst back
+func (rl *PostgresLimiter) Sweep(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout*5)
+	defer cancel()
+
+	_, err := rl.db.ExecContext(ctx, sweepQuery)
+	return err
+}
@@ -0,0 +1,70 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vitalfit/api/internal/migrate/testdb"
+	"github.com/vitalfit/api/pkg/ratelimiter"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
+
+func TestPostgresLimiter(t *testing.T) {
+	db := testdb.New(t)
+	ctx := context.Background()
+	// one request every 30 seconds, two at once
+	limiter := ratelimiter.NewPostgresLimiter(db.SQL, ratelimiter.Policy{Limit: 2, Window: time.Minute})
+
+	for i, wantRemaining := range []int{1, 0} {
+		result, err := limiter.Allow(ctx, "a")
+		if err != nil {
+			t.Fatalf("Allow %d: %v", i, err)
+		}
+		if !result.Allowed || result.Remaining != wantRemaining || result.RetryAfter != 0 {
+			t.Errorf("Allow %d = %+v, want allowed with %d remaining", i, result, wantRemaining)
+		}
+	}
+
+	denied, err := limiter.Allow(ctx, "a")
+	if err != nil {
+		t.Fatalf("Allow over the budget: %v", err)
+	}
+	if denied.Allowed || denied.Remaining != 0 {
+		t.Errorf("Allow over the budget = %+v, want denied", denied)
+	}
+	// the first request frees its slot 30 seconds after it was made, minus the time the test took
+	if denied.RetryAfter <= 25*time.Second || denied.RetryAfter > 30*time.Second {
+		t.Errorf("RetryAfter = %v, want just under 30s", denied.RetryAfter)
+	}
+	if denied.Reset <= 55*time.Second || denied.Reset > time.Minute {
+		t.Errorf("Reset = %v, want just under 1m", denied.Reset)
+	}
+
+	other, err := limiter.Allow(ctx, "b")
+	if err != nil {
+		t.Fatalf("Allow other key: %v", err)
+	}
+	if !other.Allowed {
+		t.Errorf("Allow other key = %+v, want allowed", other)
+	}
+
+	// keys whose budget is back in full are swept, the active ones stay
+	if _, err := db.SQL.ExecContext(ctx, "INSERT INTO rate_limits (key, tat) VALUES ('idle', now() - interval '1 second')"); err != nil {
+		t.Fatalf("insert idle key: %v", err)
+	}
+	if err := limiter.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	var keys int
+	if err := db.SQL.QueryRowContext(ctx, "SELECT count(*) FROM rate_limits").Scan(&keys); err != nil {
+		t.Fatalf("count keys: %v", err)
+	}
+	if keys != 2 {
+		t.Errorf("keys after Sweep = %d, want 2", keys)
+	}
+}
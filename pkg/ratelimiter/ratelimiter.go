@@ -1,13 +1,139 @@
 package ratelimiter
 
-import "time"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
 
+var (
+	ErrUnknownBackend = errors.New("unknown rate limiter backend")
+	ErrUnknownPolicy  = errors.New("unknown rate limit policy")
+)
+
+// Names of the policies the API attaches to its routes
+const (
+	PolicyIP             = "ip"
+	PolicyUser           = "user"
+	PolicyLogin          = "login"
+	PolicyPasswordForgot = "password_forgot"
+)
+
+// Result is the outcome of spending one request of a budget
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next request is allowed, zero when this one was
+	RetryAfter time.Duration
+	// Reset is how long until the whole budget is back
+	Reset time.Duration
+}
+
+// Limiter spends one request of the budget of key
 type Limiter interface {
-	Allow(ip string) (bool, time.Duration)
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Sweeper is implemented by the limiters that keep per-key state, Sweep drops the keys that
+// went back to a full budget. It is meant to run periodically from a single goroutine.
+type Sweeper interface {
+	Sweep(ctx context.Context) error
+}
+
+// Policy allows Limit requests per Window, up to Burst of them at once
+type Policy struct {
+	Limit  int
+	Window time.Duration
+	// Burst is the size of the token bucket, Limit when zero
+	Burst int
+}
+
+func (p Policy) burst() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
 }
 
 type Config struct {
-	RequestsPerTimeFrame int
-	TimeFrame            time.Duration
-	Enabled              bool
+	Enabled bool
+	// Backend is token_bucket (default), sliding_window or postgres, the last one is shared by every replica
+	Backend       string
+	Policies      map[string]Policy
+	SweepInterval time.Duration
+	// TrustedProxies are the addresses allowed to set X-Forwarded-For, none when empty so clients cannot pick their IP
+	TrustedProxies []string
+	// Exempt are the IPs and CIDRs never limited, such as internal health checks
+	Exempt []string
+}
+
+// Limiters holds one limiter per policy, built on the configured backend
+type Limiters struct {
+	policies map[string]Policy
+	limiters map[string]Limiter
+}
+
+// New builds the limiters of every policy, db is only used by the postgres backend
+func New(cfg Config, db *sql.DB) (*Limiters, error) {
+	l := &Limiters{
+		policies: map[string]Policy{},
+		limiters: map[string]Limiter{},
+	}
+	for name, policy := range cfg.Policies {
+		if policy.Limit <= 0 || policy.Window <= 0 {
+			return nil, fmt.Errorf("rate limit policy %s needs a positive limit and window", name)
+		}
+		limiter, err := newLimiter(cfg.Backend, policy, db)
+		if err != nil {
+			return nil, err
+		}
+		l.policies[name] = policy
+		l.limiters[name] = limiter
+	}
+	return l, nil
+}
+
+func newLimiter(backend string, policy Policy, db *sql.DB) (Limiter, error) {
+	switch backend {
+	case "", "token_bucket":
+		return NewTokenBucketLimiter(policy), nil
+	case "sliding_window":
+		return NewSlidingWindowLimiter(policy), nil
+	case "postgres":
+		if db == nil {
+			return nil, errors.New("postgres rate limiter requires a database")
+		}
+		return NewPostgresLimiter(db, policy), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backend)
+	}
+}
+
+// Allow spends one request of key under the policy. Keys are namespaced by policy so the
+// shared postgres table keeps them apart.
+func (l *Limiters) Allow(ctx context.Context, policy, key string) (Result, error) {
+	limiter, ok := l.limiters[policy]
+	if !ok {
+		return Result{}, fmt.Errorf("%w: %s", ErrUnknownPolicy, policy)
+	}
+	return limiter.Allow(ctx, policy+":"+key)
+}
+
+// Policy returns the settings of a policy
+func (l *Limiters) Policy(name string) (Policy, bool) {
+	policy, ok := l.policies[name]
+	return policy, ok
+}
+
+// Sweep sweeps the limiters that keep per-key state
+func (l *Limiters) Sweep(ctx context.Context) error {
+	var errs []error
+	for _, limiter := range l.limiters {
+		if sweeper, ok := limiter.(Sweeper); ok {
+			errs = append(errs, sweeper.Sweep(ctx))
+		}
+	}
+	return errors.Join(errs...)
 }
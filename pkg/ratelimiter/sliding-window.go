@@ -0,0 +1,73 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SlidingWindowLimiter keeps the time of every allowed request of a key within the last window.
+// It is exact, at the cost of up to limit timestamps per active key.
+type SlidingWindowLimiter struct {
+	mu     sync.Mutex
+	logs   map[string][]time.Time
+	limit  int
+	window time.Duration
+	now    func() time.Time
+}
+
+func NewSlidingWindowLimiter(policy Policy) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{
+		logs:   make(map[string][]time.Time),
+		limit:  policy.Limit,
+		window: policy.Window,
+		now:    time.Now,
+	}
+}
+
+func (rl *SlidingWindowLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := rl.now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	log := rl.expire(rl.logs[key], now)
+	result := Result{Allowed: len(log) < rl.limit}
+	if result.Allowed {
+		log = append(log, now)
+	} else {
+		// the oldest request leaving the window frees the next slot
+		result.RetryAfter = log[0].Add(rl.window).Sub(now)
+	}
+	rl.logs[key] = log
+	result.Remaining = rl.limit - len(log)
+	result.Reset = log[len(log)-1].Add(rl.window).Sub(now)
+	return result, nil
+}
+
+// Sweep drops the keys without requests in the last window
+func (rl *SlidingWindowLimiter) Sweep(ctx context.Context) error {
+	now := rl.now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	for key, log := range rl.logs {
+		if log = rl.expire(log, now); len(log) == 0 {
+			delete(rl.logs, key)
+		} else {
+			rl.logs[key] = log
+		}
+	}
+	return nil
+}
+
+// expire removes the timestamps that fell out of the window, the log is kept in order
+func (rl *SlidingWindowLimiter) expire(log []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-rl.window)
+	i := 0
+	for i < len(log) && !log[i].After(cutoff) {
+		i++
+	}
+	return log[i:]
+}
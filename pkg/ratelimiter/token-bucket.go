@@ -0,0 +1,75 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// TokenBucketLimiter refills each key at limit/window tokens per second up to burst, so the
+// budget is spread evenly instead of resetting at window edges
+type TokenBucketLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	rate    float64 // tokens per second
+	burst   float64
+	now     func() time.Time
+}
+
+func NewTokenBucketLimiter(policy Policy) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		buckets: make(map[string]*bucket),
+		rate:    float64(policy.Limit) / policy.Window.Seconds(),
+		burst:   float64(policy.burst()),
+		now:     time.Now,
+	}
+}
+
+func (rl *TokenBucketLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := rl.now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, exists := rl.buckets[key]
+	if !exists {
+		b = &bucket{tokens: rl.burst, last: now}
+		rl.buckets[key] = b
+	}
+	b.tokens = min(rl.burst, b.tokens+now.Sub(b.last).Seconds()*rl.rate)
+	b.last = now
+
+	result := Result{Allowed: b.tokens >= 1}
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = rl.seconds((1 - b.tokens) / rl.rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = rl.seconds((rl.burst - b.tokens) / rl.rate)
+	return result, nil
+}
+
+// Sweep drops the buckets that have refilled completely, they are the same as a missing one
+func (rl *TokenBucketLimiter) Sweep(ctx context.Context) error {
+	now := rl.now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	for key, b := range rl.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*rl.rate >= rl.burst {
+			delete(rl.buckets, key)
+		}
+	}
+	return nil
+}
+
+func (rl *TokenBucketLimiter) seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrUnknownDriver = errors.New("unknown storage driver")
+
+// Storage keeps uploaded files and returns the public URL they are served from
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Config struct {
+	Driver string
+	Local  LocalConfig
+}
+
+type LocalConfig struct {
+	Dir     string
+	BaseURL string
+}
+
+// New returns the storage selected by cfg.Driver, local disk is the default
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.Local.Dir, cfg.Local.BaseURL)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, cfg.Driver)
+	}
+}
@@ -0,0 +1,89 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitalfit/api/pkg/totp"
+)
+
+// rfcSecret is the SHA1 seed of RFC 6238 appendix B, "12345678901234567890" in base32
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// TestCodeRFC6238 checks the appendix B vectors, the RFC lists eight digits and the
+// six digit codes are their last six
+func TestCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		got, err := totp.Code(rfcSecret, totp.Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	upper, _ := totp.Code(secret, 1)
+	lower, err := totp.Code(strings.ToLower(secret), 1)
+	if err != nil || lower != upper {
+		t.Errorf("Code(lowercase) = %s, %v, want %s", lower, err, upper)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := totp.Step(now)
+	code := func(step int64) string {
+		c, err := totp.Code(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("Code(%d): %v", step, err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", code: code(current), wantStep: current, wantOK: true},
+		{name: "previous step within skew", code: code(current - 1), wantStep: current - 1, wantOK: true},
+		{name: "next step within skew", code: code(current + 1), wantStep: current + 1, wantOK: true},
+		{name: "too old", code: code(current - totp.Skew - 1)},
+		{name: "too far ahead", code: code(current + totp.Skew + 1)},
+		{name: "replayed code", code: code(current), lastStep: current},
+		{name: "code older than the last used one", code: code(current - 1), lastStep: current},
+		{name: "newer code after a used one", code: code(current + 1), lastStep: current, wantStep: current + 1, wantOK: true},
+		{name: "wrong length", code: code(current)[:5]},
+		{name: "wrong code", code: "000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := totp.Validate(rfcSecret, tt.code, now, tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}